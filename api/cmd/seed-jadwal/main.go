@@ -0,0 +1,133 @@
+// cmd/seed-jadwal/main.go
+//
+// Memuat jadwal imunisasi rutin program nasional ke master_imunisasi lalu menerbitkannya sebagai
+// versi jadwal baru. Tanpa -berlaku, versi berlaku mulai besok; versi pertama tetap dipakai
+// untuk kohort yang lahir sebelum tanggal tersebut. Contoh:
+//
+//	go run ./cmd/seed-jadwal -berlaku 2027-01-01
+//
+// -ganti hanya dapat mengganti versi yang belum mulai berlaku, sama seperti penghapusan versi lewat API.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/nadhifhafizp/api/db"
+	"github.com/nadhifhafizp/api/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// dosis menyusun satu baris jadwal; dosis dengan usia ideal di bawah 12 bulan dihitung dalam IDL
+func dosis(nama string, seri *string, dosisKe, usiaBulan int, minHari, maxHari, intervalHari *int) models.DosisJadwalPayload {
+	return models.DosisJadwalPayload{
+		NamaImunisasi:   nama,
+		Seri:            seri,
+		DosisKe:         ptr(dosisKe),
+		UsiaIdealBulan:  usiaBulan,
+		UsiaMinHari:     minHari,
+		UsiaMaxHari:     maxHari,
+		IntervalMinHari: intervalHari,
+		TermasukIdl:     usiaBulan < 12,
+	}
+}
+
+// jadwalNasional adalah jadwal imunisasi rutin bayi dan baduta program nasional
+// (termasuk PCV, rotavirus dan IPV dua dosis)
+func jadwalNasional() []models.DosisJadwalPayload {
+	polio, dpt, pcv, rota, ipv, mr := ptr("Polio"), ptr("DPT-HB-Hib"), ptr("PCV"), ptr("Rotavirus"), ptr("IPV"), ptr("MR")
+	// Batas umur program (inklusif, dalam hari): rotavirus dosis 1 sebelum umur 15 minggu dan
+	// dosis terakhir sebelum 8 bulan; DPT-HB-Hib dan PCV tidak lagi diberikan mulai umur 5 tahun
+	rotaDosis1, rotaTerakhir, bawah5Tahun := ptr(104), ptr(243), ptr(1825)
+	return []models.DosisJadwalPayload{
+		dosis("HB-0", nil, 1, 0, nil, ptr(7), nil),
+		dosis("BCG", nil, 1, 1, nil, ptr(365), nil),
+		dosis("Polio 1", polio, 1, 1, nil, nil, nil),
+		dosis("Polio 2", polio, 2, 2, nil, nil, ptr(28)),
+		dosis("Polio 3", polio, 3, 3, nil, nil, ptr(28)),
+		dosis("Polio 4", polio, 4, 4, nil, nil, ptr(28)),
+		dosis("DPT-HB-Hib 1", dpt, 1, 2, ptr(42), bawah5Tahun, nil),
+		dosis("DPT-HB-Hib 2", dpt, 2, 3, nil, bawah5Tahun, ptr(28)),
+		dosis("DPT-HB-Hib 3", dpt, 3, 4, nil, bawah5Tahun, ptr(28)),
+		dosis("PCV 1", pcv, 1, 2, ptr(42), bawah5Tahun, nil),
+		dosis("PCV 2", pcv, 2, 3, nil, bawah5Tahun, ptr(28)),
+		dosis("Rotavirus 1", rota, 1, 2, ptr(42), rotaDosis1, nil),
+		dosis("Rotavirus 2", rota, 2, 3, nil, rotaTerakhir, ptr(28)),
+		dosis("Rotavirus 3", rota, 3, 4, nil, rotaTerakhir, ptr(28)),
+		dosis("IPV 1", ipv, 1, 4, ptr(98), nil, nil),
+		dosis("IPV 2", ipv, 2, 9, nil, nil, ptr(28)),
+		dosis("MR 1", mr, 1, 9, ptr(270), nil, nil),
+		dosis("PCV 3", pcv, 3, 12, nil, bawah5Tahun, ptr(56)),
+		dosis("DPT-HB-Hib 4", dpt, 4, 18, nil, bawah5Tahun, ptr(180)),
+		dosis("MR 2", mr, 2, 18, nil, nil, ptr(180)),
+	}
+}
+
+func main() {
+	// Tanggal hari ini menurut zona waktu lokal, dalam UTC agar sebanding dengan hasil time.Parse
+	y, m, d := time.Now().Date()
+	hariIni := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	berlaku := flag.String("berlaku", hariIni.AddDate(0, 0, 1).Format("2006-01-02"), "Tanggal mulai berlaku (YYYY-MM-DD) untuk kohort kelahiran")
+	nama := flag.String("nama", "Program Imunisasi Nasional", "Nama versi jadwal")
+	ganti := flag.Bool("ganti", false, "Ganti versi yang belum mulai berlaku dengan tanggal berlaku yang sama")
+	flag.Parse()
+
+	berlakuMulai, err := time.Parse("2006-01-02", *berlaku)
+	if err != nil {
+		log.Fatalf("Format -berlaku salah (YYYY-MM-DD): %v", err)
+	}
+
+	dbpool := db.ConnectDB()
+	defer dbpool.Close()
+	ctx := context.Background()
+
+	tx, err := dbpool.Begin(ctx)
+	if err != nil {
+		log.Fatalf("Gagal memulai transaksi: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if *ganti {
+		var ada bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM versi_jadwal_imunisasi WHERE berlaku_mulai = $1)", berlakuMulai).Scan(&ada); err != nil {
+			log.Fatalf("Gagal memeriksa versi lama: %v", err)
+		}
+		if ada && !berlakuMulai.After(hariIni) {
+			log.Fatalf("Versi jadwal yang berlaku mulai %s sudah mulai berlaku dan tidak dapat diganti; terbitkan versi baru dengan tanggal berlaku berikutnya", *berlaku)
+		}
+		tag, err := tx.Exec(ctx, "DELETE FROM versi_jadwal_imunisasi WHERE berlaku_mulai = $1", berlakuMulai)
+		if err != nil {
+			log.Fatalf("Gagal menghapus versi lama: %v", err)
+		}
+		if tag.RowsAffected() > 0 {
+			log.Printf("Versi jadwal lama yang berlaku mulai %s diganti", *berlaku)
+		}
+	}
+
+	var idMaster []int
+	for _, d := range jadwalNasional() {
+		id, baru, err := db.SimpanDosisMaster(ctx, tx, d)
+		if err != nil {
+			log.Fatalf("Gagal menyimpan %s: %v", d.NamaImunisasi, err)
+		}
+		if baru {
+			log.Printf("Master imunisasi baru: %s (#%d)", d.NamaImunisasi, id)
+		} else {
+			log.Printf("Master imunisasi diperbarui: %s (#%d)", d.NamaImunisasi, id)
+		}
+		idMaster = append(idMaster, id)
+	}
+
+	keterangan := "Dimuat dari jadwal imunisasi rutin program nasional"
+	id, err := db.TerbitkanVersiJadwal(ctx, tx, *nama, berlakuMulai, &keterangan, nil, idMaster)
+	if err != nil {
+		log.Fatalf("Gagal menerbitkan versi jadwal (gunakan -ganti bila tanggal berlaku sudah dipakai): %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatalf("Gagal menyimpan transaksi: %v", err)
+	}
+	log.Printf("Versi jadwal #%d \"%s\" berlaku untuk anak yang lahir sejak %s (%d dosis)", id, *nama, *berlaku, len(idMaster))
+}
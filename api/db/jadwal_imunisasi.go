@@ -0,0 +1,60 @@
+// db/jadwal_imunisasi.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// SimpanDosisMaster mencocokkan satu dosis jadwal dengan master imunisasi (seri dan dosis ke-,
+// atau nama imunisasi tanpa membedakan huruf besar) lalu memperbarui parameter jadwalnya.
+// Dosis yang belum ada ditambahkan. Mengembalikan ID master dan apakah baris baru dibuat.
+func SimpanDosisMaster(ctx context.Context, tx pgx.Tx, d models.DosisJadwalPayload) (int, bool, error) {
+	dosisKe := 1
+	if d.DosisKe != nil {
+		dosisKe = *d.DosisKe
+	}
+
+	var id int
+	err := tx.QueryRow(ctx,
+		`SELECT id FROM master_imunisasi
+        WHERE ($1::varchar IS NOT NULL AND seri = $1 AND dosis_ke = $2) OR LOWER(nama_imunisasi) = LOWER($3)
+        ORDER BY (seri IS NOT DISTINCT FROM $1 AND dosis_ke = $2) DESC, id ASC
+        LIMIT 1`, d.Seri, dosisKe, d.NamaImunisasi).Scan(&id)
+	if err != nil && err != pgx.ErrNoRows {
+		return 0, false, err
+	}
+	if err == pgx.ErrNoRows {
+		err = tx.QueryRow(ctx,
+			`INSERT INTO master_imunisasi (nama_imunisasi, usia_ideal_bulan, seri, dosis_ke, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl, deskripsi)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			d.NamaImunisasi, d.UsiaIdealBulan, d.Seri, dosisKe, d.UsiaMinHari, d.UsiaMaxHari, d.IntervalMinHari, d.TermasukIdl, d.Deskripsi).Scan(&id)
+		return id, true, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE master_imunisasi SET usia_ideal_bulan = $1, seri = $2, dosis_ke = $3, usia_min_hari = $4, usia_max_hari = $5,
+            interval_min_hari = $6, termasuk_idl = $7, deskripsi = COALESCE($8, deskripsi), updated_at = NOW() WHERE id = $9`,
+		d.UsiaIdealBulan, d.Seri, dosisKe, d.UsiaMinHari, d.UsiaMaxHari, d.IntervalMinHari, d.TermasukIdl, d.Deskripsi, id)
+	return id, false, err
+}
+
+// TerbitkanVersiJadwal membekukan parameter jadwal master imunisasi menjadi versi baru yang berlaku
+// bagi anak yang lahir sejak berlakuMulai. idMaster membatasi dosis yang masuk versi; nil berarti seluruh master.
+func TerbitkanVersiJadwal(ctx context.Context, tx pgx.Tx, nama string, berlakuMulai time.Time, keterangan *string, idKader *int, idMaster []int) (int, error) {
+	var id int
+	err := tx.QueryRow(ctx,
+		`INSERT INTO versi_jadwal_imunisasi (nama, berlaku_mulai, keterangan, id_kader_penerbit) VALUES ($1, $2, $3, $4) RETURNING id`,
+		nama, berlakuMulai, keterangan, idKader).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO dosis_versi_jadwal_imunisasi (id_versi, id_master_imunisasi, usia_ideal_bulan, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl)
+        SELECT $1, id, usia_ideal_bulan, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl
+        FROM master_imunisasi WHERE $2::int[] IS NULL OR id = ANY($2)`, id, idMaster)
+	return id, err
+}
@@ -0,0 +1,8 @@
+-- 001_geolokasi_ibu.sql
+-- Koordinat rumah tangga (opsional) untuk pemetaan kasus gizi anak.
+ALTER TABLE ibu
+    ADD COLUMN latitude  DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT ibu_latitude_check  CHECK (latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT ibu_longitude_check CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT ibu_koordinat_check CHECK ((latitude IS NULL) = (longitude IS NULL));
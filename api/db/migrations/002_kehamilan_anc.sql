@@ -0,0 +1,39 @@
+-- 002_kehamilan_anc.sql
+-- Pencatatan ibu hamil dan kunjungan antenatal (ANC).
+CREATE TABLE kehamilan (
+    id                SERIAL PRIMARY KEY,
+    id_ibu            INT NOT NULL,
+    hpht              DATE NOT NULL,          -- Hari pertama haid terakhir
+    hpl               DATE NOT NULL,          -- Hari perkiraan lahir (Naegele: HPHT + 280 hari)
+    gravida           INT NOT NULL CHECK (gravida >= 1),
+    para              INT NOT NULL DEFAULT 0 CHECK (para >= 0),
+    abortus           INT NOT NULL DEFAULT 0 CHECK (abortus >= 0),
+    tinggi_badan_cm   NUMERIC(5,1),
+    status            VARCHAR(20) NOT NULL DEFAULT 'aktif' CHECK (status IN ('aktif', 'selesai')),
+    catatan           TEXT,
+    id_kader_pencatat INT,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ,
+    CONSTRAINT kehamilan_id_ibu_fkey FOREIGN KEY (id_ibu) REFERENCES ibu(id),
+    CONSTRAINT kehamilan_id_kader_pencatat_fkey FOREIGN KEY (id_kader_pencatat) REFERENCES kader(id)
+);
+
+CREATE TABLE kunjungan_anc (
+    id                      SERIAL PRIMARY KEY,
+    id_kehamilan            INT NOT NULL,
+    tanggal_kunjungan       DATE NOT NULL,
+    bb_kg                   NUMERIC(5,2),
+    lila_cm                 NUMERIC(4,1),
+    tekanan_darah_sistolik  INT,
+    tekanan_darah_diastolik INT,
+    tablet_fe               INT CHECK (tablet_fe >= 0), -- Jumlah tablet tambah darah yang diberikan
+    catatan                 TEXT,
+    id_kader_pencatat       INT,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ,
+    CONSTRAINT kunjungan_anc_id_kehamilan_fkey FOREIGN KEY (id_kehamilan) REFERENCES kehamilan(id),
+    CONSTRAINT kunjungan_anc_id_kader_pencatat_fkey FOREIGN KEY (id_kader_pencatat) REFERENCES kader(id)
+);
+
+CREATE INDEX kehamilan_id_ibu_idx ON kehamilan (id_ibu);
+CREATE INDEX kunjungan_anc_id_kehamilan_idx ON kunjungan_anc (id_kehamilan, tanggal_kunjungan);
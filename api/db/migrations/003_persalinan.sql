@@ -0,0 +1,22 @@
+-- 003_persalinan.sql
+-- Hasil persalinan dari kehamilan yang dipantau; bayi lahir hidup otomatis didaftarkan sebagai anak.
+CREATE TABLE persalinan (
+    id                    SERIAL PRIMARY KEY,
+    id_kehamilan          INT NOT NULL,
+    tanggal_persalinan    DATE NOT NULL,
+    tempat                VARCHAR(20) NOT NULL CHECK (tempat IN ('rumah_sakit', 'puskesmas', 'klinik', 'polindes', 'rumah', 'lainnya')),
+    penolong              VARCHAR(20) NOT NULL CHECK (penolong IN ('dokter', 'bidan', 'perawat', 'dukun', 'lainnya')),
+    usia_kehamilan_minggu INT NOT NULL CHECK (usia_kehamilan_minggu BETWEEN 0 AND 45),
+    hasil                 VARCHAR(20) NOT NULL CHECK (hasil IN ('lahir_hidup', 'lahir_mati', 'keguguran')),
+    jumlah_bayi           INT NOT NULL DEFAULT 1 CHECK (jumlah_bayi >= 0),
+    catatan               TEXT,
+    id_kader_pencatat     INT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT persalinan_id_kehamilan_key UNIQUE (id_kehamilan),
+    CONSTRAINT persalinan_id_kehamilan_fkey FOREIGN KEY (id_kehamilan) REFERENCES kehamilan(id),
+    CONSTRAINT persalinan_id_kader_pencatat_fkey FOREIGN KEY (id_kader_pencatat) REFERENCES kader(id)
+);
+
+ALTER TABLE anak
+    ADD COLUMN id_persalinan INT,
+    ADD CONSTRAINT anak_id_persalinan_fkey FOREIGN KEY (id_persalinan) REFERENCES persalinan(id);
@@ -0,0 +1,23 @@
+-- 004_status_anak.sql
+-- Siklus status anak (aktif, pindah, meninggal, lulus) beserta riwayat perubahannya.
+ALTER TABLE anak
+    ADD COLUMN status         VARCHAR(20) NOT NULL DEFAULT 'aktif',
+    ADD COLUMN status_tanggal DATE,
+    ADD COLUMN status_alasan  TEXT,
+    ADD CONSTRAINT anak_status_check CHECK (status IN ('aktif', 'pindah', 'meninggal', 'lulus'));
+
+CREATE TABLE riwayat_status_anak (
+    id              SERIAL PRIMARY KEY,
+    id_anak         INT NOT NULL,
+    status_lama     VARCHAR(20) NOT NULL,
+    status_baru     VARCHAR(20) NOT NULL,
+    tanggal_berlaku DATE NOT NULL,
+    alasan          TEXT,
+    id_kader        INT, -- NULL jika diubah otomatis oleh sistem
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT riwayat_status_anak_id_anak_fkey FOREIGN KEY (id_anak) REFERENCES anak(id),
+    CONSTRAINT riwayat_status_anak_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id)
+);
+
+CREATE INDEX anak_status_idx ON anak (status);
+CREATE INDEX riwayat_status_anak_id_anak_idx ON riwayat_status_anak (id_anak);
@@ -0,0 +1,51 @@
+-- 005_posyandu_mutasi.sql
+-- Posyandu sebagai pemilik data anak, serta mutasi (perpindahan) anak antar posyandu.
+CREATE TABLE posyandu (
+    id         SERIAL PRIMARY KEY,
+    nama       VARCHAR(100) NOT NULL,
+    desa       VARCHAR(100),
+    kecamatan  VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT posyandu_nama_desa_key UNIQUE (nama, desa)
+);
+
+ALTER TABLE kader
+    ADD COLUMN id_posyandu INT,
+    ADD CONSTRAINT kader_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id);
+
+-- Posyandu pemilik data anak saat ini
+ALTER TABLE anak
+    ADD COLUMN id_posyandu INT,
+    ADD CONSTRAINT anak_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id);
+
+-- Posyandu tempat data dicatat (tidak berubah saat anak dimutasi)
+ALTER TABLE perkembangan
+    ADD COLUMN id_posyandu INT,
+    ADD CONSTRAINT perkembangan_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id);
+ALTER TABLE riwayat_imunisasi
+    ADD COLUMN id_posyandu INT,
+    ADD CONSTRAINT riwayat_imunisasi_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id);
+
+CREATE TABLE mutasi_anak (
+    id                 SERIAL PRIMARY KEY,
+    id_anak            INT NOT NULL,
+    id_posyandu_asal   INT NOT NULL,
+    id_posyandu_tujuan INT NOT NULL,
+    status             VARCHAR(20) NOT NULL DEFAULT 'diajukan' CHECK (status IN ('diajukan', 'diterima', 'ditolak', 'dibatalkan')),
+    alasan             TEXT,
+    catatan_keputusan  TEXT,
+    id_kader_pengaju   INT NOT NULL,
+    id_kader_pemutus   INT,
+    tanggal_pengajuan  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    tanggal_keputusan  TIMESTAMPTZ,
+    CONSTRAINT mutasi_anak_posyandu_beda_check CHECK (id_posyandu_asal <> id_posyandu_tujuan),
+    CONSTRAINT mutasi_anak_id_anak_fkey FOREIGN KEY (id_anak) REFERENCES anak(id),
+    CONSTRAINT mutasi_anak_id_posyandu_asal_fkey FOREIGN KEY (id_posyandu_asal) REFERENCES posyandu(id),
+    CONSTRAINT mutasi_anak_id_posyandu_tujuan_fkey FOREIGN KEY (id_posyandu_tujuan) REFERENCES posyandu(id),
+    CONSTRAINT mutasi_anak_id_kader_pengaju_fkey FOREIGN KEY (id_kader_pengaju) REFERENCES kader(id),
+    CONSTRAINT mutasi_anak_id_kader_pemutus_fkey FOREIGN KEY (id_kader_pemutus) REFERENCES kader(id)
+);
+
+-- Satu anak hanya boleh punya satu pengajuan mutasi yang belum diputuskan
+CREATE UNIQUE INDEX mutasi_anak_pending_key ON mutasi_anak (id_anak) WHERE status = 'diajukan';
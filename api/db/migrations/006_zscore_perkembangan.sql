@@ -0,0 +1,14 @@
+-- 006_zscore_perkembangan.sql
+-- Z-score WHO 2006 dan kategori Permenkes 2/2020 yang dihitung otomatis saat perkembangan disimpan.
+ALTER TABLE perkembangan
+    ADD COLUMN zs_bbu      NUMERIC(5,2),
+    ADD COLUMN zs_tbu      NUMERIC(5,2),
+    ADD COLUMN zs_bbtb     NUMERIC(5,2),
+    ADD COLUMN zs_imtu     NUMERIC(5,2),
+    ADD COLUMN zs_lku      NUMERIC(5,2),
+    ADD COLUMN zs_lilau    NUMERIC(5,2),
+    ADD COLUMN status_bbu  VARCHAR(40),
+    ADD COLUMN status_tbu  VARCHAR(40),
+    ADD COLUMN status_bbtb VARCHAR(40),
+    ADD COLUMN status_imtu VARCHAR(40),
+    ADD COLUMN status_lku  VARCHAR(40);
@@ -0,0 +1,11 @@
+-- 007_kms_perkembangan.sql
+-- Hasil evaluasi KMS tiap penimbangan: N/T/O/B, 2T, BGM dan kenaikan berat badan terhadap KBM.
+ALTER TABLE perkembangan
+    ADD COLUMN kms_status       CHAR(1),
+    ADD COLUMN kms_2t           BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN kms_bgm          BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN kenaikan_bb_gram INT,
+    ADD COLUMN kbm_gram         INT,
+    ADD CONSTRAINT perkembangan_kms_status_check CHECK (kms_status IN ('N', 'T', 'O', 'B'));
+
+CREATE INDEX perkembangan_id_anak_tanggal_idx ON perkembangan (id_anak, tanggal_pemeriksaan);
@@ -0,0 +1,4 @@
+-- 008_kms_anak.sql
+-- Kepemilikan KMS/Buku KIA anak, dipakai untuk indikator K pada laporan SKDN.
+ALTER TABLE anak
+    ADD COLUMN punya_kms BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,5 @@
+-- 009_kewajaran_perkembangan.sql
+-- Peringatan kewajaran pengukuran yang dikonfirmasi kader beserta alasannya.
+ALTER TABLE perkembangan
+    ADD COLUMN peringatan        TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN alasan_konfirmasi TEXT;
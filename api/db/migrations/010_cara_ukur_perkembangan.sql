@@ -0,0 +1,6 @@
+-- 010_cara_ukur_perkembangan.sql
+-- Cara ukur panjang/tinggi badan dan hasil koreksinya (±0,7 cm) yang dipakai untuk z-score.
+ALTER TABLE perkembangan
+    ADD COLUMN cara_ukur     VARCHAR(10),
+    ADD COLUMN tb_cm_standar NUMERIC(5,1),
+    ADD CONSTRAINT perkembangan_cara_ukur_check CHECK (cara_ukur IN ('telentang', 'berdiri'));
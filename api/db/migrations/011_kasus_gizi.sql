@@ -0,0 +1,45 @@
+-- 011_kasus_gizi.sql
+-- Register kasus stunting dan wasting beserta tindak lanjutnya.
+CREATE TABLE kasus_gizi (
+    id                        SERIAL PRIMARY KEY,
+    id_anak                   INT NOT NULL,
+    jenis                     VARCHAR(20) NOT NULL CHECK (jenis IN ('stunting', 'wasting')),
+    tingkat                   VARCHAR(10) NOT NULL DEFAULT 'sedang' CHECK (tingkat IN ('sedang', 'berat')), -- berat: < -3 SD
+    sumber                    VARCHAR(10) NOT NULL CHECK (sumber IN ('otomatis', 'manual')),
+    id_perkembangan_deteksi   INT,
+    tanggal_deteksi           DATE NOT NULL,
+    z_score_deteksi           NUMERIC(5,2),
+    status                    VARCHAR(10) NOT NULL DEFAULT 'terbuka' CHECK (status IN ('terbuka', 'ditutup')),
+    dirujuk                   BOOLEAN NOT NULL DEFAULT FALSE,
+    tanggal_rujukan           DATE,
+    tempat_rujukan            TEXT,
+    tanggal_tindak_lanjut     DATE NOT NULL,  -- Jatuh tempo tindak lanjut berikutnya
+    id_kader_penanggung_jawab INT,
+    catatan                   TEXT,
+    hasil_penutupan           VARCHAR(20) CHECK (hasil_penutupan IN ('pulih', 'membaik', 'tidak_membaik', 'pindah', 'meninggal', 'lainnya')),
+    tanggal_penutupan         DATE,
+    catatan_penutupan         TEXT,
+    created_at                TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                TIMESTAMPTZ,
+    CONSTRAINT kasus_gizi_id_anak_fkey FOREIGN KEY (id_anak) REFERENCES anak(id),
+    CONSTRAINT kasus_gizi_id_perkembangan_deteksi_fkey FOREIGN KEY (id_perkembangan_deteksi) REFERENCES perkembangan(id) ON DELETE SET NULL,
+    CONSTRAINT kasus_gizi_id_kader_penanggung_jawab_fkey FOREIGN KEY (id_kader_penanggung_jawab) REFERENCES kader(id)
+);
+
+-- Satu anak hanya punya satu kasus terbuka per jenis
+CREATE UNIQUE INDEX kasus_gizi_terbuka_key ON kasus_gizi (id_anak, jenis) WHERE status = 'terbuka';
+CREATE INDEX kasus_gizi_jatuh_tempo_idx ON kasus_gizi (tanggal_tindak_lanjut) WHERE status = 'terbuka';
+
+CREATE TABLE tindak_lanjut_kasus_gizi (
+    id             SERIAL PRIMARY KEY,
+    id_kasus       INT NOT NULL,
+    tanggal        DATE NOT NULL,
+    jenis          VARCHAR(20) NOT NULL CHECK (jenis IN ('rujukan', 'pmt', 'konseling', 'kunjungan_rumah', 'lainnya')),
+    catatan        TEXT,
+    id_kader       INT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT tindak_lanjut_kasus_gizi_id_kasus_fkey FOREIGN KEY (id_kasus) REFERENCES kasus_gizi(id) ON DELETE CASCADE,
+    CONSTRAINT tindak_lanjut_kasus_gizi_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id)
+);
+
+CREATE INDEX tindak_lanjut_kasus_gizi_id_kasus_idx ON tindak_lanjut_kasus_gizi (id_kasus);
@@ -0,0 +1,10 @@
+-- 012_usia_kehamilan_lahir.sql
+-- Usia kehamilan saat lahir untuk koreksi umur bayi prematur sampai umur 24 bulan.
+ALTER TABLE anak
+    ADD COLUMN usia_kehamilan_lahir_minggu SMALLINT,
+    ADD CONSTRAINT anak_usia_kehamilan_lahir_minggu_check CHECK (usia_kehamilan_lahir_minggu BETWEEN 22 AND 44);
+
+-- Anak yang dicatat dari persalinan memakai usia kehamilan saat persalinan
+UPDATE anak a SET usia_kehamilan_lahir_minggu = p.usia_kehamilan_minggu
+FROM persalinan p
+WHERE a.id_persalinan = p.id AND p.usia_kehamilan_minggu BETWEEN 22 AND 44;
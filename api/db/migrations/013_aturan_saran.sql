@@ -0,0 +1,56 @@
+-- 013_aturan_saran.sql
+-- Peran kader, status menyusui pada perkembangan, dan aturan pembentuk saran konseling.
+ALTER TABLE kader
+    ADD COLUMN peran VARCHAR(10) NOT NULL DEFAULT 'kader',
+    ADD CONSTRAINT kader_peran_check CHECK (peran IN ('kader', 'bidan', 'admin'));
+
+-- Kader pertama yang terdaftar menjadi admin agar aturan saran dapat langsung dikelola
+UPDATE kader SET peran = 'admin' WHERE id = (SELECT MIN(id) FROM kader);
+
+ALTER TABLE perkembangan
+    ADD COLUMN status_asi VARCHAR(10),
+    ADD CONSTRAINT perkembangan_status_asi_check CHECK (status_asi IN ('eksklusif', 'parsial', 'tidak'));
+
+-- Setiap kolom kondisi yang kosong (NULL) berarti tidak membatasi.
+-- Kolom larik cocok bila nilai anak termasuk salah satu isinya.
+CREATE TABLE aturan_saran (
+    id                    SERIAL PRIMARY KEY,
+    nama                  VARCHAR(100) NOT NULL,
+    prioritas             INT NOT NULL DEFAULT 100,      -- Kecil lebih dulu ditampilkan
+    aktif                 BOOLEAN NOT NULL DEFAULT TRUE,
+    usia_min_bulan        INT,                           -- Inklusif
+    usia_max_bulan        INT,                           -- Eksklusif
+    indikator             VARCHAR(10) CHECK (indikator IN ('bbu', 'tbu', 'bbtb', 'imtu', 'lku')),
+    status                TEXT[],                        -- Kategori indikator di atas, mis. {Pendek,Sangat pendek}
+    kms_status            TEXT[],                        -- N, T, O, B
+    kms_2t                BOOLEAN,
+    imunisasi_tertunggak  BOOLEAN,
+    status_asi            TEXT[],                        -- eksklusif, parsial, tidak
+    teks_saran            TEXT NOT NULL,                 -- Boleh memuat {nama_anak}, {usia_bulan}, {imunisasi_tertunggak}
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ,
+    CONSTRAINT aturan_saran_status_indikator_check CHECK (status IS NULL OR indikator IS NOT NULL),
+    CONSTRAINT aturan_saran_usia_check CHECK (usia_min_bulan IS NULL OR usia_max_bulan IS NULL OR usia_min_bulan < usia_max_bulan)
+);
+
+INSERT INTO aturan_saran (nama, prioritas, usia_min_bulan, usia_max_bulan, indikator, status, kms_status, kms_2t, imunisasi_tertunggak, status_asi, teks_saran) VALUES
+    ('Gizi buruk', 10, NULL, NULL, 'bbtb', '{Gizi buruk}', NULL, NULL, NULL, NULL,
+     'Status gizi buruk. Segera rujuk {nama_anak} ke puskesmas untuk pemeriksaan dan tata laksana gizi buruk.'),
+    ('Dua kali tidak naik', 10, NULL, NULL, NULL, NULL, NULL, TRUE, NULL, NULL,
+     'Berat badan tidak naik dua kali berturut-turut (2T). Rujuk ke bidan atau puskesmas untuk mencari penyebabnya.'),
+    ('Gizi kurang', 20, NULL, NULL, 'bbtb', '{Gizi kurang}', NULL, NULL, NULL, NULL,
+     'Status gizi kurang. Berikan PMT pemulihan, tambah porsi dan frekuensi makan dengan lauk hewani, dan timbang ulang 2 minggu lagi.'),
+    ('Pendek', 20, NULL, NULL, 'tbu', '{Pendek,Sangat pendek}', NULL, NULL, NULL, NULL,
+     'Panjang/tinggi badan di bawah standar umur. Rujuk ke puskesmas untuk konfirmasi stunting dan pastikan anak mendapat protein hewani setiap hari.'),
+    ('Tidak naik', 30, NULL, NULL, NULL, NULL, '{T}', FALSE, NULL, NULL,
+     'Berat badan tidak naik bulan ini. Tanyakan pola makan dan riwayat sakit, beri konseling pemberian makan, dan pastikan anak ditimbang bulan depan.'),
+    ('Imunisasi belum lengkap', 30, NULL, NULL, NULL, NULL, NULL, NULL, TRUE, NULL,
+     'Imunisasi yang terlewat: {imunisasi_tertunggak}. Anjurkan ibu melengkapinya pada jadwal posyandu atau di puskesmas terdekat.'),
+    ('ASI belum eksklusif', 40, 0, 6, NULL, NULL, NULL, NULL, NULL, '{parsial,tidak}',
+     'Berikan ASI saja tanpa makanan atau minuman lain sampai umur 6 bulan. Susui sesering mungkin, minimal 8 kali sehari.'),
+    ('ASI eksklusif', 40, 0, 6, NULL, NULL, NULL, NULL, NULL, '{eksklusif}',
+     'Pertahankan ASI eksklusif sampai umur 6 bulan.'),
+    ('MP-ASI', 50, 6, 24, NULL, NULL, NULL, NULL, NULL, NULL,
+     'Berikan MP-ASI sesuai umur {usia_bulan} bulan dengan lauk hewani setiap kali makan, dan lanjutkan ASI sampai 2 tahun.'),
+    ('Pemantauan rutin', 100, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL,
+     'Lanjutkan penimbangan setiap bulan di posyandu.');
@@ -0,0 +1,31 @@
+-- 014_seri_dosis_imunisasi.sql
+-- Seri dosis, batas umur dan interval minimal pada master imunisasi, serta peringatan dosis pada riwayat.
+ALTER TABLE master_imunisasi
+    ADD COLUMN seri              VARCHAR(50),                -- Nama seri, mis. 'DPT-HB-Hib'; NULL untuk antigen dosis tunggal
+    ADD COLUMN dosis_ke          SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN usia_min_hari     INT,                        -- Umur termuda dosis dianggap sah
+    ADD COLUMN usia_max_hari     INT,                        -- Umur tertua dosis masih boleh diberikan
+    ADD COLUMN interval_min_hari INT,                        -- Jarak minimal dari dosis sebelumnya pada seri yang sama
+    ADD CONSTRAINT master_imunisasi_dosis_ke_check CHECK (dosis_ke >= 1),
+    ADD CONSTRAINT master_imunisasi_usia_check CHECK (usia_min_hari IS NULL OR usia_max_hari IS NULL OR usia_min_hari <= usia_max_hari),
+    ADD CONSTRAINT master_imunisasi_interval_check CHECK (interval_min_hari IS NULL OR (seri IS NOT NULL AND dosis_ke > 1));
+
+CREATE UNIQUE INDEX master_imunisasi_seri_dosis_key ON master_imunisasi (seri, dosis_ke) WHERE seri IS NOT NULL;
+
+-- Nama berakhiran nomor dosis (mis. 'Polio 2', 'DPT-HB-Hib 3') dipecah menjadi seri dan dosis_ke
+UPDATE master_imunisasi
+SET seri = TRIM(SUBSTRING(nama_imunisasi FROM '^(.*[^0-9 ])\s*[1-9]$')),
+    dosis_ke = SUBSTRING(nama_imunisasi FROM '([1-9])$')::smallint
+WHERE nama_imunisasi ~ '^.*[^0-9 ]\s*[1-9]$';
+
+-- Nilai awal mengikuti jadwal imunisasi nasional; admin sebaiknya memeriksa ulang
+UPDATE master_imunisasi SET interval_min_hari = 28 WHERE seri IS NOT NULL AND dosis_ke > 1;
+UPDATE master_imunisasi SET usia_min_hari = 42
+WHERE (seri ILIKE 'DPT%' OR seri ILIKE 'PCV%' OR seri ILIKE 'Rota%') AND dosis_ke = 1;
+UPDATE master_imunisasi SET usia_min_hari = 270 WHERE nama_imunisasi ~* '^(MR|campak)' AND dosis_ke = 1;
+UPDATE master_imunisasi SET usia_max_hari = 7 WHERE nama_imunisasi ~* '^(HB|hepatitis B)[ -]?0$';
+UPDATE master_imunisasi SET usia_max_hari = 365 WHERE nama_imunisasi ILIKE 'BCG%';
+
+ALTER TABLE riwayat_imunisasi
+    ADD COLUMN peringatan        TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN alasan_konfirmasi TEXT;
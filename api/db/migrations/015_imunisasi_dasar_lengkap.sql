@@ -0,0 +1,7 @@
+-- 015_imunisasi_dasar_lengkap.sql
+-- Penanda antigen yang termasuk Imunisasi Dasar Lengkap (IDL).
+ALTER TABLE master_imunisasi
+    ADD COLUMN termasuk_idl BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Imunisasi dasar adalah antigen yang dijadwalkan sebelum umur 12 bulan; admin dapat menyesuaikan
+UPDATE master_imunisasi SET termasuk_idl = TRUE WHERE usia_ideal_bulan < 12;
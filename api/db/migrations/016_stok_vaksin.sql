@@ -0,0 +1,50 @@
+-- 016_stok_vaksin.sql
+-- Persediaan vaksin per batch: penerimaan, pemakaian, vial dibuka dan vaksin terbuang.
+CREATE TABLE batch_vaksin (
+    id                  SERIAL PRIMARY KEY,
+    id_posyandu         INT,                         -- Lokasi penyimpanan; NULL untuk gudang puskesmas
+    nama_vaksin         VARCHAR(50) NOT NULL,        -- Sama dengan seri master imunisasi, atau nama imunisasi untuk dosis tunggal
+    nomor_batch         VARCHAR(50) NOT NULL,
+    produsen            VARCHAR(100),
+    tanggal_kedaluwarsa DATE NOT NULL,
+    dosis_per_vial      SMALLINT NOT NULL DEFAULT 1 CHECK (dosis_per_vial >= 1),
+    status_vvm          VARCHAR(1) NOT NULL DEFAULT 'A' CHECK (status_vvm IN ('A', 'B', 'C', 'D')), -- C dan D tidak boleh dipakai
+    stok_dosis          INT NOT NULL DEFAULT 0,      -- Jumlah seluruh transaksi, disimpan agar cepat dibaca
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ,
+    CONSTRAINT batch_vaksin_stok_dosis_check CHECK (stok_dosis >= 0),
+    CONSTRAINT batch_vaksin_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id)
+);
+
+CREATE UNIQUE INDEX batch_vaksin_lokasi_nomor_key ON batch_vaksin (COALESCE(id_posyandu, 0), nama_vaksin, nomor_batch);
+
+ALTER TABLE riwayat_imunisasi
+    ADD COLUMN id_batch_vaksin INT,
+    ADD CONSTRAINT riwayat_imunisasi_id_batch_vaksin_fkey FOREIGN KEY (id_batch_vaksin) REFERENCES batch_vaksin(id);
+
+-- jumlah_dosis bertanda: penerimaan positif, pemakaian dan terbuang negatif, buka_vial nol
+CREATE TABLE transaksi_vaksin (
+    id                   SERIAL PRIMARY KEY,
+    id_batch             INT NOT NULL,
+    jenis                VARCHAR(20) NOT NULL CHECK (jenis IN ('penerimaan', 'pemakaian', 'buka_vial', 'terbuang', 'penyesuaian')),
+    tanggal              DATE NOT NULL,
+    jumlah_dosis         INT NOT NULL,
+    jumlah_vial          INT,
+    alasan               VARCHAR(20) CHECK (alasan IN ('sisa_vial', 'kedaluwarsa', 'vvm', 'rusak', 'hilang', 'lainnya')),
+    id_riwayat_imunisasi INT,
+    id_kader             INT,
+    catatan              TEXT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT transaksi_vaksin_id_batch_fkey FOREIGN KEY (id_batch) REFERENCES batch_vaksin(id) ON DELETE CASCADE,
+    CONSTRAINT transaksi_vaksin_id_riwayat_imunisasi_fkey FOREIGN KEY (id_riwayat_imunisasi) REFERENCES riwayat_imunisasi(id) ON DELETE SET NULL,
+    CONSTRAINT transaksi_vaksin_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id),
+    CONSTRAINT transaksi_vaksin_jumlah_check CHECK (
+        (jenis = 'penerimaan' AND jumlah_dosis > 0) OR
+        (jenis IN ('pemakaian', 'terbuang') AND jumlah_dosis < 0) OR
+        (jenis = 'buka_vial' AND jumlah_dosis = 0 AND jumlah_vial > 0) OR
+        jenis = 'penyesuaian'),
+    CONSTRAINT transaksi_vaksin_alasan_check CHECK (jenis = 'terbuang' OR alasan IS NULL)
+);
+
+CREATE INDEX transaksi_vaksin_id_batch_tanggal_idx ON transaksi_vaksin (id_batch, tanggal);
+CREATE INDEX transaksi_vaksin_id_riwayat_imunisasi_idx ON transaksi_vaksin (id_riwayat_imunisasi);
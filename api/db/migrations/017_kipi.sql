@@ -0,0 +1,41 @@
+-- 017_kipi.sql
+-- Laporan Kejadian Ikutan Pasca Imunisasi (KIPI) dan notifikasi untuk kader.
+CREATE TABLE kipi (
+    id                   SERIAL PRIMARY KEY,
+    id_riwayat_imunisasi INT NOT NULL,
+    gejala               TEXT[] NOT NULL,
+    waktu_mulai          TIMESTAMPTZ NOT NULL,  -- Saat gejala pertama muncul
+    keparahan            VARCHAR(10) NOT NULL CHECK (keparahan IN ('ringan', 'sedang', 'berat')),
+    hasil                VARCHAR(20) NOT NULL DEFAULT 'belum_diketahui'
+                         CHECK (hasil IN ('sembuh', 'dalam_perawatan', 'rawat_inap', 'gejala_sisa', 'meninggal', 'belum_diketahui')),
+    serius               BOOLEAN NOT NULL DEFAULT FALSE, -- Berat, rawat inap, gejala sisa atau meninggal
+    tindakan             TEXT,
+    dirujuk              BOOLEAN NOT NULL DEFAULT FALSE,
+    tanggal_rujukan      DATE,
+    tempat_rujukan       TEXT,
+    id_kader_pelapor     INT,
+    id_kader_updater     INT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ,
+    CONSTRAINT kipi_gejala_check CHECK (cardinality(gejala) > 0),
+    CONSTRAINT kipi_id_riwayat_imunisasi_fkey FOREIGN KEY (id_riwayat_imunisasi) REFERENCES riwayat_imunisasi(id),
+    CONSTRAINT kipi_id_kader_pelapor_fkey FOREIGN KEY (id_kader_pelapor) REFERENCES kader(id),
+    CONSTRAINT kipi_id_kader_updater_fkey FOREIGN KEY (id_kader_updater) REFERENCES kader(id)
+);
+
+CREATE INDEX kipi_id_riwayat_imunisasi_idx ON kipi (id_riwayat_imunisasi);
+CREATE INDEX kipi_serius_idx ON kipi (waktu_mulai) WHERE serius;
+
+CREATE TABLE notifikasi (
+    id           SERIAL PRIMARY KEY,
+    id_kader     INT NOT NULL,             -- Penerima
+    jenis        VARCHAR(30) NOT NULL,     -- Mis. kipi_serius
+    judul        VARCHAR(150) NOT NULL,
+    pesan        TEXT NOT NULL,
+    id_referensi INT,                      -- ID data sumber sesuai jenis
+    dibaca_at    TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT notifikasi_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notifikasi_belum_dibaca_idx ON notifikasi (id_kader, created_at) WHERE dibaca_at IS NULL;
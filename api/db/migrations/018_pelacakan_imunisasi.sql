@@ -0,0 +1,16 @@
+-- 018_pelacakan_imunisasi.sql
+-- Catatan upaya pelacakan anak yang tertinggal jadwal imunisasi (defaulter).
+CREATE TABLE pelacakan_imunisasi (
+    id                   SERIAL PRIMARY KEY,
+    id_anak              INT NOT NULL,
+    tanggal              DATE NOT NULL,
+    hasil                VARCHAR(15) NOT NULL CHECK (hasil IN ('ditelepon', 'dikunjungi', 'menolak', 'pindah')),
+    imunisasi_tertunggak TEXT[] NOT NULL DEFAULT '{}', -- Dosis tertunggak saat pelacakan dicatat
+    catatan              TEXT,
+    id_kader             INT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pelacakan_imunisasi_id_anak_fkey FOREIGN KEY (id_anak) REFERENCES anak(id) ON DELETE CASCADE,
+    CONSTRAINT pelacakan_imunisasi_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id)
+);
+
+CREATE INDEX pelacakan_imunisasi_id_anak_tanggal_idx ON pelacakan_imunisasi (id_anak, tanggal);
@@ -0,0 +1,35 @@
+-- 019_versi_jadwal_imunisasi.sql
+-- Versi jadwal imunisasi dengan tanggal berlaku per kohort kelahiran. master_imunisasi tetap menjadi
+-- katalog dosis (dan draf jadwal); parameter jadwal yang dipakai dibekukan di setiap versi.
+CREATE TABLE versi_jadwal_imunisasi (
+    id                SERIAL PRIMARY KEY,
+    nama              VARCHAR(100) NOT NULL,
+    berlaku_mulai     DATE NOT NULL,  -- Berlaku bagi anak yang lahir pada atau sesudah tanggal ini
+    keterangan        TEXT,
+    id_kader_penerbit INT,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT versi_jadwal_imunisasi_berlaku_mulai_key UNIQUE (berlaku_mulai),
+    CONSTRAINT versi_jadwal_imunisasi_id_kader_penerbit_fkey FOREIGN KEY (id_kader_penerbit) REFERENCES kader(id)
+);
+
+CREATE TABLE dosis_versi_jadwal_imunisasi (
+    id_versi            INT NOT NULL,
+    id_master_imunisasi INT NOT NULL,
+    usia_ideal_bulan    INT NOT NULL,
+    usia_min_hari       INT,
+    usia_max_hari       INT,
+    interval_min_hari   INT,
+    termasuk_idl        BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT dosis_versi_jadwal_imunisasi_pkey PRIMARY KEY (id_versi, id_master_imunisasi),
+    CONSTRAINT dosis_versi_jadwal_imunisasi_id_versi_fkey FOREIGN KEY (id_versi) REFERENCES versi_jadwal_imunisasi(id) ON DELETE CASCADE,
+    CONSTRAINT dosis_versi_jadwal_imunisasi_id_master_imunisasi_fkey FOREIGN KEY (id_master_imunisasi) REFERENCES master_imunisasi(id)
+);
+
+-- Jadwal yang dipakai selama ini dibekukan sebagai versi pertama bagi seluruh kohort
+INSERT INTO versi_jadwal_imunisasi (nama, berlaku_mulai, keterangan)
+VALUES ('Jadwal awal', '1900-01-01', 'Salinan master imunisasi saat versi jadwal diperkenalkan');
+
+INSERT INTO dosis_versi_jadwal_imunisasi (id_versi, id_master_imunisasi, usia_ideal_bulan, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl)
+SELECT v.id, m.id, m.usia_ideal_bulan, m.usia_min_hari, m.usia_max_hari, m.interval_min_hari, m.termasuk_idl
+FROM master_imunisasi m, versi_jadwal_imunisasi v
+WHERE v.berlaku_mulai = '1900-01-01';
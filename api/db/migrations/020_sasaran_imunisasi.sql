@@ -0,0 +1,16 @@
+-- 020_sasaran_imunisasi.sql
+-- Sasaran (denominator) cakupan imunisasi tahunan per posyandu. Bila tidak diisi, laporan memakai kohort kelahiran terdaftar.
+CREATE TABLE sasaran_imunisasi (
+    id             SERIAL PRIMARY KEY,
+    tahun          INT NOT NULL,
+    id_posyandu    INT NOT NULL,
+    sasaran_bayi   INT NOT NULL, -- Bayi (surviving infant) untuk antigen sebelum umur 12 bulan
+    sasaran_baduta INT NOT NULL, -- Anak umur 12-23 bulan untuk imunisasi lanjutan
+    id_kader       INT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ,
+    CONSTRAINT sasaran_imunisasi_tahun_posyandu_key UNIQUE (tahun, id_posyandu),
+    CONSTRAINT sasaran_imunisasi_jumlah_check CHECK (sasaran_bayi >= 0 AND sasaran_baduta >= 0),
+    CONSTRAINT sasaran_imunisasi_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id) ON DELETE CASCADE,
+    CONSTRAINT sasaran_imunisasi_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id)
+);
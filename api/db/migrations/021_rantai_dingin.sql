@@ -0,0 +1,60 @@
+-- 021_rantai_dingin.sql
+-- Pencatatan suhu alat rantai dingin vaksin dan ekskursi suhu beserta batch vaksin yang tersimpan saat itu.
+CREATE TABLE alat_rantai_dingin (
+    id          SERIAL PRIMARY KEY,
+    nama        VARCHAR(100) NOT NULL,
+    jenis       VARCHAR(20) NOT NULL CHECK (jenis IN ('lemari_es', 'freezer', 'cold_box', 'vaccine_carrier')),
+    id_posyandu INT,                                -- Lokasi; NULL untuk gudang puskesmas
+    nomor_seri  VARCHAR(50),
+    suhu_min    NUMERIC(4,1) NOT NULL DEFAULT 2.0,
+    suhu_max    NUMERIC(4,1) NOT NULL DEFAULT 8.0,
+    aktif       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ,
+    CONSTRAINT alat_rantai_dingin_suhu_check CHECK (suhu_min < suhu_max),
+    CONSTRAINT alat_rantai_dingin_id_posyandu_fkey FOREIGN KEY (id_posyandu) REFERENCES posyandu(id)
+);
+
+CREATE TABLE suhu_alat (
+    id         SERIAL PRIMARY KEY,
+    id_alat    INT NOT NULL,
+    waktu      TIMESTAMPTZ NOT NULL,
+    suhu       NUMERIC(4,1) NOT NULL,
+    sumber     VARCHAR(10) NOT NULL DEFAULT 'manual' CHECK (sumber IN ('manual', 'logger')),
+    id_kader   INT,
+    catatan    TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT suhu_alat_id_alat_waktu_key UNIQUE (id_alat, waktu),
+    CONSTRAINT suhu_alat_id_alat_fkey FOREIGN KEY (id_alat) REFERENCES alat_rantai_dingin(id) ON DELETE CASCADE,
+    CONSTRAINT suhu_alat_id_kader_fkey FOREIGN KEY (id_kader) REFERENCES kader(id)
+);
+
+CREATE TABLE ekskursi_suhu (
+    id                SERIAL PRIMARY KEY,
+    id_alat           INT NOT NULL,
+    jenis             VARCHAR(10) NOT NULL CHECK (jenis IN ('panas', 'beku')),
+    mulai             TIMESTAMPTZ NOT NULL,
+    selesai           TIMESTAMPTZ,                  -- NULL selama suhu belum kembali normal
+    suhu_ekstrem      NUMERIC(4,1) NOT NULL,
+    jumlah_bacaan     INT NOT NULL,
+    status            VARCHAR(10) NOT NULL DEFAULT 'baru' CHECK (status IN ('baru', 'ditinjau')),
+    tindak_lanjut     TEXT,
+    id_kader_peninjau INT,
+    ditinjau_at       TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ,
+    CONSTRAINT ekskursi_suhu_id_alat_fkey FOREIGN KEY (id_alat) REFERENCES alat_rantai_dingin(id) ON DELETE CASCADE,
+    CONSTRAINT ekskursi_suhu_id_kader_peninjau_fkey FOREIGN KEY (id_kader_peninjau) REFERENCES kader(id)
+);
+
+CREATE INDEX ekskursi_suhu_id_alat_mulai_idx ON ekskursi_suhu (id_alat, mulai);
+
+-- Batch di lokasi alat yang memiliki stok pada saat mana pun selama ekskursi
+CREATE TABLE ekskursi_batch_vaksin (
+    id_ekskursi     INT NOT NULL,
+    id_batch_vaksin INT NOT NULL,
+    stok_dosis      INT NOT NULL,                   -- Stok awal ditambah penerimaan selama ekskursi
+    PRIMARY KEY (id_ekskursi, id_batch_vaksin),
+    CONSTRAINT ekskursi_batch_vaksin_id_ekskursi_fkey FOREIGN KEY (id_ekskursi) REFERENCES ekskursi_suhu(id) ON DELETE CASCADE,
+    CONSTRAINT ekskursi_batch_vaksin_id_batch_vaksin_fkey FOREIGN KEY (id_batch_vaksin) REFERENCES batch_vaksin(id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+-- 022_ekskursi_digantikan.sql
+-- Ekskursi yang tidak lagi terdeteksi (mis. tergabung dengan ekskursi lain setelah bacaan susulan atau
+-- perubahan rentang suhu alat) tidak dihapus, tetapi ditandai digantikan agar tinjauan dan batch terkait tetap tersimpan.
+ALTER TABLE ekskursi_suhu
+    ADD COLUMN digantikan_oleh INT,                 -- Ekskursi yang menggabungkannya; NULL bila tidak ada pengganti
+    ADD COLUMN digantikan_at   TIMESTAMPTZ,
+    ADD CONSTRAINT ekskursi_suhu_digantikan_oleh_fkey FOREIGN KEY (digantikan_oleh) REFERENCES ekskursi_suhu(id) ON DELETE SET NULL,
+    ADD CONSTRAINT ekskursi_suhu_digantikan_check CHECK (digantikan_oleh IS NULL OR digantikan_at IS NOT NULL);
@@ -0,0 +1,15 @@
+-- 023_tanpa_bbtb_lilau.sql
+-- Tabel WHO BB/PB-TB dan LILA/U yang sebelumnya disertakan hanyalah tabel pengganti yang jarang, bukan berkas resmi.
+-- Sampai berkas resmi disertakan, indikator tersebut tidak dihitung: hapus z-score dan kategori yang berasal dari
+-- tabel pengganti, ambil status gizi utama dari IMT/U, dan alihkan aturan saran gizi buruk/kurang ke IMT/U.
+UPDATE perkembangan
+SET status_gizi = status_imtu
+WHERE status_bbtb IS NOT NULL;
+
+UPDATE perkembangan
+SET zs_bbtb = NULL, status_bbtb = NULL, zs_lilau = NULL
+WHERE zs_bbtb IS NOT NULL OR status_bbtb IS NOT NULL OR zs_lilau IS NOT NULL;
+
+UPDATE aturan_saran
+SET indikator = 'imtu', updated_at = NOW()
+WHERE indikator = 'bbtb';
@@ -0,0 +1,112 @@
+// Package grafik menggambar grafik pertumbuhan anak di atas kurva SD WHO dengan warna KMS,
+// dalam format SVG maupun PNG, tanpa ketergantungan pustaka luar.
+package grafik
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+)
+
+// Ukuran kanvas dan margin area plot (piksel)
+const (
+	Lebar       = 800
+	Tinggi      = 560
+	marginKiri  = 64
+	marginKanan = 40
+	marginAtas  = 56
+	marginBawah = 56
+)
+
+// Titik adalah satu titik data dalam satuan sumbu (bukan piksel)
+type Titik struct {
+	X, Y float64
+}
+
+// Grafik adalah spesifikasi satu grafik pertumbuhan
+type Grafik struct {
+	Judul    string
+	Subjudul string
+	LabelX   string
+	LabelY   string
+	MinX     float64
+	MaxX     float64
+	LangkahX float64 // Jarak garis bantu sumbu X
+	MinY     float64
+	MaxY     float64
+	LangkahY float64 // Jarak garis bantu sumbu Y
+	// Kurva berisi kurva SD WHO berurutan dari -3 SD (indeks 0) sampai +3 SD (indeks 6)
+	Kurva [7][]Titik
+	Anak  []Titik
+}
+
+// Warna KMS: pita hijau di sekitar median, kuning mendekati batas, garis merah pada -3 SD
+var (
+	warnaLatar      = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	warnaGrid       = color.RGBA{0xE0, 0xE0, 0xE0, 0xFF}
+	warnaSumbu      = color.RGBA{0x42, 0x42, 0x42, 0xFF}
+	warnaTeks       = color.RGBA{0x21, 0x21, 0x21, 0xFF}
+	warnaTitikAnak  = color.RGBA{0x15, 0x65, 0xC0, 0xFF}
+	warnaHijauTua   = color.RGBA{0x81, 0xC7, 0x84, 0xFF}
+	warnaHijauMuda  = color.RGBA{0xC5, 0xE1, 0xA5, 0xFF}
+	warnaKuning     = color.RGBA{0xFF, 0xF1, 0x76, 0xFF}
+	warnaGarisMerah = color.RGBA{0xD3, 0x2F, 0x2F, 0xFF}
+	warnaGarisOren  = color.RGBA{0xF5, 0x7C, 0x00, 0xFF}
+	warnaGarisHijau = color.RGBA{0x2E, 0x7D, 0x32, 0xFF}
+)
+
+// warnaPita adalah warna pita di antara kurva i dan i+1
+var warnaPita = [6]color.RGBA{warnaKuning, warnaHijauMuda, warnaHijauTua, warnaHijauTua, warnaHijauMuda, warnaKuning}
+
+// warnaKurva adalah warna garis kurva -3 SD sampai +3 SD
+var warnaKurva = [7]color.RGBA{warnaGarisMerah, warnaGarisOren, warnaGarisHijau, warnaGarisHijau, warnaGarisHijau, warnaGarisOren, warnaGarisMerah}
+
+// labelKurva adalah label di ujung kanan setiap kurva
+var labelKurva = [7]string{"-3", "-2", "-1", "0", "+1", "+2", "+3"}
+
+// px mengubah koordinat data menjadi koordinat piksel
+func (g *Grafik) px(t Titik) (float64, float64) {
+	lebarPlot := float64(Lebar - marginKiri - marginKanan)
+	tinggiPlot := float64(Tinggi - marginAtas - marginBawah)
+	x := marginKiri + (t.X-g.MinX)/(g.MaxX-g.MinX)*lebarPlot
+	y := marginAtas + (g.MaxY-t.Y)/(g.MaxY-g.MinY)*tinggiPlot
+	return x, y
+}
+
+// pita mengembalikan poligon pita di antara kurva i dan i+1
+func (g *Grafik) pita(i int) []Titik {
+	bawah, atas := g.Kurva[i], g.Kurva[i+1]
+	poligon := make([]Titik, 0, len(bawah)+len(atas))
+	poligon = append(poligon, atas...)
+	for j := len(bawah) - 1; j >= 0; j-- {
+		poligon = append(poligon, bawah[j])
+	}
+	return poligon
+}
+
+// tanda mengembalikan nilai-nilai garis bantu dari min sampai max
+func tanda(min, max, langkah float64) []float64 {
+	var hasil []float64
+	for v := math.Ceil(min/langkah) * langkah; v <= max+langkah/1e6; v += langkah {
+		hasil = append(hasil, math.Round(v*1000)/1000)
+	}
+	return hasil
+}
+
+// formatAngka menulis angka tanpa desimal berlebih
+func formatAngka(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// LangkahSumbu memilih jarak garis bantu yang menghasilkan paling banyak maksTanda garis
+func LangkahSumbu(rentang float64, maksTanda int) float64 {
+	for _, l := range []float64{0.5, 1, 2, 5, 10, 20} {
+		if rentang/l <= float64(maksTanda) {
+			return l
+		}
+	}
+	return 50
+}
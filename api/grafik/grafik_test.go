@@ -0,0 +1,69 @@
+package grafik
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestLangkahSumbu(t *testing.T) {
+	kasus := []struct {
+		rentang   float64
+		maksTanda int
+		ingin     float64
+	}{
+		{4, 10, 0.5},
+		{8, 10, 1},
+		{25, 10, 5},
+		{60, 12, 5},
+		{120, 10, 20},
+		{1000, 10, 50},
+	}
+	for _, k := range kasus {
+		if got := LangkahSumbu(k.rentang, k.maksTanda); got != k.ingin {
+			t.Errorf("LangkahSumbu(%v, %d) = %v, ingin %v", k.rentang, k.maksTanda, got, k.ingin)
+		}
+	}
+}
+
+// grafikContoh adalah grafik kecil dengan kurva lurus yang sejajar
+func grafikContoh() *Grafik {
+	g := &Grafik{
+		Judul: "Berat Badan menurut Umur <L & P>", LabelX: "Umur (bulan)", LabelY: "Berat (kg)",
+		MinX: 0, MaxX: 24, LangkahX: 2, MinY: 0, MaxY: 16, LangkahY: 2,
+		Anak: []Titik{{0, 3.3}, {6, 7.9}, {12, 9.6}},
+	}
+	for i := range g.Kurva {
+		for _, x := range []float64{0, 12, 24} {
+			g.Kurva[i] = append(g.Kurva[i], Titik{x, 2 + float64(i) + x/3})
+		}
+	}
+	return g
+}
+
+func TestSVGValid(t *testing.T) {
+	dec := xml.NewDecoder(bytes.NewReader(grafikContoh().SVG()))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG tidak valid: %v", err)
+		}
+	}
+}
+
+func TestPNGUkuran(t *testing.T) {
+	data, err := grafikContoh().PNG()
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG tidak valid: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Lebar || b.Dy() != Tinggi {
+		t.Errorf("ukuran PNG = %dx%d, ingin %dx%d", b.Dx(), b.Dy(), Lebar, Tinggi)
+	}
+}
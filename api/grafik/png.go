@@ -0,0 +1,169 @@
+package grafik
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"sort"
+)
+
+// kanvas adalah rasterizer sederhana di atas image.RGBA
+type kanvas struct {
+	img *image.RGBA
+	// Batas area gambar (klip); piksel di luar tidak diubah
+	klip image.Rectangle
+}
+
+func (k *kanvas) set(x, y int, c color.RGBA) {
+	if image.Pt(x, y).In(k.klip) {
+		k.img.SetRGBA(x, y, c)
+	}
+}
+
+// isiPoligon mewarnai poligon dengan aturan even-odd per baris piksel
+func (k *kanvas) isiPoligon(titik [][2]float64, c color.RGBA) {
+	if len(titik) < 3 {
+		return
+	}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, t := range titik {
+		minY = math.Min(minY, t[1])
+		maxY = math.Max(maxY, t[1])
+	}
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		py := float64(y) + 0.5
+		var potong []float64
+		for i := range titik {
+			a, b := titik[i], titik[(i+1)%len(titik)]
+			if (a[1] <= py) == (b[1] <= py) {
+				continue
+			}
+			potong = append(potong, a[0]+(py-a[1])/(b[1]-a[1])*(b[0]-a[0]))
+		}
+		sort.Float64s(potong)
+		for i := 0; i+1 < len(potong); i += 2 {
+			for x := int(math.Round(potong[i])); x < int(math.Round(potong[i+1])); x++ {
+				k.set(x, y, c)
+			}
+		}
+	}
+}
+
+// lingkaran mewarnai lingkaran penuh berpusat di (cx, cy)
+func (k *kanvas) lingkaran(cx, cy, r float64, c color.RGBA) {
+	for y := int(math.Floor(cy - r)); y <= int(math.Ceil(cy+r)); y++ {
+		for x := int(math.Floor(cx - r)); x <= int(math.Ceil(cx+r)); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r*r {
+				k.set(x, y, c)
+			}
+		}
+	}
+}
+
+// garis menggambar garis setebal tebal piksel
+func (k *kanvas) garis(x1, y1, x2, y2, tebal float64, c color.RGBA) {
+	panjang := math.Hypot(x2-x1, y2-y1)
+	langkah := int(math.Ceil(panjang*2)) + 1
+	for i := 0; i <= langkah; i++ {
+		t := float64(i) / float64(langkah)
+		k.lingkaran(x1+t*(x2-x1), y1+t*(y2-y1), tebal/2, c)
+	}
+}
+
+// poliGaris menggambar garis yang menghubungkan titik-titik data
+func (k *kanvas) poliGaris(g *Grafik, titik []Titik, tebal float64, c color.RGBA) {
+	for i := 1; i < len(titik); i++ {
+		x1, y1 := g.px(titik[i-1])
+		x2, y2 := g.px(titik[i])
+		k.garis(x1, y1, x2, y2, tebal, c)
+	}
+}
+
+// PNG menggambar grafik sebagai gambar PNG
+func (g *Grafik) PNG() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Lebar, Tinggi))
+	k := &kanvas{img: img, klip: img.Bounds()}
+	for y := 0; y < Tinggi; y++ {
+		for x := 0; x < Lebar; x++ {
+			img.SetRGBA(x, y, warnaLatar)
+		}
+	}
+
+	kiri, atas := g.px(Titik{g.MinX, g.MaxY})
+	kanan, bawah := g.px(Titik{g.MaxX, g.MinY})
+	plot := image.Rect(int(kiri), int(atas), int(math.Ceil(kanan)), int(math.Ceil(bawah)))
+
+	// Pita warna KMS dan kurva SD, dipotong pada area plot
+	k.klip = plot
+	for i := 0; i < 6; i++ {
+		pita := g.pita(i)
+		poligon := make([][2]float64, len(pita))
+		for j, t := range pita {
+			x, y := g.px(t)
+			poligon[j] = [2]float64{x, y}
+		}
+		k.isiPoligon(poligon, warnaPita[i])
+	}
+	k.klip = img.Bounds()
+
+	skala := 2
+	for _, v := range tanda(g.MinX, g.MaxX, g.LangkahX) {
+		x, _ := g.px(Titik{v, g.MinY})
+		k.garis(x, atas, x, bawah, 1, warnaGrid)
+		label := formatAngka(v)
+		k.tulis(label, int(x)-lebarTeks(label, 1)/2, int(bawah)+6, 1, warnaTeks)
+	}
+	for _, v := range tanda(g.MinY, g.MaxY, g.LangkahY) {
+		_, y := g.px(Titik{g.MinX, v})
+		k.garis(kiri, y, kanan, y, 1, warnaGrid)
+		label := formatAngka(v)
+		k.tulis(label, int(kiri)-6-lebarTeks(label, 1), int(y)-tinggiHuruf/2, 1, warnaTeks)
+	}
+
+	k.klip = plot
+	for i, kurva := range g.Kurva {
+		tebal := 1.5
+		if i == 3 || i == 0 {
+			tebal = 2.5
+		}
+		k.poliGaris(g, kurva, tebal, warnaKurva[i])
+	}
+	k.klip = img.Bounds()
+	for i, kurva := range g.Kurva {
+		if len(kurva) == 0 {
+			continue
+		}
+		x, y := g.px(kurva[len(kurva)-1])
+		if y < atas || y > bawah {
+			continue
+		}
+		k.tulis(labelKurva[i], int(x)+4, int(y)-tinggiHuruf/2, 1, warnaKurva[i])
+	}
+
+	k.garis(kiri, atas, kanan, atas, 1, warnaSumbu)
+	k.garis(kiri, bawah, kanan, bawah, 1, warnaSumbu)
+	k.garis(kiri, atas, kiri, bawah, 1, warnaSumbu)
+	k.garis(kanan, atas, kanan, bawah, 1, warnaSumbu)
+
+	k.poliGaris(g, g.Anak, 2, warnaTitikAnak)
+	for _, t := range g.Anak {
+		x, y := g.px(t)
+		k.lingkaran(x, y, 5, warnaLatar)
+		k.lingkaran(x, y, 4, warnaTitikAnak)
+	}
+
+	k.tulis(g.Judul, marginKiri, 10, skala, warnaTeks)
+	k.tulis(g.Subjudul, marginKiri, 10+tinggiHuruf*skala+6, 1, warnaTeks)
+	k.tulis(g.LabelX, int((kiri+kanan)/2)-lebarTeks(g.LabelX, 1)/2, Tinggi-18, 1, warnaTeks)
+	// Tanpa rotasi teks, nama sumbu Y ditulis di atas sumbu
+	k.tulis(g.LabelY, 8, int(atas)-14, 1, warnaTeks)
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
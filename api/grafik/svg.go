@@ -0,0 +1,101 @@
+package grafik
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// hex mengubah warna menjadi notasi #RRGGBB
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// escape meloloskan teks untuk disisipkan ke SVG
+func escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// titikSVG menulis daftar titik untuk atribut points
+func (g *Grafik) titikSVG(titik []Titik) string {
+	bagian := make([]string, len(titik))
+	for i, t := range titik {
+		x, y := g.px(t)
+		bagian[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(bagian, " ")
+}
+
+// SVG menggambar grafik sebagai dokumen SVG
+func (g *Grafik) SVG() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Arial, Helvetica, sans-serif">`+"\n", Lebar, Tinggi, Lebar, Tinggi)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", Lebar, Tinggi, hex(warnaLatar))
+
+	kiri, atas := g.px(Titik{g.MinX, g.MaxY})
+	kanan, bawah := g.px(Titik{g.MaxX, g.MinY})
+	fmt.Fprintf(&b, `<clipPath id="plot"><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/></clipPath>`+"\n", kiri, atas, kanan-kiri, bawah-atas)
+
+	// Pita warna KMS dan kurva SD
+	b.WriteString(`<g clip-path="url(#plot)">` + "\n")
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s"/>`+"\n", g.titikSVG(g.pita(i)), hex(warnaPita[i]))
+	}
+	b.WriteString("</g>\n")
+
+	// Garis bantu dan label sumbu
+	for _, v := range tanda(g.MinX, g.MaxX, g.LangkahX) {
+		x, _ := g.px(Titik{v, g.MinY})
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="0.5"/>`+"\n", x, atas, x, bawah, hex(warnaGrid))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="middle" fill="%s">%s</text>`+"\n", x, bawah+16, hex(warnaTeks), formatAngka(v))
+	}
+	for _, v := range tanda(g.MinY, g.MaxY, g.LangkahY) {
+		_, y := g.px(Titik{g.MinX, v})
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="0.5"/>`+"\n", kiri, y, kanan, y, hex(warnaGrid))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="end" fill="%s">%s</text>`+"\n", kiri-6, y+4, hex(warnaTeks), formatAngka(v))
+	}
+
+	b.WriteString(`<g clip-path="url(#plot)" fill="none">` + "\n")
+	for i, kurva := range g.Kurva {
+		lebarGaris := 1.2
+		if i == 3 || i == 0 {
+			lebarGaris = 2
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" stroke="%s" stroke-width="%.1f"/>`+"\n", g.titikSVG(kurva), hex(warnaKurva[i]), lebarGaris)
+	}
+	b.WriteString("</g>\n")
+	for i, kurva := range g.Kurva {
+		if len(kurva) == 0 {
+			continue
+		}
+		x, y := g.px(kurva[len(kurva)-1])
+		if y < atas || y > bawah {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="10" fill="%s">%s</text>`+"\n", x+4, y+3, hex(warnaKurva[i]), labelKurva[i])
+	}
+
+	fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="%s"/>`+"\n", kiri, atas, kanan-kiri, bawah-atas, hex(warnaSumbu))
+
+	// Titik pengukuran anak
+	if len(g.Anak) > 1 {
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", g.titikSVG(g.Anak), hex(warnaTitikAnak))
+	}
+	for _, t := range g.Anak {
+		x, y := g.px(t)
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="4" fill="%s" stroke="#FFFFFF" stroke-width="1"/>`+"\n", x, y, hex(warnaTitikAnak))
+	}
+
+	// Judul dan nama sumbu
+	fmt.Fprintf(&b, `<text x="%d" y="24" font-size="16" font-weight="bold" fill="%s">%s</text>`+"\n", marginKiri, hex(warnaTeks), escape(g.Judul))
+	fmt.Fprintf(&b, `<text x="%d" y="42" font-size="12" fill="%s">%s</text>`+"\n", marginKiri, hex(warnaTeks), escape(g.Subjudul))
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" font-size="12" text-anchor="middle" fill="%s">%s</text>`+"\n", (kiri+kanan)/2, Tinggi-12, hex(warnaTeks), escape(g.LabelX))
+	fmt.Fprintf(&b, `<text x="16" y="%.1f" font-size="12" text-anchor="middle" fill="%s" transform="rotate(-90 16 %.1f)">%s</text>`+"\n", (atas+bawah)/2, hex(warnaTeks), (atas+bawah)/2, escape(g.LabelY))
+
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
@@ -0,0 +1,40 @@
+package growth
+
+import "math"
+
+// BatasFaltering adalah penurunan z-score yang dianggap gagal tumbuh (growth faltering),
+// setara dengan memotong satu garis persentil utama pada kurva pertumbuhan.
+const BatasFaltering = 0.67
+
+// Faltering adalah satu episode gagal tumbuh pada deret z-score.
+// Indeks merujuk ke posisi pada deret yang diberikan ke DeteksiFaltering.
+type Faltering struct {
+	IndeksPuncak int     // Titik z-score tertinggi sebelum penurunan
+	IndeksMulai  int     // Titik pertama penurunan melewati BatasFaltering
+	ZPuncak      float64 // z-score pada titik puncak
+	Z            float64 // z-score pada titik mulai
+	Penurunan    float64 // ZPuncak - Z
+}
+
+// DeteksiFaltering mencari titik-titik saat z-score turun lebih dari BatasFaltering dari
+// puncak sebelumnya. Deret harus urut menurut waktu. Setelah satu episode terdeteksi,
+// puncak dihitung ulang dari titik tersebut sehingga penurunan lanjutan tercatat sebagai episode baru.
+func DeteksiFaltering(z []float64) []Faltering {
+	var hasil []Faltering
+	if len(z) == 0 {
+		return hasil
+	}
+	puncak := 0
+	for i := 1; i < len(z); i++ {
+		if z[i] > z[puncak] {
+			puncak = i
+			continue
+		}
+		turun := math.Round((z[puncak]-z[i])*100) / 100
+		if turun > BatasFaltering {
+			hasil = append(hasil, Faltering{IndeksPuncak: puncak, IndeksMulai: i, ZPuncak: z[puncak], Z: z[i], Penurunan: turun})
+			puncak = i
+		}
+	}
+	return hasil
+}
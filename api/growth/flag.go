@@ -0,0 +1,44 @@
+package growth
+
+import "fmt"
+
+// Batas flag WHO (WHO Anthro): z-score di luar batas ini kemungkinan besar akibat
+// kesalahan pengukuran atau pencatatan dan perlu diperiksa ulang.
+var batasFlag = map[Indikator][2]float64{
+	BBU:   {-6, 5},
+	TBU:   {-6, 6},
+	BBTB:  {-5, 5},
+	IMTU:  {-5, 5},
+	LKU:   {-5, 5},
+	LILAU: {-5, 5},
+}
+
+var namaIndikator = map[Indikator]string{
+	BBU:   "BB/U",
+	TBU:   "PB/U atau TB/U",
+	BBTB:  "BB/PB atau BB/TB",
+	IMTU:  "IMT/U",
+	LKU:   "LK/U",
+	LILAU: "LILA/U",
+}
+
+// PeriksaFlag mengembalikan peringatan untuk setiap z-score yang melewati batas flag WHO
+func PeriksaFlag(h Hasil) []string {
+	var peringatan []string
+	periksa := func(ind Indikator, z *float64) {
+		if z == nil {
+			return
+		}
+		batas := batasFlag[ind]
+		if *z < batas[0] || *z > batas[1] {
+			peringatan = append(peringatan, fmt.Sprintf("Z-score %s %.2f di luar batas wajar WHO (%.0f s.d. +%.0f).", namaIndikator[ind], *z, batas[0], batas[1]))
+		}
+	}
+	periksa(BBU, h.ZBBU)
+	periksa(TBU, h.ZTBU)
+	periksa(BBTB, h.ZBBTB)
+	periksa(IMTU, h.ZIMTU)
+	periksa(LKU, h.ZLKU)
+	periksa(LILAU, h.ZLILAU)
+	return peringatan
+}
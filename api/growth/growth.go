@@ -0,0 +1,304 @@
+// Package growth menghitung z-score antropometri anak 0-60 bulan berdasarkan
+// WHO Child Growth Standards 2006 (metode LMS) dan mengklasifikasikannya
+// sesuai Permenkes No. 2 Tahun 2020 tentang Standar Antropometri Anak.
+package growth
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Indikator antropometri yang didukung
+type Indikator string
+
+const (
+	BBU   Indikator = "bbu"   // Berat badan menurut umur (weight-for-age)
+	TBU   Indikator = "tbu"   // Panjang/tinggi badan menurut umur (length/height-for-age)
+	BBTB  Indikator = "bbtb"  // Berat badan menurut panjang/tinggi badan (weight-for-length/height)
+	IMTU  Indikator = "imtu"  // Indeks massa tubuh menurut umur (BMI-for-age)
+	LKU   Indikator = "lku"   // Lingkar kepala menurut umur (head circumference-for-age)
+	LILAU Indikator = "lilau" // Lingkar lengan atas menurut umur (MUAC-for-age)
+)
+
+const (
+	// HariPerBulan adalah panjang rata-rata satu bulan yang dipakai WHO untuk konversi umur
+	HariPerBulan = 30.4375
+	// UsiaMaksHari adalah batas umur tabel WHO 0-5 tahun (60 bulan)
+	UsiaMaksHari = 1856
+	// batasPanjangHari adalah umur (24 bulan) mulai anak diukur berdiri dan dipakai tabel tinggi badan
+	batasPanjangHari = 731
+)
+
+var (
+	ErrJenisKelamin  = errors.New("jenis kelamin harus L atau P")
+	ErrDiLuarRentang = errors.New("nilai di luar rentang tabel WHO")
+	// ErrTabelBelumTersedia dikembalikan untuk indikator yang tabel resmi WHO-nya belum disertakan
+	ErrTabelBelumTersedia = errors.New("tabel resmi WHO untuk indikator ini belum tersedia")
+)
+
+// LMS adalah parameter Box-Cox (lambda, median, koefisien variasi) pada satu titik tabel
+type LMS struct {
+	X float64 // Umur dalam bulan, atau panjang/tinggi badan dalam cm
+	L float64
+	M float64
+	S float64
+}
+
+// Tabel WHO disimpan dalam format berkas z-score resmi WHO (kolom pertama Month/Day/Length/Height,
+// lalu L, M, S dan kolom lain yang diabaikan), sehingga berkas dapat diganti langsung dengan
+// unduhan dari who.int tanpa disunting. Baris tidak harus berjarak sama; nilai di antara dua
+// baris diinterpolasi linear. Tabel BB/PB-TB (wfl 45-110 cm, wfh 65-120 cm) dan LILA/U (acfa)
+// belum disertakan; indikator tersebut mengembalikan ErrTabelBelumTersedia sampai berkas resmi
+// WHO ditambahkan ke data/ dan dipetakan di namaTabel.
+//
+//go:embed data/*.txt
+var dataFS embed.FS
+
+var (
+	muatTabel  sync.Once
+	tabel      map[string][]LMS
+	errMuatTbl error
+)
+
+// namaTabel memetakan indikator, jenis kelamin dan umur ke nama berkas tabel
+func namaTabel(ind Indikator, jenisKelamin string, usiaHari int) (string, error) {
+	var jk string
+	switch jenisKelamin {
+	case "L":
+		jk = "boys"
+	case "P":
+		jk = "girls"
+	default:
+		return "", ErrJenisKelamin
+	}
+	bawah2Tahun := usiaHari < batasPanjangHari
+
+	switch ind {
+	case BBU:
+		return "wfa_" + jk + "_0_5", nil
+	case TBU:
+		if bawah2Tahun {
+			return "lhfa_" + jk + "_0_2", nil
+		}
+		return "lhfa_" + jk + "_2_5", nil
+	case BBTB, LILAU:
+		return "", ErrTabelBelumTersedia
+	case IMTU:
+		if bawah2Tahun {
+			return "bfa_" + jk + "_0_2", nil
+		}
+		return "bfa_" + jk + "_2_5", nil
+	case LKU:
+		return "hcfa_" + jk + "_0_5", nil
+	}
+	return "", fmt.Errorf("indikator %q tidak dikenal", ind)
+}
+
+// ambilTabel mengembalikan baris LMS sebuah berkas tabel, memuat seluruh tabel saat pertama dipanggil
+func ambilTabel(nama string) ([]LMS, error) {
+	muatTabel.Do(func() {
+		tabel, errMuatTbl = bacaSemuaTabel()
+	})
+	if errMuatTbl != nil {
+		return nil, errMuatTbl
+	}
+	baris, ok := tabel[nama]
+	if !ok {
+		return nil, fmt.Errorf("tabel WHO %s tidak tersedia", nama)
+	}
+	return baris, nil
+}
+
+func bacaSemuaTabel() (map[string][]LMS, error) {
+	entries, err := dataFS.ReadDir("data")
+	if err != nil {
+		return nil, err
+	}
+	hasil := make(map[string][]LMS, len(entries))
+	for _, e := range entries {
+		f, err := dataFS.Open("data/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		baris, err := parseTabel(bufio.NewScanner(f))
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("tabel %s: %w", e.Name(), err)
+		}
+		hasil[strings.TrimSuffix(e.Name(), ".txt")] = baris
+	}
+	return hasil, nil
+}
+
+// parseTabel membaca berkas tabel berformat WHO (dipisah tab/spasi, baris pertama header).
+// Kolom L, M dan S dicari dari judulnya sehingga kolom SD atau persentil tambahan pada berkas
+// WHO diabaikan. Kolom pertama adalah titik tabel; judul Day (tabel WHO yang diperluas per hari)
+// diubah ke bulan agar sama dengan tabel bulanan.
+func parseTabel(sc *bufio.Scanner) ([]LMS, error) {
+	var baris []LMS
+	var header []string
+	indeks := map[string]int{}
+	for sc.Scan() {
+		kolom := strings.Fields(sc.Text())
+		if len(kolom) == 0 {
+			continue
+		}
+		if header == nil {
+			header = kolom
+			for i, judul := range kolom {
+				indeks[strings.ToUpper(judul)] = i
+			}
+			for _, judul := range []string{"L", "M", "S"} {
+				if i, ok := indeks[judul]; !ok || i == 0 {
+					return nil, fmt.Errorf("kolom %s tidak ada pada header %q", judul, sc.Text())
+				}
+			}
+			continue
+		}
+		if len(kolom) < len(header) {
+			return nil, fmt.Errorf("baris %q tidak lengkap", sc.Text())
+		}
+		var nilai [4]float64
+		for i, k := range [4]int{0, indeks["L"], indeks["M"], indeks["S"]} {
+			v, err := strconv.ParseFloat(kolom[k], 64)
+			if err != nil {
+				return nil, fmt.Errorf("baris %q: %w", sc.Text(), err)
+			}
+			nilai[i] = v
+		}
+		if strings.EqualFold(header[0], "Day") {
+			nilai[0] /= HariPerBulan
+		}
+		baris = append(baris, LMS{X: nilai[0], L: nilai[1], M: nilai[2], S: nilai[3]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(baris, func(i, j int) bool { return baris[i].X < baris[j].X })
+	return baris, nil
+}
+
+// interpolasi mencari parameter LMS pada titik x dengan interpolasi linear antar baris tabel
+func interpolasi(baris []LMS, x float64) (LMS, error) {
+	if len(baris) == 0 || x < baris[0].X || x > baris[len(baris)-1].X {
+		return LMS{}, ErrDiLuarRentang
+	}
+	i := sort.Search(len(baris), func(i int) bool { return baris[i].X >= x })
+	if baris[i].X == x || i == 0 {
+		return baris[i], nil
+	}
+	a, b := baris[i-1], baris[i]
+	t := (x - a.X) / (b.X - a.X)
+	return LMS{
+		X: x,
+		L: a.L + t*(b.L-a.L),
+		M: a.M + t*(b.M-a.M),
+		S: a.S + t*(b.S-a.S),
+	}, nil
+}
+
+// ParameterLMS mengembalikan parameter LMS sebuah indikator. Untuk BBTB, x adalah
+// panjang/tinggi badan (cm); untuk indikator lain x diabaikan dan umur yang dipakai.
+func ParameterLMS(ind Indikator, jenisKelamin string, usiaHari int, x float64) (LMS, error) {
+	if usiaHari < 0 || usiaHari > UsiaMaksHari {
+		return LMS{}, ErrDiLuarRentang
+	}
+	nama, err := namaTabel(ind, jenisKelamin, usiaHari)
+	if err != nil {
+		return LMS{}, err
+	}
+	baris, err := ambilTabel(nama)
+	if err != nil {
+		return LMS{}, err
+	}
+	if ind != BBTB {
+		x = float64(usiaHari) / HariPerBulan
+	}
+	return interpolasi(baris, x)
+}
+
+// ZScore menghitung z-score sebuah pengukuran. Untuk BBTB, tbCm wajib diisi.
+func ZScore(ind Indikator, jenisKelamin string, usiaHari int, tbCm, nilai float64) (float64, error) {
+	if nilai <= 0 {
+		return 0, ErrDiLuarRentang
+	}
+	lms, err := ParameterLMS(ind, jenisKelamin, usiaHari, tbCm)
+	if err != nil {
+		return 0, err
+	}
+	z := lms.Z(nilai)
+	// WHO membatasi ekor distribusi indikator berbasis berat/lingkar lengan (restricted LMS)
+	if ind != TBU && ind != LKU && math.Abs(z) > 3 {
+		z = lms.zTerbatas(nilai, z)
+	}
+	z = math.Round(z*100) / 100
+	if z == 0 {
+		z = 0 // hindari -0 pada JSON
+	}
+	return z, nil
+}
+
+// Z menghitung z-score LMS tanpa pembatasan ekor
+func (p LMS) Z(y float64) float64 {
+	if p.L == 0 {
+		return math.Log(y/p.M) / p.S
+	}
+	return (math.Pow(y/p.M, p.L) - 1) / (p.L * p.S)
+}
+
+// Nilai mengembalikan ukuran pada z-score tertentu (kebalikan dari Z)
+func (p LMS) Nilai(z float64) float64 {
+	if p.L == 0 {
+		return p.M * math.Exp(p.S*z)
+	}
+	return p.M * math.Pow(1+p.L*p.S*z, 1/p.L)
+}
+
+// zTerbatas menerapkan penyesuaian WHO untuk |z| > 3: jarak di luar ±3 SD diukur
+// dengan lebar pita antara 2 dan 3 SD, bukan dengan kurva LMS yang makin melengkung.
+func (p LMS) zTerbatas(y, z float64) float64 {
+	if z > 3 {
+		sd3 := p.Nilai(3)
+		return 3 + (y-sd3)/(sd3-p.Nilai(2))
+	}
+	sd3 := p.Nilai(-3)
+	return -3 + (y-sd3)/(p.Nilai(-2)-sd3)
+}
+
+// Cara pengukuran panjang/tinggi badan
+const (
+	CaraTelentang = "telentang" // Panjang badan (recumbent length)
+	CaraBerdiri   = "berdiri"   // Tinggi badan (standing height)
+)
+
+// SelisihTelentangBerdiriCm adalah selisih rata-rata panjang telentang terhadap tinggi berdiri menurut WHO
+const SelisihTelentangBerdiriCm = 0.7
+
+// SesuaikanPanjang mengoreksi hasil ukur ke cara yang diasumsikan tabel WHO: telentang di bawah
+// 24 bulan dan berdiri sejak 24 bulan. Anak < 24 bulan yang diukur berdiri ditambah 0,7 cm,
+// anak >= 24 bulan yang diukur telentang dikurangi 0,7 cm. caraUkur kosong dianggap sudah sesuai.
+func SesuaikanPanjang(tbCm float64, usiaHari int, caraUkur string) float64 {
+	switch {
+	case usiaHari < batasPanjangHari && caraUkur == CaraBerdiri:
+		return tbCm + SelisihTelentangBerdiriCm
+	case usiaHari >= batasPanjangHari && caraUkur == CaraTelentang:
+		return tbCm - SelisihTelentangBerdiriCm
+	}
+	return tbCm
+}
+
+// PanjangTelentang mengubah hasil ukur ke panjang setara telentang pada semua umur, dipakai untuk
+// membandingkan dua pengukuran yang melintasi umur 24 bulan. caraUkur kosong dianggap sesuai umur.
+func PanjangTelentang(tbCm float64, usiaHari int, caraUkur string) float64 {
+	if caraUkur == CaraBerdiri || (caraUkur == "" && usiaHari >= batasPanjangHari) {
+		return tbCm + SelisihTelentangBerdiriCm
+	}
+	return tbCm
+}
@@ -0,0 +1,190 @@
+package growth
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// Nilai acuan diambil dari tabel simpangan baku (SD) WHO Child Growth Standards 2006 yang dibulatkan
+// ke 0,1 kg atau 0,1 cm, sehingga z-score hasil hitung boleh meleset sedikit dari angka SD bulatnya.
+func TestZScoreTabelSDWHO(t *testing.T) {
+	const toleransi = 0.15
+	kasus := []struct {
+		nama         string
+		ind          Indikator
+		jenisKelamin string
+		usiaHari     int
+		nilai        float64
+		z            float64
+	}{
+		{"BB/U laki-laki lahir median", BBU, "L", 0, 3.3, 0},
+		{"BB/U laki-laki lahir -2 SD", BBU, "L", 0, 2.5, -2},
+		{"BB/U laki-laki lahir +2 SD", BBU, "L", 0, 4.4, 2},
+		{"BB/U perempuan lahir -2 SD", BBU, "P", 0, 2.4, -2},
+		{"BB/U perempuan lahir +2 SD", BBU, "P", 0, 4.2, 2},
+		{"BB/U laki-laki 12 bulan -2 SD", BBU, "L", 365, 7.7, -2},
+		{"BB/U laki-laki 12 bulan median", BBU, "L", 365, 9.6, 0},
+		{"PB/U laki-laki lahir -2 SD", TBU, "L", 0, 46.1, -2},
+		{"PB/U laki-laki lahir median", TBU, "L", 0, 49.9, 0},
+		{"PB/U laki-laki lahir +2 SD", TBU, "L", 0, 53.7, 2},
+		{"PB/U perempuan lahir -2 SD", TBU, "P", 0, 45.4, -2},
+		{"PB/U perempuan lahir -3 SD", TBU, "P", 0, 43.6, -3},
+		{"LK/U laki-laki lahir -2 SD", LKU, "L", 0, 31.9, -2},
+		{"LK/U laki-laki lahir +2 SD", LKU, "L", 0, 36.9, 2},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			z, err := ZScore(k.ind, k.jenisKelamin, k.usiaHari, 0, k.nilai)
+			if err != nil {
+				t.Fatalf("ZScore: %v", err)
+			}
+			if math.Abs(z-k.z) > toleransi {
+				t.Errorf("ZScore(%s, %s, %d, %.1f) = %.2f, ingin %.0f ± %.1f", k.ind, k.jenisKelamin, k.usiaHari, k.nilai, z, k.z, toleransi)
+			}
+		})
+	}
+}
+
+// Di luar ±3 SD, BB/U memakai restricted LMS WHO: jarak dari 3 SD diukur dengan lebar pita 2-3 SD.
+// Parameter LMS di bawah adalah baris bulan 0 tabel wfa_boys WHO.
+func TestZScoreEkorTerbatas(t *testing.T) {
+	const l, m, s = 0.3487, 3.3464, 0.14602
+	sd := func(z float64) float64 { return m * math.Pow(1+l*s*z, 1/l) }
+	kasus := []struct {
+		nama  string
+		nilai float64
+		z     float64
+	}{
+		{"di bawah -3 SD", 1.8, -3 + (1.8-sd(-3))/(sd(-2)-sd(-3))},
+		{"di atas +3 SD", 5.6, 3 + (5.6-sd(3))/(sd(3)-sd(2))},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			z, err := ZScore(BBU, "L", 0, 0, k.nilai)
+			if err != nil {
+				t.Fatalf("ZScore: %v", err)
+			}
+			if ingin := math.Round(k.z*100) / 100; z != ingin {
+				t.Errorf("ZScore(bbu, L, 0, %.1f) = %.2f, ingin %.2f", k.nilai, z, ingin)
+			}
+		})
+	}
+}
+
+func TestZScoreGalat(t *testing.T) {
+	kasus := []struct {
+		nama         string
+		ind          Indikator
+		jenisKelamin string
+		usiaHari     int
+		nilai        float64
+		galat        error
+	}{
+		{"jenis kelamin tidak dikenal", BBU, "X", 0, 3.3, ErrJenisKelamin},
+		{"nilai nol", BBU, "L", 0, 0, ErrDiLuarRentang},
+		{"umur di atas 60 bulan", BBU, "L", UsiaMaksHari + 30, 18, ErrDiLuarRentang},
+		{"BB/TB belum tersedia", BBTB, "L", 400, 9, ErrTabelBelumTersedia},
+		{"LILA/U belum tersedia", LILAU, "P", 400, 14, ErrTabelBelumTersedia},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			tb := 0.0
+			if k.ind == BBTB {
+				tb = 75
+			}
+			if _, err := ZScore(k.ind, k.jenisKelamin, k.usiaHari, tb, k.nilai); !errors.Is(err, k.galat) {
+				t.Errorf("galat = %v, ingin %v", err, k.galat)
+			}
+		})
+	}
+}
+
+func TestHitungStatusGiziDariIMTU(t *testing.T) {
+	bb, tb := 9.6, 75.7
+	h := Hitung(Pengukuran{JenisKelamin: "L", UsiaHari: 365, BbKg: &bb, TbCm: &tb})
+	if h.ZBBTB != nil || h.StatusBBTB != nil || h.ZLILAU != nil {
+		t.Errorf("BB/TB dan LILA/U harus kosong selama tabel resmi belum tersedia")
+	}
+	if h.StatusIMTU == nil || h.StatusGizi == nil || *h.StatusGizi != *h.StatusIMTU {
+		t.Fatalf("StatusGizi = %v, ingin sama dengan StatusIMTU %v", h.StatusGizi, h.StatusIMTU)
+	}
+	if *h.StatusGizi != "Gizi baik" {
+		t.Errorf("StatusGizi = %q, ingin %q", *h.StatusGizi, "Gizi baik")
+	}
+}
+
+func TestKategoriBatas(t *testing.T) {
+	kasus := []struct {
+		nama     string
+		kategori func(float64) string
+		z        float64
+		ingin    string
+	}{
+		{"BB/U tepat -3", KategoriBBU, -3, "Berat badan kurang"},
+		{"BB/U di bawah -3", KategoriBBU, -3.01, "Berat badan sangat kurang"},
+		{"BB/U tepat +1", KategoriBBU, 1, "Berat badan normal"},
+		{"TB/U tepat -2", KategoriTBU, -2, "Normal"},
+		{"TB/U di bawah -2", KategoriTBU, -2.01, "Pendek"},
+		{"TB/U di atas +3", KategoriTBU, 3.01, "Tinggi"},
+		{"IMT/U tepat +2", KategoriIMTU, 2, "Berisiko gizi lebih"},
+		{"IMT/U di atas +3", KategoriIMTU, 3.01, "Obesitas"},
+		{"LK/U di bawah -2", KategoriLKU, -2.01, "Mikrosefali"},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			if got := k.kategori(k.z); got != k.ingin {
+				t.Errorf("kategori(%.2f) = %q, ingin %q", k.z, got, k.ingin)
+			}
+		})
+	}
+}
+
+func TestPanjangTelentang(t *testing.T) {
+	kasus := []struct {
+		nama     string
+		tbCm     float64
+		usiaHari int
+		cara     string
+		ingin    float64
+	}{
+		{"telentang di bawah 24 bulan", 80, 600, CaraTelentang, 80},
+		{"berdiri di bawah 24 bulan", 80, 600, CaraBerdiri, 80.7},
+		{"berdiri sejak 24 bulan", 88, 800, CaraBerdiri, 88.7},
+		{"standar sejak 24 bulan", 88, 800, "", 88.7},
+		{"standar di bawah 24 bulan", 80, 600, "", 80},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			if got := PanjangTelentang(k.tbCm, k.usiaHari, k.cara); math.Abs(got-k.ingin) > 1e-9 {
+				t.Errorf("PanjangTelentang = %.2f, ingin %.2f", got, k.ingin)
+			}
+		})
+	}
+}
+
+func TestDeteksiFaltering(t *testing.T) {
+	kasus := []struct {
+		nama  string
+		z     []float64
+		mulai []int
+	}{
+		{"stabil", []float64{0.1, 0, -0.2, 0.1}, nil},
+		{"turun tepat batas", []float64{0.5, -0.17}, nil},
+		{"turun melewati batas", []float64{0.5, 0.2, -0.3}, []int{2}},
+		{"dua episode", []float64{1, 0.2, 0.1, -0.6}, []int{1, 3}},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			hasil := DeteksiFaltering(k.z)
+			if len(hasil) != len(k.mulai) {
+				t.Fatalf("jumlah episode = %d, ingin %d (%+v)", len(hasil), len(k.mulai), hasil)
+			}
+			for i, f := range hasil {
+				if f.IndeksMulai != k.mulai[i] {
+					t.Errorf("episode %d mulai di %d, ingin %d", i, f.IndeksMulai, k.mulai[i])
+				}
+			}
+		})
+	}
+}
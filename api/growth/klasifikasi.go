@@ -0,0 +1,126 @@
+package growth
+
+// Kategori status gizi menurut Permenkes No. 2 Tahun 2020 (anak 0-60 bulan).
+// Batas bawah setiap kategori inklusif kecuali disebut lain di fungsi masing-masing.
+
+// KategoriBBU mengklasifikasikan z-score berat badan menurut umur
+func KategoriBBU(z float64) string {
+	switch {
+	case z < -3:
+		return "Berat badan sangat kurang"
+	case z < -2:
+		return "Berat badan kurang"
+	case z <= 1:
+		return "Berat badan normal"
+	default:
+		return "Risiko berat badan lebih"
+	}
+}
+
+// KategoriTBU mengklasifikasikan z-score panjang/tinggi badan menurut umur
+func KategoriTBU(z float64) string {
+	switch {
+	case z < -3:
+		return "Sangat pendek"
+	case z < -2:
+		return "Pendek"
+	case z <= 3:
+		return "Normal"
+	default:
+		return "Tinggi"
+	}
+}
+
+// KategoriBBTB mengklasifikasikan z-score berat badan menurut panjang/tinggi badan.
+// IMT/U pada anak 0-60 bulan memakai kategori yang sama.
+func KategoriBBTB(z float64) string {
+	switch {
+	case z < -3:
+		return "Gizi buruk"
+	case z < -2:
+		return "Gizi kurang"
+	case z <= 1:
+		return "Gizi baik"
+	case z <= 2:
+		return "Berisiko gizi lebih"
+	case z <= 3:
+		return "Gizi lebih"
+	default:
+		return "Obesitas"
+	}
+}
+
+// KategoriIMTU mengklasifikasikan z-score indeks massa tubuh menurut umur
+func KategoriIMTU(z float64) string {
+	return KategoriBBTB(z)
+}
+
+// KategoriLKU mengklasifikasikan z-score lingkar kepala menurut umur (±2 SD, pedoman SDIDTK)
+func KategoriLKU(z float64) string {
+	switch {
+	case z < -2:
+		return "Mikrosefali"
+	case z > 2:
+		return "Makrosefali"
+	default:
+		return "Normal"
+	}
+}
+
+// Pengukuran adalah data satu kali penimbangan/pengukuran anak
+type Pengukuran struct {
+	JenisKelamin string // "L" atau "P"
+	UsiaHari     int
+	BbKg         *float64
+	TbCm         *float64
+	LkCm         *float64
+	LilaCm       *float64
+}
+
+// Hasil memuat z-score dan kategori setiap indikator (nil jika tidak dapat dihitung)
+type Hasil struct {
+	ZBBU       *float64
+	ZTBU       *float64
+	ZBBTB      *float64 // Selalu nil sampai tabel resmi WHO BB/PB-TB disertakan
+	ZIMTU      *float64
+	ZLKU       *float64
+	ZLILAU     *float64 // Selalu nil sampai tabel resmi WHO LILA/U disertakan
+	StatusBBU  *string
+	StatusTBU  *string
+	StatusBBTB *string
+	StatusIMTU *string
+	StatusLKU  *string
+	StatusGizi *string // Status gizi utama: IMT/U (kategori sama dengan BB/PB-TB pada anak 0-60 bulan)
+}
+
+// Hitung menghitung seluruh indikator yang datanya tersedia.
+// Indikator yang ukurannya kosong atau di luar rentang tabel dibiarkan nil, begitu pula BB/PB-TB
+// dan LILA/U yang tabel resminya belum disertakan (lihat ErrTabelBelumTersedia).
+func Hitung(p Pengukuran) Hasil {
+	var h Hasil
+	hitung := func(ind Indikator, nilai float64, kategori func(float64) string) (*float64, *string) {
+		z, err := ZScore(ind, p.JenisKelamin, p.UsiaHari, 0, nilai)
+		if err != nil {
+			return nil, nil
+		}
+		k := kategori(z)
+		return &z, &k
+	}
+
+	if p.BbKg != nil {
+		h.ZBBU, h.StatusBBU = hitung(BBU, *p.BbKg, KategoriBBU)
+	}
+	if p.TbCm != nil {
+		h.ZTBU, h.StatusTBU = hitung(TBU, *p.TbCm, KategoriTBU)
+	}
+	if p.BbKg != nil && p.TbCm != nil {
+		imt := *p.BbKg / (*p.TbCm / 100 * *p.TbCm / 100)
+		h.ZIMTU, h.StatusIMTU = hitung(IMTU, imt, KategoriIMTU)
+	}
+	if p.LkCm != nil {
+		h.ZLKU, h.StatusLKU = hitung(LKU, *p.LkCm, KategoriLKU)
+	}
+
+	h.StatusGizi = h.StatusIMTU
+	return h
+}
@@ -0,0 +1,77 @@
+package growth
+
+import "math"
+
+// Evaluasi penimbangan KMS (Kartu Menuju Sehat) sesuai Permenkes No. 155 Tahun 2010.
+
+// Status penimbangan pada KMS
+const (
+	KMSNaik           = "N" // Naik: kenaikan berat badan >= kenaikan berat badan minimal (KBM)
+	KMSTidakNaik      = "T" // Tidak naik: kenaikan berat badan < KBM
+	KMSTidakDitimbang = "O" // Bulan lalu tidak ditimbang
+	KMSBaru           = "B" // Baru pertama kali ditimbang
+)
+
+// BatasBGM adalah z-score BB/U garis merah KMS; di bawahnya anak berstatus BGM
+const BatasBGM = -3.0
+
+// tabelKBM berisi kenaikan berat badan minimal (gram) per bulan untuk umur 1-11 bulan
+var tabelKBM = [...]int{1: 800, 2: 900, 3: 800, 4: 600, 5: 500, 6: 400, 7: 400, 8: 300, 9: 300, 10: 300, 11: 200}
+
+// KBMGram mengembalikan kenaikan berat badan minimal (gram) untuk umur dalam bulan penuh.
+// Umur 12-60 bulan memakai KBM 200 gram.
+func KBMGram(usiaBulan int) int {
+	if usiaBulan < 1 {
+		usiaBulan = 1
+	}
+	if usiaBulan < len(tabelKBM) {
+		return tabelKBM[usiaBulan]
+	}
+	return 200
+}
+
+// PenimbanganKMS adalah data satu penimbangan yang dievaluasi
+type PenimbanganKMS struct {
+	Tahun, Bulan int // Bulan penimbangan (bulan kalender)
+	UsiaHari     int
+	BbKg         float64
+	ZBBU         *float64
+}
+
+// HasilKMS adalah hasil evaluasi satu penimbangan
+type HasilKMS struct {
+	Status       string
+	DuaT         bool // T dua kali berturut-turut
+	BGM          bool // Bawah garis merah
+	KenaikanGram *int // Kenaikan berat badan sejak penimbangan sebelumnya
+	KBMGram      *int // Kenaikan minimal yang disyaratkan
+}
+
+// EvaluasiKMS menilai penimbangan saat ini terhadap penimbangan sebelumnya.
+// sebelumnya nil untuk penimbangan pertama; hasilSebelumnya dipakai untuk menilai 2T.
+func EvaluasiKMS(kini PenimbanganKMS, sebelumnya *PenimbanganKMS, hasilSebelumnya *HasilKMS) HasilKMS {
+	var h HasilKMS
+	h.BGM = kini.ZBBU != nil && *kini.ZBBU < BatasBGM
+
+	if sebelumnya == nil {
+		h.Status = KMSBaru
+		return h
+	}
+	selisihBulan := (kini.Tahun*12 + kini.Bulan) - (sebelumnya.Tahun*12 + sebelumnya.Bulan)
+	if selisihBulan > 1 {
+		h.Status = KMSTidakDitimbang
+		return h
+	}
+
+	kenaikan := int(math.Round((kini.BbKg - sebelumnya.BbKg) * 1000))
+	kbm := KBMGram(int(float64(kini.UsiaHari) / HariPerBulan))
+	h.KenaikanGram = &kenaikan
+	h.KBMGram = &kbm
+	if kenaikan >= kbm {
+		h.Status = KMSNaik
+	} else {
+		h.Status = KMSTidakNaik
+		h.DuaT = hasilSebelumnya != nil && hasilSebelumnya.Status == KMSTidakNaik
+	}
+	return h
+}
@@ -0,0 +1,32 @@
+package growth
+
+// Koreksi umur bayi prematur: selama 24 bulan pertama pertumbuhan dinilai pada umur
+// koreksi, yaitu umur kronologis dikurangi kekurangan usia kehamilan dari 40 minggu.
+const (
+	// MingguAterm adalah usia kehamilan cukup bulan yang menjadi acuan koreksi
+	MingguAterm = 40
+	// MingguPrematur adalah batas usia kehamilan lahir yang dikoreksi (< 37 minggu)
+	MingguPrematur = 37
+	// batasKoreksiHari adalah umur kronologis (24 bulan) sampai koreksi berlaku
+	batasKoreksiHari = batasPanjangHari
+)
+
+// UsiaKoreksiHari mengembalikan umur koreksi dalam hari, atau nil bila koreksi tidak berlaku
+// (usia kehamilan tidak diketahui, bayi lahir cukup bulan, atau umur kronologis sudah 24 bulan).
+// Umur koreksi sebelum hari perkiraan lahir dibatasi nol.
+func UsiaKoreksiHari(usiaHari int, usiaKehamilanLahirMinggu *int) *int {
+	if usiaKehamilanLahirMinggu == nil || *usiaKehamilanLahirMinggu >= MingguPrematur || usiaHari >= batasKoreksiHari {
+		return nil
+	}
+	koreksi := max(usiaHari-(MingguAterm-*usiaKehamilanLahirMinggu)*7, 0)
+	return &koreksi
+}
+
+// UsiaPenilaianHari mengembalikan umur yang dipakai untuk menilai pertumbuhan:
+// umur koreksi bila berlaku, selain itu umur kronologis
+func UsiaPenilaianHari(usiaHari int, usiaKehamilanLahirMinggu *int) int {
+	if koreksi := UsiaKoreksiHari(usiaHari, usiaKehamilanLahirMinggu); koreksi != nil {
+		return *koreksi
+	}
+	return usiaHari
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +19,13 @@ import (
 // TambahAnakHandler menangani penambahan data anak baru
 func TambahAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
 		var payload models.TambahAnakPayload
 		if err := c.ShouldBindJSON(&payload); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
@@ -35,8 +43,8 @@ func TambahAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		_, err = dbpool.Exec(context.Background(),
-			`INSERT INTO anak (id_ibu, nama_anak, nik_anak, tanggal_lahir, jenis_kelamin, anak_ke, berat_lahir_kg, tinggi_lahir_cm) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-			payload.IdIbu, payload.NamaAnak, payload.NikAnak, tglLahir, payload.JenisKelamin, payload.AnakKe, payload.BeratLahirKg, payload.TinggiLahirCm)
+			`INSERT INTO anak (id_ibu, nama_anak, nik_anak, tanggal_lahir, jenis_kelamin, anak_ke, berat_lahir_kg, tinggi_lahir_cm, id_posyandu, punya_kms, usia_kehamilan_lahir_minggu) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT id_posyandu FROM kader WHERE id = $9), COALESCE($10, TRUE), $11)`,
+			payload.IdIbu, payload.NamaAnak, payload.NikAnak, tglLahir, payload.JenisKelamin, payload.AnakKe, payload.BeratLahirKg, payload.TinggiLahirCm, kaderId, payload.PunyaKms, payload.UsiaKehamilanLahirMinggu)
 
 		if err != nil {
 			log.Printf("ERROR inserting anak: %v", err)
@@ -67,15 +75,23 @@ func GetAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		var daftarAnak []models.Anak
 		searchQuery := c.Query("search")
 		// Pastikan JOIN ke ibu sudah ada
-		baseQuery := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id`
+		baseQuery := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.status, a.status_tanggal, a.status_alasan, a.id_posyandu, ps.nama AS nama_posyandu, a.punya_kms, a.usia_kehamilan_lahir_minggu, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id LEFT JOIN posyandu ps ON a.id_posyandu = ps.id`
 		var args []interface{}
+		var conditions []string
 		query := baseQuery
 
 		if searchQuery != "" {
 			// Tambahkan i.nik ILIKE $1 ke pencarian
-			query += " WHERE a.nama_anak ILIKE $1 OR a.nik_anak ILIKE $1 OR i.nama_lengkap ILIKE $1 OR i.nik ILIKE $1"
+			conditions = append(conditions, "(a.nama_anak ILIKE $1 OR a.nik_anak ILIKE $1 OR i.nama_lengkap ILIKE $1 OR i.nik ILIKE $1)")
 			args = append(args, fmt.Sprintf("%%%s%%", searchQuery))
 		}
+		if !sertakanNonaktif(c) {
+			conditions = append(conditions, kondisiAnakAktif)
+		}
+
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
 		query += " ORDER BY a.nama_anak ASC"
 
 		rows, err := dbpool.Query(context.Background(), query, args...)
@@ -89,11 +105,12 @@ func GetAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		for rows.Next() {
 			var a models.Anak
 			// Scan tetap sama, karena kita tidak menambahkan nik_ibu di list
-			if err := rows.Scan(&a.ID, &a.IdIbu, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.JenisKelamin, &a.AnakKe, &a.BeratLahirKg, &a.TinggiLahirCm, &a.CreatedAt, &a.UpdatedAt, &a.NamaIbu); err != nil {
+			if err := rows.Scan(&a.ID, &a.IdIbu, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.JenisKelamin, &a.AnakKe, &a.BeratLahirKg, &a.TinggiLahirCm, &a.Status, &a.StatusTanggal, &a.StatusAlasan, &a.IdPosyandu, &a.NamaPosyandu, &a.PunyaKms, &a.UsiaKehamilanLahirMinggu, &a.CreatedAt, &a.UpdatedAt, &a.NamaIbu); err != nil {
 				log.Printf("ERROR scanning anak row (all): %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data anak."})
 				return
 			}
+			a.UsiaHari, a.UsiaKoreksiHari = usiaKronologisDanKoreksi(a.TanggalLahir, a.UsiaKehamilanLahirMinggu, time.Now())
 			daftarAnak = append(daftarAnak, a)
 		}
 
@@ -110,7 +127,11 @@ func GetAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 func GetAnakSimpleHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var daftarAnak []models.AnakSimple
-		query := `SELECT id, nama_anak, nik_anak FROM anak ORDER BY nama_anak ASC`
+		query := `SELECT a.id, a.nama_anak, a.nik_anak FROM anak a`
+		if !sertakanNonaktif(c) {
+			query += " WHERE " + kondisiAnakAktif
+		}
+		query += " ORDER BY a.nama_anak ASC"
 		rows, err := dbpool.Query(context.Background(), query)
 		if err != nil {
 			log.Printf("ERROR querying anak (simple): %v", err)
@@ -150,10 +171,10 @@ func GetAnakByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		var anak models.Anak
 		// Perbarui query untuk menyertakan i.nik AS nik_ibu
-		query := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu, i.nik AS nik_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id WHERE a.id = $1`
+		query := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.status, a.status_tanggal, a.status_alasan, a.id_posyandu, ps.nama AS nama_posyandu, a.punya_kms, a.usia_kehamilan_lahir_minggu, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu, i.nik AS nik_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id LEFT JOIN posyandu ps ON a.id_posyandu = ps.id WHERE a.id = $1`
 		err = dbpool.QueryRow(context.Background(), query, id).
 			// Perbarui Scan untuk menyertakan &anak.NikIbu
-			Scan(&anak.ID, &anak.IdIbu, &anak.NamaAnak, &anak.NikAnak, &anak.TanggalLahir, &anak.JenisKelamin, &anak.AnakKe, &anak.BeratLahirKg, &anak.TinggiLahirCm, &anak.CreatedAt, &anak.UpdatedAt, &anak.NamaIbu, &anak.NikIbu)
+			Scan(&anak.ID, &anak.IdIbu, &anak.NamaAnak, &anak.NikAnak, &anak.TanggalLahir, &anak.JenisKelamin, &anak.AnakKe, &anak.BeratLahirKg, &anak.TinggiLahirCm, &anak.Status, &anak.StatusTanggal, &anak.StatusAlasan, &anak.IdPosyandu, &anak.NamaPosyandu, &anak.PunyaKms, &anak.UsiaKehamilanLahirMinggu, &anak.CreatedAt, &anak.UpdatedAt, &anak.NamaIbu, &anak.NikIbu)
 
 		if err != nil {
 			if err.Error() == "no rows in result set" {
@@ -164,6 +185,7 @@ func GetAnakByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			}
 			return
 		}
+		anak.UsiaHari, anak.UsiaKoreksiHari = usiaKronologisDanKoreksi(anak.TanggalLahir, anak.UsiaKehamilanLahirMinggu, time.Now())
 		c.JSON(http.StatusOK, anak)
 	}
 }
@@ -195,8 +217,8 @@ func UpdateAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		_, err = dbpool.Exec(context.Background(),
-			`UPDATE anak SET id_ibu = $1, nama_anak = $2, nik_anak = $3, tanggal_lahir = $4, jenis_kelamin = $5, anak_ke = $6, berat_lahir_kg = $7, tinggi_lahir_cm = $8, updated_at = NOW() WHERE id = $9`,
-			payload.IdIbu, payload.NamaAnak, payload.NikAnak, tglLahir, payload.JenisKelamin, payload.AnakKe, payload.BeratLahirKg, payload.TinggiLahirCm, id)
+			`UPDATE anak SET id_ibu = $1, nama_anak = $2, nik_anak = $3, tanggal_lahir = $4, jenis_kelamin = $5, anak_ke = $6, berat_lahir_kg = $7, tinggi_lahir_cm = $8, punya_kms = COALESCE($9, punya_kms), usia_kehamilan_lahir_minggu = $10, updated_at = NOW() WHERE id = $11`,
+			payload.IdIbu, payload.NamaAnak, payload.NikAnak, tglLahir, payload.JenisKelamin, payload.AnakKe, payload.BeratLahirKg, payload.TinggiLahirCm, payload.PunyaKms, payload.UsiaKehamilanLahirMinggu, id)
 
 		if err != nil {
 			log.Printf("ERROR updating anak ID %d: %v", id, err)
@@ -217,6 +239,10 @@ func UpdateAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui data anak."})
 			return
 		}
+		// Tanggal lahir, jenis kelamin atau usia kehamilan dapat berubah: nilai ulang seluruh pengukuran
+		if err := hitungUlangGiziAnak(context.Background(), dbpool, id); err != nil && err.Error() != "no rows in result set" {
+			log.Printf("ERROR recalculating growth of anak %d: %v", id, err)
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "Data anak berhasil diperbarui!"})
 	}
 }
@@ -240,6 +266,10 @@ func DeleteAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 					c.JSON(http.StatusConflict, gin.H{"error": "Anak tidak bisa dihapus karena masih terhubung dengan data perkembangan/imunisasi."})
 					return
 				}
+				if pgErr.ConstraintName == "kasus_gizi_id_anak_fkey" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Anak tidak bisa dihapus karena masih tercatat dalam register kasus gizi."})
+					return
+				}
 			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus data anak."})
 			return
@@ -29,8 +29,8 @@ func LoginHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		err := dbpool.QueryRow(context.Background(),
-			"SELECT id, nama_lengkap, nik, no_telepon, password, username, created_at, updated_at FROM kader WHERE username = $1", payload.Username).Scan(
-			&kader.ID, &kader.NamaLengkap, &kader.NIK, &kader.NoTelepon, &kader.Password, &kader.Username, &kader.CreatedAt, &kader.UpdatedAt)
+			"SELECT id, nama_lengkap, nik, no_telepon, password, username, id_posyandu, peran, created_at, updated_at FROM kader WHERE username = $1", payload.Username).Scan(
+			&kader.ID, &kader.NamaLengkap, &kader.NIK, &kader.NoTelepon, &kader.Password, &kader.Username, &kader.IdPosyandu, &kader.Peran, &kader.CreatedAt, &kader.UpdatedAt)
 
 		if err != nil {
 			log.Printf("INFO: Login attempt failed for username %s: %v", payload.Username, err)
@@ -55,7 +55,7 @@ func LoginHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		log.Printf("INFO: User %s (ID: %d) logged in successfully", payload.Username, kader.ID)
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Login berhasil!",
-			"user":    gin.H{"id": kader.ID, "nama_lengkap": kader.NamaLengkap, "username": kader.Username},
+			"user":    gin.H{"id": kader.ID, "nama_lengkap": kader.NamaLengkap, "username": kader.Username, "id_posyandu": kader.IdPosyandu, "peran": kader.Peran},
 			"token":   token,
 		})
 	}
@@ -118,3 +118,37 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// RequirePeran membatasi rute untuk kader dengan peran tertentu. Dipasang setelah AuthMiddleware.
+// Peran dibaca dari database agar perubahan peran langsung berlaku tanpa menunggu token kedaluwarsa.
+func RequirePeran(dbpool *pgxpool.Pool, daftarPeran ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			c.Abort()
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+		var peran string
+		if err := dbpool.QueryRow(context.Background(), "SELECT peran FROM kader WHERE id = $1", kaderId).Scan(&peran); err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Akun kader tidak ditemukan."})
+			} else {
+				log.Printf("ERROR fetching peran for kader %d: %v", kaderId, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa hak akses."})
+			}
+			c.Abort()
+			return
+		}
+		for _, p := range daftarPeran {
+			if p == peran {
+				c.Set("peranKader", peran)
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak. Fitur ini hanya untuk peran: " + strings.Join(daftarPeran, ", ") + "."})
+		c.Abort()
+	}
+}
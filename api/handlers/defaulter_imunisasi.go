@@ -0,0 +1,271 @@
+// handlers/defaulter_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+const pelacakanImunisasiSelect = `SELECT p.id, p.id_anak, p.tanggal, p.hasil, p.imunisasi_tertunggak, p.catatan, p.id_kader, k.nama_lengkap, p.created_at
+    FROM pelacakan_imunisasi p
+    LEFT JOIN kader k ON p.id_kader = k.id`
+
+// GetDefaulterImunisasiHandler menangani daftar anak yang tertinggal imunisasi lebih dari masa tenggang,
+// lengkap dengan nomor telepon ibu dan upaya pelacakan. Anak keluar dari daftar begitu dosisnya dicatat.
+// Query: tenggang_hari (default 28 hari sejak tanggal jadwal), id_posyandu (opsional).
+func GetDefaulterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenggang := imunisasi.TenggangDefaulterHari
+		if tenggangQuery := c.Query("tenggang_hari"); tenggangQuery != "" {
+			t, err := strconv.Atoi(tenggangQuery)
+			if err != nil || t < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Tenggang hari harus bilangan bulat tidak negatif."})
+				return
+			}
+			tenggang = t
+		}
+		acuan := tanggalHariIni()
+		ctx := context.Background()
+
+		query := `SELECT a.id, a.nama_anak, a.nik_anak, a.tanggal_lahir, i.nama_lengkap, i.no_telepon, a.id_posyandu, ps.nama
+            FROM anak a
+            JOIN ibu i ON a.id_ibu = i.id
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id
+            WHERE ` + kondisiAnakAktif + ` AND a.tanggal_lahir <= $1 AND a.tanggal_lahir > ($1::date - INTERVAL '60 months')`
+		args := []interface{}{acuan}
+		if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+			idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+				return
+			}
+			query += " AND a.id_posyandu = $2"
+			args = append(args, idPosyandu)
+		}
+
+		rows, err := dbpool.Query(ctx, query, args...)
+		if err != nil {
+			log.Printf("ERROR querying anak for defaulter imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			return
+		}
+		var daftarAnak []models.AnakDefaulterImunisasi
+		var idAnak []int
+		for rows.Next() {
+			var a models.AnakDefaulterImunisasi
+			if err := rows.Scan(&a.IdAnak, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.NamaIbu, &a.NoTeleponIbu, &a.IdPosyandu, &a.NamaPosyandu); err != nil {
+				rows.Close()
+				log.Printf("ERROR scanning anak for defaulter imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data anak."})
+				return
+			}
+			daftarAnak = append(daftarAnak, a)
+			idAnak = append(idAnak, a.IdAnak)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating anak for defaulter imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data anak."})
+			return
+		}
+
+		kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+		if err != nil {
+			log.Printf("ERROR fetching jadwal imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jadwal imunisasi."})
+			return
+		}
+		diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, idAnak)
+		if err != nil {
+			log.Printf("ERROR fetching riwayat imunisasi for defaulter: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat imunisasi."})
+			return
+		}
+
+		hasil := models.DefaulterImunisasi{TanggalAcuan: acuan, TenggangHari: tenggang, Anak: make([]models.AnakDefaulterImunisasi, 0)}
+		sejak := make(map[int]time.Time) // id anak -> tanggal jadwal dosis tertunggak tertua
+		var idDefaulter []int
+		for _, a := range daftarAnak {
+			tertunggak := imunisasi.Defaulter(a.TanggalLahir, imunisasi.Susun(a.TanggalLahir, kalender.UntukKelahiran(a.TanggalLahir).Antigen, diberikan[a.IdAnak], acuan), acuan, tenggang)
+			if len(tertunggak) == 0 {
+				continue
+			}
+			for _, j := range tertunggak {
+				m := modelJadwalImunisasi(j)
+				hari := j.HariTerlambat
+				m.HariTerlambat = &hari
+				a.Imunisasi = append(a.Imunisasi, m)
+				if _, ok := sejak[a.IdAnak]; !ok || j.HariTerlambat > a.HariTerlambat {
+					a.HariTerlambat = j.HariTerlambat
+					sejak[a.IdAnak] = j.TanggalJadwal
+				}
+			}
+			hasil.Anak = append(hasil.Anak, a)
+			idDefaulter = append(idDefaulter, a.IdAnak)
+		}
+
+		// Upaya pelacakan sejak dosis tertunggak tertua jatuh jadwal
+		if len(idDefaulter) > 0 {
+			rows, err := dbpool.Query(ctx, pelacakanImunisasiSelect+" WHERE p.id_anak = ANY($1) ORDER BY p.tanggal ASC, p.id ASC", idDefaulter)
+			if err != nil {
+				log.Printf("ERROR querying pelacakan imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pelacakan."})
+				return
+			}
+			pelacakan := make(map[int][]models.PelacakanImunisasi)
+			for rows.Next() {
+				var p models.PelacakanImunisasi
+				if err := rows.Scan(&p.ID, &p.IdAnak, &p.Tanggal, &p.Hasil, &p.ImunisasiTertunggak, &p.Catatan, &p.IdKader, &p.NamaKader, &p.CreatedAt); err != nil {
+					rows.Close()
+					log.Printf("ERROR scanning pelacakan imunisasi: %v", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data pelacakan."})
+					return
+				}
+				if !p.Tanggal.Before(sejak[p.IdAnak]) {
+					pelacakan[p.IdAnak] = append(pelacakan[p.IdAnak], p)
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				log.Printf("ERROR iterating pelacakan imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data pelacakan."})
+				return
+			}
+			for i := range hasil.Anak {
+				a := &hasil.Anak[i]
+				if daftar := pelacakan[a.IdAnak]; len(daftar) > 0 {
+					a.JumlahPelacakan = len(daftar)
+					a.PelacakanTerakhir = &daftar[len(daftar)-1]
+				}
+			}
+		}
+
+		// Yang paling lama tertinggal ditampilkan lebih dulu
+		sort.SliceStable(hasil.Anak, func(i, j int) bool {
+			if hasil.Anak[i].HariTerlambat != hasil.Anak[j].HariTerlambat {
+				return hasil.Anak[i].HariTerlambat > hasil.Anak[j].HariTerlambat
+			}
+			return hasil.Anak[i].NamaAnak < hasil.Anak[j].NamaAnak
+		})
+		hasil.JumlahAnak = len(hasil.Anak)
+		c.JSON(http.StatusOK, hasil)
+	}
+}
+
+// TambahPelacakanImunisasiHandler mencatat upaya pelacakan anak yang tertinggal imunisasi
+// beserta dosis yang tertunggak saat itu
+func TambahPelacakanImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idAnak, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		var payload models.TambahPelacakanPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hasil pelacakan harus ditelepon, dikunjungi, menolak atau pindah."})
+			return
+		}
+		tanggal := tanggalHariIni()
+		if payload.Tanggal != nil {
+			t, err := time.Parse("2006-01-02", *payload.Tanggal)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+				return
+			}
+			tanggal = t
+		}
+		ctx := context.Background()
+
+		var tanggalLahir time.Time
+		if err := dbpool.QueryRow(ctx, "SELECT tanggal_lahir FROM anak WHERE id = $1", idAnak).Scan(&tanggalLahir); err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR fetching anak %d for pelacakan: %v", idAnak, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan pelacakan."})
+			}
+			return
+		}
+		jadwal, _, err := jadwalImunisasiAnak(ctx, dbpool, idAnak, tanggalLahir, tanggal)
+		if err != nil {
+			log.Printf("ERROR building jadwal imunisasi for pelacakan anak %d: %v", idAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan pelacakan."})
+			return
+		}
+		tertunggak := make([]string, 0)
+		for _, j := range imunisasi.Defaulter(tanggalLahir, jadwal, tanggal, 0) {
+			tertunggak = append(tertunggak, j.Nama)
+		}
+
+		var id int
+		err = dbpool.QueryRow(ctx,
+			`INSERT INTO pelacakan_imunisasi (id_anak, tanggal, hasil, imunisasi_tertunggak, catatan, id_kader) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			idAnak, tanggal, payload.Hasil, tertunggak, payload.Catatan, kaderId).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR inserting pelacakan imunisasi anak %d by kader %d: %v", idAnak, kaderId, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == "pelacakan_imunisasi_id_anak_fkey" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan pelacakan."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Pelacakan imunisasi berhasil dicatat!", "id": id, "imunisasi_tertunggak": tertunggak})
+	}
+}
+
+// GetPelacakanImunisasiHandler menampilkan seluruh upaya pelacakan imunisasi seorang anak, terbaru lebih dulu
+func GetPelacakanImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		idAnak, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+
+		rows, err := dbpool.Query(context.Background(), pelacakanImunisasiSelect+" WHERE p.id_anak = $1 ORDER BY p.tanggal DESC, p.id DESC", idAnak)
+		if err != nil {
+			log.Printf("ERROR querying pelacakan imunisasi anak %d: %v", idAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.PelacakanImunisasi, 0)
+		for rows.Next() {
+			var p models.PelacakanImunisasi
+			if err := rows.Scan(&p.ID, &p.IdAnak, &p.Tanggal, &p.Hasil, &p.ImunisasiTertunggak, &p.Catatan, &p.IdKader, &p.NamaKader, &p.CreatedAt); err != nil {
+				log.Printf("ERROR scanning pelacakan imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, p)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating pelacakan imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
@@ -0,0 +1,162 @@
+// handlers/geo.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// maksPresisiAnonim membatasi digit desimal koordinat ekspor anonim; 3 digit (sel sekitar 110 m) tidak lagi
+// menunjuk satu rumah, sedangkan 4 digit ke atas praktis masih dapat mengenali rumah ibu
+const maksPresisiAnonim = 3
+
+// GetGeoAnakHandler mengekspor lokasi anak (berdasarkan koordinat rumah ibu) dalam format GeoJSON.
+// Filter opsional:
+//   - status_gizi: daftar status dipisah koma, dicocokkan dengan status gizi pemeriksaan terakhir
+//   - kunjungan_dari / kunjungan_sampai: rentang tanggal kunjungan terakhir (YYYY-MM-DD)
+//   - presisi: jumlah digit desimal koordinat (0-3, paling teliti sekitar 100 m) untuk dibagikan ke publik;
+//     nama, ID anak dan ibu, serta tanggal lahir tidak disertakan, hanya kelompok umur
+func GetGeoAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		layout := "2006-01-02"
+		query := `SELECT a.id, a.nama_anak, a.jenis_kelamin, a.tanggal_lahir, i.id, i.latitude, i.longitude, p.status_gizi, p.tanggal_pemeriksaan
+            FROM anak a
+            JOIN ibu i ON a.id_ibu = i.id
+            LEFT JOIN LATERAL (
+                SELECT status_gizi, tanggal_pemeriksaan FROM perkembangan
+                WHERE id_anak = a.id
+                ORDER BY tanggal_pemeriksaan DESC, id DESC
+                LIMIT 1
+            ) p ON TRUE`
+
+		var args []interface{}
+		conditions := []string{"i.latitude IS NOT NULL", "i.longitude IS NOT NULL"}
+		argCounter := 1
+
+		if !sertakanNonaktif(c) {
+			conditions = append(conditions, kondisiAnakAktif)
+		}
+
+		if statusQuery := c.Query("status_gizi"); statusQuery != "" {
+			var daftarStatus []string
+			for _, s := range strings.Split(statusQuery, ",") {
+				if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+					daftarStatus = append(daftarStatus, s)
+				}
+			}
+			if len(daftarStatus) > 0 {
+				conditions = append(conditions, fmt.Sprintf("LOWER(p.status_gizi) = ANY($%d)", argCounter))
+				args = append(args, daftarStatus)
+				argCounter++
+			}
+		}
+
+		if dari := c.Query("kunjungan_dari"); dari != "" {
+			tgl, err := time.Parse(layout, dari)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format kunjungan_dari tidak valid (YYYY-MM-DD)."})
+				return
+			}
+			conditions = append(conditions, fmt.Sprintf("p.tanggal_pemeriksaan >= $%d", argCounter))
+			args = append(args, tgl)
+			argCounter++
+		}
+		if sampai := c.Query("kunjungan_sampai"); sampai != "" {
+			tgl, err := time.Parse(layout, sampai)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format kunjungan_sampai tidak valid (YYYY-MM-DD)."})
+				return
+			}
+			conditions = append(conditions, fmt.Sprintf("p.tanggal_pemeriksaan < $%d", argCounter))
+			args = append(args, tgl.AddDate(0, 0, 1)) // Inklusif sampai akhir hari
+			argCounter++
+		}
+
+		presisi := -1 // -1 berarti koordinat tidak dibulatkan
+		if presisiQuery := c.Query("presisi"); presisiQuery != "" {
+			p, err := strconv.Atoi(presisiQuery)
+			if err != nil || p < 0 || p > maksPresisiAnonim {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Presisi harus angka 0 sampai %d.", maksPresisiAnonim)})
+				return
+			}
+			presisi = p
+		}
+
+		query += " WHERE " + strings.Join(conditions, " AND ")
+		query += " ORDER BY a.id ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying geo anak: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data peta."})
+			return
+		}
+		defer rows.Close()
+
+		hariIni := tanggalHariIni()
+		koleksi := models.GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]models.GeoJSONFeature, 0)}
+		for rows.Next() {
+			var prop models.GeoAnakProperti
+			var idAnak, idIbu int
+			var namaAnak string
+			var tanggalLahir time.Time
+			var lat, lng float64
+			if err := rows.Scan(&idAnak, &namaAnak, &prop.JenisKelamin, &tanggalLahir, &idIbu, &lat, &lng, &prop.StatusGizi, &prop.KunjunganTerakhir); err != nil {
+				log.Printf("ERROR scanning geo anak row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data peta."})
+				return
+			}
+			prop.KelompokUmur = kelompokUmurGeo(usiaBulanPenuh(tanggalLahir, hariIni))
+			if presisi >= 0 {
+				lat, lng = bulatkanKoordinat(lat, presisi), bulatkanKoordinat(lng, presisi)
+			} else {
+				prop.IdAnak, prop.NamaAnak, prop.TanggalLahir, prop.IdIbu = &idAnak, &namaAnak, &tanggalLahir, &idIbu
+			}
+			koleksi.Features = append(koleksi.Features, models.GeoJSONFeature{
+				Type:       "Feature",
+				Geometry:   models.GeoJSONGeometry{Type: "Point", Coordinates: [2]float64{lng, lat}},
+				Properties: prop,
+			})
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating geo anak rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data peta."})
+			return
+		}
+
+		body, err := json.Marshal(koleksi)
+		if err != nil {
+			log.Printf("ERROR encoding geojson: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat GeoJSON."})
+			return
+		}
+		c.Data(http.StatusOK, "application/geo+json", body)
+	}
+}
+
+// bulatkanKoordinat membulatkan koordinat ke sejumlah digit desimal
+func bulatkanKoordinat(v float64, presisi int) float64 {
+	faktor := math.Pow(10, float64(presisi))
+	return math.Round(v*faktor) / faktor
+}
+
+// kelompokUmurGeo mengembalikan kelompok umur anak pada peta: kelompok SKDN, ditambah 60+ bulan
+// untuk anak yang sudah lewat umur balita
+func kelompokUmurGeo(usiaBulan int) string {
+	if usiaBulan >= 60 {
+		return "60+"
+	}
+	return kelompokUmurSKDN(usiaBulan)
+}
@@ -0,0 +1,218 @@
+// handlers/gizi.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/growth"
+)
+
+// dataAnakGizi adalah data anak yang dibutuhkan untuk menilai hasil pengukuran
+type dataAnakGizi struct {
+	TanggalLahir             time.Time
+	JenisKelamin             string
+	UsiaKehamilanLahirMinggu *int
+}
+
+// ambilDataAnakGizi mengambil tanggal lahir, jenis kelamin dan usia kehamilan saat lahir anak
+func ambilDataAnakGizi(ctx context.Context, dbpool *pgxpool.Pool, idAnak int) (dataAnakGizi, error) {
+	var a dataAnakGizi
+	err := dbpool.QueryRow(ctx, "SELECT tanggal_lahir, jenis_kelamin, usia_kehamilan_lahir_minggu FROM anak WHERE id = $1", idAnak).Scan(&a.TanggalLahir, &a.JenisKelamin, &a.UsiaKehamilanLahirMinggu)
+	return a, err
+}
+
+// tanggalHariIni mengembalikan tanggal hari ini menurut zona waktu lokal server, dinyatakan pada tengah
+// malam UTC seperti nilai kolom DATE yang dibaca pgx, sehingga selisih harinya dengan tanggal lahir tepat
+func tanggalHariIni() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// usiaHari menghitung umur anak dalam hari pada tanggal tertentu
+func usiaHari(tanggalLahir, tanggal time.Time) int {
+	return int(tanggal.Sub(tanggalLahir).Hours() / 24)
+}
+
+// usiaPenilaian mengembalikan umur (hari) yang dipakai untuk menilai pertumbuhan pada tanggal tertentu:
+// umur koreksi untuk bayi prematur sampai 24 bulan, selain itu umur kronologis
+func (a dataAnakGizi) usiaPenilaian(tanggal time.Time) int {
+	return growth.UsiaPenilaianHari(usiaHari(a.TanggalLahir, tanggal), a.UsiaKehamilanLahirMinggu)
+}
+
+// usiaKronologisDanKoreksi mengembalikan umur kronologis dan umur koreksi (nil bila tidak berlaku) dalam hari
+func usiaKronologisDanKoreksi(tanggalLahir time.Time, usiaKehamilanLahirMinggu *int, tanggal time.Time) (int, *int) {
+	usia := usiaHari(tanggalLahir, tanggal)
+	return usia, growth.UsiaKoreksiHari(usia, usiaKehamilanLahirMinggu)
+}
+
+// panjangStandar mengoreksi panjang/tinggi badan sesuai cara ukur yang diasumsikan tabel WHO
+func panjangStandar(anak dataAnakGizi, tanggal time.Time, tbCm *float64, caraUkur *string) *float64 {
+	if tbCm == nil {
+		return nil
+	}
+	cara := ""
+	if caraUkur != nil {
+		cara = *caraUkur
+	}
+	tb := math.Round(growth.SesuaikanPanjang(*tbCm, anak.usiaPenilaian(tanggal), cara)*10) / 10
+	return &tb
+}
+
+// hitungGizi menghitung z-score WHO dan kategori Permenkes 2/2020 sebuah pengukuran.
+// tbCm harus sudah dikoreksi dengan panjangStandar.
+func hitungGizi(anak dataAnakGizi, tanggal time.Time, bbKg, tbCm, lkCm, llCm *float64) growth.Hasil {
+	return growth.Hitung(growth.Pengukuran{
+		JenisKelamin: anak.JenisKelamin,
+		UsiaHari:     anak.usiaPenilaian(tanggal),
+		BbKg:         bbKg,
+		TbCm:         tbCm,
+		LkCm:         lkCm,
+		LilaCm:       llCm,
+	})
+}
+
+// Batas biologis pengukuran balita; nilai di luar rentang ini pasti salah input dan ditolak
+var batasBiologis = []struct {
+	nama     string
+	min, max float64
+}{
+	{"Berat badan", 0.5, 50},
+	{"Panjang/tinggi badan", 35, 130},
+	{"Lingkar kepala", 25, 60},
+	{"Lingkar lengan atas", 5, 30},
+}
+
+// Toleransi penurunan ukuran dibanding pengukuran sebelumnya
+const (
+	toleransiTurunTbCm     = 1.5 // Galat ukur antar kunjungan, termasuk data lama tanpa cara ukur
+	toleransiTurunLkCm     = 1.0
+	toleransiTurunBbPersen = 10.0
+)
+
+// periksaPengukuran memeriksa kewajaran pengukuran terhadap batas biologis, batas flag WHO
+// dan riwayat anak sendiri. tbCm adalah panjang terkoreksi, sedangkan tbCmUkur adalah hasil
+// ukur apa adanya. galat berisi pesan jika nilai mustahil (ditolak), sedangkan peringatan
+// berisi temuan yang boleh disimpan setelah dikonfirmasi kader.
+// idPerkembangan diisi 0 untuk data baru.
+func periksaPengukuran(ctx context.Context, dbpool *pgxpool.Pool, idAnak, idPerkembangan int, tanggal time.Time, bbKg, tbCmUkur, tbCm, lkCm, llCm *float64, gizi growth.Hasil) (galat string, peringatan []string, err error) {
+	for i, nilai := range []*float64{bbKg, tbCmUkur, lkCm, llCm} {
+		if nilai == nil {
+			continue
+		}
+		b := batasBiologis[i]
+		if *nilai < b.min || *nilai > b.max {
+			return fmt.Sprintf("%s %.1f di luar rentang yang mungkin (%.1f - %.1f).", b.nama, *nilai, b.min, b.max), nil, nil
+		}
+	}
+
+	peringatan = growth.PeriksaFlag(gizi)
+
+	// Bandingkan dengan pengukuran terakhir sebelum tanggal ini untuk setiap jenis ukuran, dan untuk panjang
+	// badan serta lingkar kepala juga dengan pengukuran sesudahnya bila data dicatat susulan
+	var bbLalu, tbLalu, lkLalu, tbBerikut, lkBerikut *float64
+	var tglTbLalu, tglTbBerikut *time.Time
+	err = dbpool.QueryRow(ctx,
+		`SELECT
+            (SELECT bb_kg FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan <= $2 AND bb_kg IS NOT NULL ORDER BY tanggal_pemeriksaan DESC, id DESC LIMIT 1),
+            (SELECT COALESCE(tb_cm_standar, tb_cm) FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan <= $2 AND tb_cm IS NOT NULL ORDER BY tanggal_pemeriksaan DESC, id DESC LIMIT 1),
+            (SELECT tanggal_pemeriksaan FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan <= $2 AND tb_cm IS NOT NULL ORDER BY tanggal_pemeriksaan DESC, id DESC LIMIT 1),
+            (SELECT lk_cm FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan <= $2 AND lk_cm IS NOT NULL ORDER BY tanggal_pemeriksaan DESC, id DESC LIMIT 1),
+            (SELECT COALESCE(tb_cm_standar, tb_cm) FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan > $2 AND tb_cm IS NOT NULL ORDER BY tanggal_pemeriksaan ASC, id ASC LIMIT 1),
+            (SELECT tanggal_pemeriksaan FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan > $2 AND tb_cm IS NOT NULL ORDER BY tanggal_pemeriksaan ASC, id ASC LIMIT 1),
+            (SELECT lk_cm FROM perkembangan WHERE id_anak = $1 AND id <> $3 AND tanggal_pemeriksaan > $2 AND lk_cm IS NOT NULL ORDER BY tanggal_pemeriksaan ASC, id ASC LIMIT 1)`,
+		idAnak, tanggal, idPerkembangan).Scan(&bbLalu, &tbLalu, &tglTbLalu, &lkLalu, &tbBerikut, &tglTbBerikut, &lkBerikut)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if tbCm != nil && (tbLalu != nil || tbBerikut != nil) {
+		anak, err := ambilDataAnakGizi(ctx, dbpool, idAnak)
+		if err != nil {
+			return "", nil, err
+		}
+		// Panjang standar berbeda 0,7 cm di kedua sisi umur 24 bulan, jadi dibandingkan sebagai panjang setara telentang
+		telentang := func(tb float64, tgl time.Time) float64 {
+			return growth.PanjangTelentang(tb, anak.usiaPenilaian(tgl), "")
+		}
+		tbIni := telentang(*tbCm, tanggal)
+		if tbLalu != nil {
+			if turun := telentang(*tbLalu, *tglTbLalu) - tbIni; turun > toleransiTurunTbCm {
+				peringatan = append(peringatan, fmt.Sprintf("Panjang/tinggi badan turun %.1f cm dari pengukuran sebelumnya (%.1f cm).", turun, *tbLalu))
+			}
+		}
+		if tbBerikut != nil {
+			if turun := tbIni - telentang(*tbBerikut, *tglTbBerikut); turun > toleransiTurunTbCm {
+				peringatan = append(peringatan, fmt.Sprintf("Panjang/tinggi badan %.1f cm lebih besar dari pengukuran sesudahnya pada %s (%.1f cm).", turun, tglTbBerikut.Format("02-01-2006"), *tbBerikut))
+			}
+		}
+	}
+	if lkCm != nil && lkBerikut != nil && *lkCm-*lkBerikut > toleransiTurunLkCm {
+		peringatan = append(peringatan, fmt.Sprintf("Lingkar kepala %.1f cm lebih besar dari pengukuran sesudahnya (%.1f cm).", *lkCm-*lkBerikut, *lkBerikut))
+	}
+	if lkCm != nil && lkLalu != nil && *lkLalu-*lkCm > toleransiTurunLkCm {
+		peringatan = append(peringatan, fmt.Sprintf("Lingkar kepala turun %.1f cm dari pengukuran sebelumnya (%.1f cm).", *lkLalu-*lkCm, *lkLalu))
+	}
+	if bbKg != nil && bbLalu != nil && (*bbLalu-*bbKg)/(*bbLalu)*100 > toleransiTurunBbPersen {
+		peringatan = append(peringatan, fmt.Sprintf("Berat badan turun lebih dari %.0f%% dari penimbangan sebelumnya (%.2f kg).", toleransiTurunBbPersen, *bbLalu))
+	}
+	return "", peringatan, nil
+}
+
+// hitungUlangGiziAnak menghitung ulang z-score dan status seluruh perkembangan seorang anak,
+// dipakai setelah data yang memengaruhi umur penilaian (tanggal lahir, jenis kelamin,
+// usia kehamilan saat lahir) diubah. Status KMS ikut dinilai ulang.
+func hitungUlangGiziAnak(ctx context.Context, dbpool *pgxpool.Pool, idAnak int) error {
+	anak, err := ambilDataAnakGizi(ctx, dbpool, idAnak)
+	if err != nil {
+		return err
+	}
+	rows, err := dbpool.Query(ctx, `SELECT id, tanggal_pemeriksaan, bb_kg, tb_cm, cara_ukur, lk_cm, ll_cm FROM perkembangan WHERE id_anak = $1`, idAnak)
+	if err != nil {
+		return err
+	}
+	type baris struct {
+		id                     int
+		tanggal                time.Time
+		bbKg, tbCm, lkCm, llCm *float64
+		caraUkur               *string
+	}
+	var daftar []baris
+	for rows.Next() {
+		var b baris
+		if err := rows.Scan(&b.id, &b.tanggal, &b.bbKg, &b.tbCm, &b.caraUkur, &b.lkCm, &b.llCm); err != nil {
+			rows.Close()
+			return err
+		}
+		daftar = append(daftar, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	for _, b := range daftar {
+		tbStandar := panjangStandar(anak, b.tanggal, b.tbCm, b.caraUkur)
+		gizi := hitungGizi(anak, b.tanggal, b.bbKg, tbStandar, b.lkCm, b.llCm)
+		// Status gizi isian kader dipertahankan bila tidak dapat dihitung
+		if _, err := tx.Exec(ctx,
+			`UPDATE perkembangan SET tb_cm_standar = $1, zs_bbu = $2, zs_tbu = $3, zs_bbtb = $4, zs_imtu = $5, zs_lku = $6, zs_lilau = $7,
+                status_bbu = $8, status_tbu = $9, status_bbtb = $10, status_imtu = $11, status_lku = $12, status_gizi = COALESCE($13, status_gizi) WHERE id = $14`,
+			tbStandar, gizi.ZBBU, gizi.ZTBU, gizi.ZBBTB, gizi.ZIMTU, gizi.ZLKU, gizi.ZLILAU,
+			gizi.StatusBBU, gizi.StatusTBU, gizi.StatusBBTB, gizi.StatusIMTU, gizi.StatusLKU, gizi.StatusGizi, b.id); err != nil {
+			return err
+		}
+	}
+	if err := evaluasiKMSAnak(ctx, tx, idAnak); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
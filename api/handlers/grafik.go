@@ -0,0 +1,178 @@
+// handlers/grafik.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/grafik"
+	"github.com/nadhifhafizp/api/growth"
+	"github.com/nadhifhafizp/api/models"
+)
+
+var judulIndikatorGrafik = map[growth.Indikator]string{
+	growth.BBU: "Berat Badan menurut Umur (BB/U)",
+	growth.TBU: "Panjang/Tinggi Badan menurut Umur (PB/U, TB/U)",
+}
+
+// kurvaSD menghitung kurva -3 SD sampai +3 SD sebuah indikator menurut umur; xs adalah umur dalam hari
+func kurvaSD(ind growth.Indikator, jenisKelamin string, xs []float64, skalaX func(float64) float64) ([7][]grafik.Titik, error) {
+	var kurva [7][]grafik.Titik
+	for _, x := range xs {
+		lms, err := growth.ParameterLMS(ind, jenisKelamin, int(x), 0)
+		if err != nil {
+			return kurva, err
+		}
+		for i := range kurva {
+			kurva[i] = append(kurva[i], grafik.Titik{X: skalaX(x), Y: lms.Nilai(float64(i - 3))})
+		}
+	}
+	return kurva, nil
+}
+
+// buatGrafikAnak menyusun grafik pertumbuhan anak untuk satu indikator
+func buatGrafikAnak(ctx context.Context, dbpool *pgxpool.Pool, idAnak int, ind growth.Indikator) (*grafik.Grafik, error) {
+	p, err := susunPertumbuhan(ctx, dbpool, idAnak)
+	if err != nil {
+		return nil, err
+	}
+	jenisKelamin := "Laki-laki"
+	if p.JenisKelamin == "P" {
+		jenisKelamin = "Perempuan"
+	}
+	g := &grafik.Grafik{
+		Judul:    judulIndikatorGrafik[ind],
+		Subjudul: fmt.Sprintf("%s (%s), lahir %s - Standar WHO 2006", p.NamaAnak, jenisKelamin, p.TanggalLahir.Format("02-01-2006")),
+	}
+	if p.UsiaKehamilanLahirMinggu != nil && *p.UsiaKehamilanLahirMinggu < growth.MingguPrematur {
+		g.Subjudul += fmt.Sprintf(" - lahir %d minggu, umur dikoreksi s.d. 24 bulan", *p.UsiaKehamilanLahirMinggu)
+	}
+
+	// Bayi prematur diplot pada umur koreksi sampai 24 bulan
+	usiaPlot := func(t models.TitikPertumbuhan) (int, float64) {
+		if t.UsiaKoreksiHari != nil {
+			return *t.UsiaKoreksiHari, *t.UsiaKoreksiBulan
+		}
+		return t.UsiaHari, t.UsiaBulan
+	}
+	usiaKini := growth.UsiaPenilaianHari(usiaHari(p.TanggalLahir, time.Now()), p.UsiaKehamilanLahirMinggu)
+	for _, t := range p.Titik {
+		hari, _ := usiaPlot(t)
+		usiaKini = max(usiaKini, hari)
+	}
+	bawah2Tahun := usiaKini < 731
+
+	var xs []float64
+	g.MinX, g.MaxX, g.LangkahX = 0, 60, 6
+	if bawah2Tahun {
+		g.MaxX, g.LangkahX = 24, 2
+	}
+	g.LabelX = "Umur (bulan)"
+	for b := 0; b <= int(g.MaxX); b++ {
+		hari := math.Round(float64(b) * growth.HariPerBulan)
+		if hari > growth.UsiaMaksHari {
+			hari = growth.UsiaMaksHari
+		}
+		if b == 24 {
+			// Titik terakhir tabel panjang badan agar loncatan ke tabel tinggi badan tergambar
+			xs = append(xs, 730)
+		}
+		xs = append(xs, hari)
+	}
+	skalaX := func(x float64) float64 { return x / growth.HariPerBulan }
+	for _, t := range p.Titik {
+		nilai := t.BbKg
+		if ind == growth.TBU {
+			nilai = t.TbCm
+		}
+		_, bulan := usiaPlot(t)
+		if nilai != nil && bulan <= g.MaxX {
+			g.Anak = append(g.Anak, grafik.Titik{X: bulan, Y: *nilai})
+		}
+	}
+	g.LabelY = "Berat badan (kg)"
+	if ind == growth.TBU {
+		g.LabelY = "Panjang/tinggi badan (cm)"
+	}
+
+	g.Kurva, err = kurvaSD(ind, p.JenisKelamin, xs, skalaX)
+	if err != nil {
+		return nil, err
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, t := range g.Kurva[0] {
+		minY = math.Min(minY, t.Y)
+	}
+	for _, t := range g.Kurva[6] {
+		maxY = math.Max(maxY, t.Y)
+	}
+	for _, t := range g.Anak {
+		minY = math.Min(minY, t.Y)
+		maxY = math.Max(maxY, t.Y)
+	}
+	g.LangkahY = grafik.LangkahSumbu(maxY-minY, 14)
+	g.MinY = math.Floor(minY/g.LangkahY) * g.LangkahY
+	g.MaxY = math.Ceil(maxY/g.LangkahY) * g.LangkahY
+	return g, nil
+}
+
+// grafikAnakHandler membuat handler grafik pertumbuhan untuk satu format keluaran
+func grafikAnakHandler(dbpool *pgxpool.Pool, format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		ind := growth.Indikator(c.DefaultQuery("indikator", string(growth.BBU)))
+		if _, ok := judulIndikatorGrafik[ind]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Indikator harus bbu atau tbu"})
+			return
+		}
+
+		g, err := buatGrafikAnak(context.Background(), dbpool, id, ind)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR building %s chart for anak %d: %v", ind, id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat grafik."})
+			}
+			return
+		}
+
+		namaBerkas := fmt.Sprintf("grafik-%s-anak-%d.%s", ind, id, format)
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, namaBerkas))
+		if format == "svg" {
+			c.Data(http.StatusOK, "image/svg+xml", g.SVG())
+			return
+		}
+		gambar, err := g.PNG()
+		if err != nil {
+			log.Printf("ERROR encoding chart png for anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat grafik."})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", gambar)
+	}
+}
+
+// GetGrafikAnakSVGHandler menangani grafik pertumbuhan anak dalam format SVG.
+// Query: indikator=bbu|tbu (default bbu).
+func GetGrafikAnakSVGHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return grafikAnakHandler(dbpool, "svg")
+}
+
+// GetGrafikAnakPNGHandler menangani grafik pertumbuhan anak dalam format PNG untuk dicetak atau dikirim lewat WhatsApp
+func GetGrafikAnakPNGHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return grafikAnakHandler(dbpool, "png")
+}
@@ -33,10 +33,14 @@ func TambahIbuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "NIK max 16 karakter."})
 			return
 		}
+		if msg := validasiKoordinat(payload.Latitude, payload.Longitude); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
 
 		_, err := dbpool.Exec(context.Background(),
-			`INSERT INTO ibu (nama_lengkap, nik, no_telepon, alamat, id_kader_pendaftar) VALUES ($1, $2, $3, $4, $5)`,
-			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Alamat, kaderId)
+			`INSERT INTO ibu (nama_lengkap, nik, no_telepon, alamat, latitude, longitude, id_kader_pendaftar) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Alamat, payload.Latitude, payload.Longitude, kaderId)
 
 		if err != nil {
 			log.Printf("ERROR inserting ibu by kader %d: %v", kaderId, err)
@@ -59,7 +63,7 @@ func GetIbuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var daftarIbu []models.Ibu
 		searchQuery := c.Query("search")
-		baseQuery := "SELECT id, nama_lengkap, nik, no_telepon, alamat, id_kader_pendaftar, created_at, updated_at FROM ibu"
+		baseQuery := "SELECT id, nama_lengkap, nik, no_telepon, alamat, latitude, longitude, id_kader_pendaftar, created_at, updated_at FROM ibu"
 		var args []interface{}
 		query := baseQuery
 
@@ -79,7 +83,7 @@ func GetIbuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		for rows.Next() {
 			var i models.Ibu
-			if err := rows.Scan(&i.ID, &i.NamaLengkap, &i.NIK, &i.NoTelepon, &i.Alamat, &i.IdKaderPendaftar, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			if err := rows.Scan(&i.ID, &i.NamaLengkap, &i.NIK, &i.NoTelepon, &i.Alamat, &i.Latitude, &i.Longitude, &i.IdKaderPendaftar, &i.CreatedAt, &i.UpdatedAt); err != nil {
 				log.Printf("ERROR scanning ibu row: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data ibu."})
 				return
@@ -140,8 +144,8 @@ func GetIbuByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		var ibu models.Ibu
 		err = dbpool.QueryRow(context.Background(),
-			`SELECT id, nama_lengkap, nik, no_telepon, alamat, id_kader_pendaftar, created_at, updated_at FROM ibu WHERE id = $1`, id).
-			Scan(&ibu.ID, &ibu.NamaLengkap, &ibu.NIK, &ibu.NoTelepon, &ibu.Alamat, &ibu.IdKaderPendaftar, &ibu.CreatedAt, &ibu.UpdatedAt)
+			`SELECT id, nama_lengkap, nik, no_telepon, alamat, latitude, longitude, id_kader_pendaftar, created_at, updated_at FROM ibu WHERE id = $1`, id).
+			Scan(&ibu.ID, &ibu.NamaLengkap, &ibu.NIK, &ibu.NoTelepon, &ibu.Alamat, &ibu.Latitude, &ibu.Longitude, &ibu.IdKaderPendaftar, &ibu.CreatedAt, &ibu.UpdatedAt)
 
 		if err != nil {
 			if err.Error() == "no rows in result set" {
@@ -175,10 +179,14 @@ func UpdateIbuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "NIK max 16 karakter."})
 			return
 		}
+		if msg := validasiKoordinat(payload.Latitude, payload.Longitude); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
 
 		_, err = dbpool.Exec(context.Background(),
-			`UPDATE ibu SET nama_lengkap = $1, nik = $2, no_telepon = $3, alamat = $4, updated_at = NOW() WHERE id = $5`,
-			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Alamat, id)
+			`UPDATE ibu SET nama_lengkap = $1, nik = $2, no_telepon = $3, alamat = $4, latitude = $5, longitude = $6, updated_at = NOW() WHERE id = $7`,
+			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Alamat, payload.Latitude, payload.Longitude, id)
 
 		if err != nil {
 			log.Printf("ERROR updating ibu ID %d: %v", id, err)
@@ -220,3 +228,18 @@ func DeleteIbuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "Data ibu berhasil dihapus!"})
 	}
 }
+
+// validasiKoordinat memastikan latitude/longitude diisi berpasangan dan dalam rentang yang benar.
+// Mengembalikan pesan error (kosong jika valid).
+func validasiKoordinat(lat, lng *float64) string {
+	if lat == nil && lng == nil {
+		return ""
+	}
+	if lat == nil || lng == nil {
+		return "Latitude dan Longitude harus diisi bersamaan."
+	}
+	if *lat < -90 || *lat > 90 || *lng < -180 || *lng > 180 {
+		return "Koordinat di luar rentang (latitude -90..90, longitude -180..180)."
+	}
+	return ""
+}
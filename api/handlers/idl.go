@@ -0,0 +1,193 @@
+// handlers/idl.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+	"github.com/nadhifhafizp/api/pdf"
+)
+
+var namaBulan = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// formatTanggalIndonesia memformat tanggal seperti "5 Januari 2026"
+func formatTanggalIndonesia(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), namaBulan[t.Month()-1], t.Year())
+}
+
+// modelStatusIDL mengubah hasil perhitungan IDL menjadi bentuk respons
+func modelStatusIDL(tanggalLahir time.Time, s imunisasi.StatusIDL) models.StatusIDLAnak {
+	m := models.StatusIDLAnak{
+		TanggalLahir:   tanggalLahir,
+		Status:         s.Status,
+		TanggalLengkap: s.TanggalLengkap,
+		Diberikan:      make([]models.JadwalImunisasi, 0, len(s.Diberikan)),
+		BelumDiberikan: make([]models.MasterImunisasiSimple, 0, len(s.BelumDiberikan)),
+	}
+	if s.TanggalLengkap != nil {
+		bulan := usiaBulanPenuh(tanggalLahir, *s.TanggalLengkap)
+		m.UsiaLengkapBulan = &bulan
+	}
+	for _, j := range s.Diberikan {
+		m.Diberikan = append(m.Diberikan, modelJadwalImunisasi(j))
+	}
+	for _, a := range s.BelumDiberikan {
+		m.BelumDiberikan = append(m.BelumDiberikan, models.MasterImunisasiSimple{ID: a.ID, NamaImunisasi: a.Nama, UsiaIdealBulan: a.UsiaIdealBulan, Seri: a.Seri, DosisKe: a.DosisKe})
+	}
+	return m
+}
+
+// usiaBulanPenuh mengembalikan umur dalam bulan kalender penuh pada tanggal tertentu
+func usiaBulanPenuh(tanggalLahir, tanggal time.Time) int {
+	bulan := (tanggal.Year()-tanggalLahir.Year())*12 + int(tanggal.Month()-tanggalLahir.Month())
+	if imunisasi.TambahBulan(tanggalLahir, bulan).After(tanggal) {
+		bulan--
+	}
+	return bulan
+}
+
+// statusIDLAnak menghitung status Imunisasi Dasar Lengkap seorang anak per hari ini
+func statusIDLAnak(ctx context.Context, dbpool *pgxpool.Pool, idAnak int) (models.StatusIDLAnak, error) {
+	var namaAnak string
+	var tanggalLahir time.Time
+	if err := dbpool.QueryRow(ctx, "SELECT nama_anak, tanggal_lahir FROM anak WHERE id = $1", idAnak).Scan(&namaAnak, &tanggalLahir); err != nil {
+		return models.StatusIDLAnak{}, err
+	}
+	kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+	if err != nil {
+		return models.StatusIDLAnak{}, err
+	}
+	diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, []int{idAnak})
+	if err != nil {
+		return models.StatusIDLAnak{}, err
+	}
+	status := modelStatusIDL(tanggalLahir, imunisasi.HitungIDL(tanggalLahir, kalender.UntukKelahiran(tanggalLahir).Antigen, diberikan[idAnak], time.Now()))
+	status.IdAnak, status.NamaAnak = idAnak, namaAnak
+	return status, nil
+}
+
+// GetStatusIDLAnakHandler menangani status Imunisasi Dasar Lengkap seorang anak
+func GetStatusIDLAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		status, err := statusIDLAnak(context.Background(), dbpool, id)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR computing status IDL for anak %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung status imunisasi dasar."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// GetSertifikatIDLHandler menangani pembuatan sertifikat Imunisasi Dasar Lengkap (PDF A4 mendatar).
+// Sertifikat hanya dibuat bila seluruh imunisasi dasar sudah diberikan.
+func GetSertifikatIDLHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		status, err := statusIDLAnak(ctx, dbpool, id)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR computing status IDL for anak %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung status imunisasi dasar."})
+			}
+			return
+		}
+		if status.Status == imunisasi.IDLBelumLengkap {
+			c.JSON(http.StatusConflict, gin.H{"error": "Imunisasi dasar anak belum lengkap.", "belum_diberikan": status.BelumDiberikan})
+			return
+		}
+
+		var namaIbu string
+		var jenisKelamin string
+		var namaPosyandu *string
+		err = dbpool.QueryRow(ctx,
+			`SELECT i.nama_lengkap, a.jenis_kelamin, ps.nama FROM anak a JOIN ibu i ON a.id_ibu = i.id
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id WHERE a.id = $1`, id).Scan(&namaIbu, &jenisKelamin, &namaPosyandu)
+		if err != nil {
+			log.Printf("ERROR fetching certificate data for anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat sertifikat."})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="sertifikat-idl-anak-%d.pdf"`, id))
+		c.Data(http.StatusOK, "application/pdf", sertifikatIDL(status, namaIbu, jenisKelamin, namaPosyandu))
+	}
+}
+
+// sertifikatIDL menyusun halaman sertifikat
+func sertifikatIDL(s models.StatusIDLAnak, namaIbu, jenisKelamin string, namaPosyandu *string) []byte {
+	d := pdf.Baru(pdf.A4Panjang, pdf.A4Pendek)
+	d.Warna(0.13, 0.45, 0.35)
+	d.Kotak(24, 24, d.Lebar-48, d.Tinggi-48, 3)
+	d.Kotak(32, 32, d.Lebar-64, d.Tinggi-64, 0.8)
+
+	d.TeksTengah(95, 30, true, "SERTIFIKAT")
+	d.TeksTengah(122, 16, true, "IMUNISASI DASAR LENGKAP")
+	d.Warna(0, 0, 0)
+	d.TeksTengah(160, 12, false, "Diberikan kepada")
+	d.TeksTengah(195, 24, true, s.NamaAnak)
+	d.Garis(d.Lebar/2-180, 203, d.Lebar/2+180, 203, 0.6)
+
+	anakDari := "Putra"
+	if jenisKelamin == "P" {
+		anakDari = "Putri"
+	}
+	d.TeksTengah(225, 12, false, fmt.Sprintf("Lahir %s, %s dari Ibu %s", formatTanggalIndonesia(s.TanggalLahir), anakDari, namaIbu))
+	keterangan := fmt.Sprintf("telah menerima imunisasi dasar lengkap pada %s (umur %d bulan)", formatTanggalIndonesia(*s.TanggalLengkap), *s.UsiaLengkapBulan)
+	d.TeksTengah(245, 12, false, keterangan)
+	if s.Status == imunisasi.IDLTepatWaktu {
+		d.TeksTengah(265, 12, true, "tepat waktu sebelum umur 12 bulan")
+	}
+
+	// Rincian antigen dalam dua kolom
+	const yMulai, jarak = 300.0, 16.0
+	perKolom := (len(s.Diberikan) + 1) / 2
+	for i, j := range s.Diberikan {
+		x := d.Lebar/2 - 250
+		if i >= perKolom {
+			x = d.Lebar/2 + 30
+		}
+		y := yMulai + float64(i%perKolom)*jarak
+		d.Teks(x, y, 10, false, j.NamaImunisasi)
+		if j.TanggalImunisasi != nil {
+			d.TeksKanan(x+220, y, 10, false, j.TanggalImunisasi.Format("02-01-2006"))
+		}
+	}
+
+	yBawah := d.Tinggi - 90
+	tempat := "Posyandu"
+	if namaPosyandu != nil {
+		tempat = "Posyandu " + *namaPosyandu
+	}
+	d.TeksKanan(d.Lebar-90, yBawah-50, 11, false, fmt.Sprintf("%s, %s", tempat, formatTanggalIndonesia(time.Now())))
+	d.Garis(d.Lebar-290, yBawah+5, d.Lebar-90, yBawah+5, 0.6)
+	d.TeksKanan(d.Lebar-90, yBawah+20, 10, false, "Petugas / Kader")
+	return d.Bytes()
+}
@@ -18,6 +18,25 @@ import (
 
 // --- Master Imunisasi Handlers ---
 
+// tanggapiGalatSeriImunisasi menerjemahkan pelanggaran aturan seri dosis master imunisasi menjadi pesan yang jelas
+func tanggapiGalatSeriImunisasi(c *gin.Context, err error) bool {
+	pgErr, ok := err.(*pgconn.PgError)
+	if !ok {
+		return false
+	}
+	switch pgErr.ConstraintName {
+	case "master_imunisasi_seri_dosis_key":
+		c.JSON(http.StatusConflict, gin.H{"error": "Dosis ke-berapa pada seri ini sudah ada."})
+	case "master_imunisasi_usia_check":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Umur minimal tidak boleh melebihi umur maksimal."})
+	case "master_imunisasi_interval_check":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Interval minimal hanya berlaku untuk dosis kedua dan seterusnya dari sebuah seri."})
+	default:
+		return false
+	}
+	return true
+}
+
 // TambahMasterImunisasiHandler menangani penambahan master imunisasi baru
 func TambahMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -31,12 +50,21 @@ func TambahMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
+		dosisKe := 1
+		if payload.DosisKe != nil {
+			dosisKe = *payload.DosisKe
+		}
+
 		_, err := dbpool.Exec(context.Background(),
-			`INSERT INTO master_imunisasi (nama_imunisasi, usia_ideal_bulan, deskripsi) VALUES ($1, $2, $3)`,
-			payload.NamaImunisasi, payload.UsiaIdealBulan, payload.Deskripsi)
+			`INSERT INTO master_imunisasi (nama_imunisasi, usia_ideal_bulan, seri, dosis_ke, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl, deskripsi)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, FALSE), $9)`,
+			payload.NamaImunisasi, payload.UsiaIdealBulan, payload.Seri, dosisKe, payload.UsiaMinHari, payload.UsiaMaxHari, payload.IntervalMinHari, payload.TermasukIdl, payload.Deskripsi)
 
 		if err != nil {
 			log.Printf("ERROR inserting master_imunisasi: %v", err)
+			if tanggapiGalatSeriImunisasi(c, err) {
+				return
+			}
 			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 				if pgErr.ConstraintName == "master_imunisasi_nama_imunisasi_key" { // Ganti dg nama constraint yg benar
 					c.JSON(http.StatusConflict, gin.H{"error": "Nama imunisasi ini sudah ada."})
@@ -61,7 +89,7 @@ func GetMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		daftarImunisasi := make([]models.MasterImunisasi, 0) // Gunakan slice kosong agar return [] bukan null
 		searchQuery := c.Query("search")
-		baseQuery := "SELECT id, nama_imunisasi, usia_ideal_bulan, deskripsi, created_at, updated_at FROM master_imunisasi"
+		baseQuery := "SELECT id, nama_imunisasi, usia_ideal_bulan, seri, dosis_ke, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl, deskripsi, created_at, updated_at FROM master_imunisasi"
 		var args []interface{}
 		query := baseQuery
 
@@ -81,7 +109,7 @@ func GetMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		for rows.Next() {
 			var m models.MasterImunisasi
-			if err := rows.Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan, &m.Deskripsi, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			if err := rows.Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan, &m.Seri, &m.DosisKe, &m.UsiaMinHari, &m.UsiaMaxHari, &m.IntervalMinHari, &m.TermasukIdl, &m.Deskripsi, &m.CreatedAt, &m.UpdatedAt); err != nil {
 				log.Printf("ERROR scanning master_imunisasi: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 				return
@@ -102,7 +130,7 @@ func GetMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 func GetMasterImunisasiSimpleHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		daftarImunisasi := make([]models.MasterImunisasiSimple, 0)
-		query := "SELECT id, nama_imunisasi, usia_ideal_bulan FROM master_imunisasi ORDER BY usia_ideal_bulan ASC, nama_imunisasi ASC"
+		query := "SELECT id, nama_imunisasi, usia_ideal_bulan, seri, dosis_ke FROM master_imunisasi ORDER BY usia_ideal_bulan ASC, nama_imunisasi ASC"
 		rows, err := dbpool.Query(context.Background(), query)
 		if err != nil {
 			log.Printf("ERROR querying master_imunisasi simple: %v", err)
@@ -113,7 +141,7 @@ func GetMasterImunisasiSimpleHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		for rows.Next() {
 			var m models.MasterImunisasiSimple
-			if err := rows.Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan); err != nil {
+			if err := rows.Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan, &m.Seri, &m.DosisKe); err != nil {
 				log.Printf("ERROR scanning master_imunisasi simple: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 				return
@@ -142,8 +170,8 @@ func GetMasterImunisasiByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		var m models.MasterImunisasi
 		err = dbpool.QueryRow(context.Background(),
-			`SELECT id, nama_imunisasi, usia_ideal_bulan, deskripsi, created_at, updated_at FROM master_imunisasi WHERE id = $1`, id).
-			Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan, &m.Deskripsi, &m.CreatedAt, &m.UpdatedAt)
+			`SELECT id, nama_imunisasi, usia_ideal_bulan, seri, dosis_ke, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl, deskripsi, created_at, updated_at FROM master_imunisasi WHERE id = $1`, id).
+			Scan(&m.ID, &m.NamaImunisasi, &m.UsiaIdealBulan, &m.Seri, &m.DosisKe, &m.UsiaMinHari, &m.UsiaMaxHari, &m.IntervalMinHari, &m.TermasukIdl, &m.Deskripsi, &m.CreatedAt, &m.UpdatedAt)
 
 		if err != nil {
 			if err.Error() == "no rows in result set" {
@@ -178,12 +206,21 @@ func UpdateMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
+		dosisKe := 1
+		if payload.DosisKe != nil {
+			dosisKe = *payload.DosisKe
+		}
+
 		_, err = dbpool.Exec(context.Background(),
-			`UPDATE master_imunisasi SET nama_imunisasi = $1, usia_ideal_bulan = $2, deskripsi = $3, updated_at = NOW() WHERE id = $4`,
-			payload.NamaImunisasi, payload.UsiaIdealBulan, payload.Deskripsi, id)
+			`UPDATE master_imunisasi SET nama_imunisasi = $1, usia_ideal_bulan = $2, seri = $3, dosis_ke = $4, usia_min_hari = $5, usia_max_hari = $6,
+                interval_min_hari = $7, termasuk_idl = COALESCE($8, termasuk_idl), deskripsi = $9, updated_at = NOW() WHERE id = $10`,
+			payload.NamaImunisasi, payload.UsiaIdealBulan, payload.Seri, dosisKe, payload.UsiaMinHari, payload.UsiaMaxHari, payload.IntervalMinHari, payload.TermasukIdl, payload.Deskripsi, id)
 
 		if err != nil {
 			log.Printf("ERROR updating master_imunisasi ID %d: %v", id, err)
+			if tanggapiGalatSeriImunisasi(c, err) {
+				return
+			}
 			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 				if pgErr.ConstraintName == "master_imunisasi_nama_imunisasi_key" { // Ganti dg nama constraint yg benar
 					c.JSON(http.StatusConflict, gin.H{"error": "Nama imunisasi ini sudah digunakan."})
@@ -215,6 +252,10 @@ func DeleteMasterImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 					c.JSON(http.StatusConflict, gin.H{"error": "Master imunisasi tidak bisa dihapus karena terhubung dengan riwayat."})
 					return
 				}
+				if pgErr.ConstraintName == "dosis_versi_jadwal_imunisasi_id_master_imunisasi_fkey" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Master imunisasi tidak bisa dihapus karena termasuk dalam versi jadwal yang sudah diterbitkan."})
+					return
+				}
 			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
 			return
@@ -247,9 +288,31 @@ func TambahRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(),
-			`INSERT INTO riwayat_imunisasi (id_anak, id_master_imunisasi, tanggal_imunisasi, catatan, id_kader_pencatat) VALUES ($1, $2, $3, $4, $5)`,
-			payload.IdAnak, payload.IdMasterImunisasi, tglImunisasi, payload.Catatan, kaderId)
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for riwayat imunisasi by kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		if err := kunciAnak(ctx, tx, payload.IdAnak); err != nil {
+			log.Printf("ERROR locking anak %d for riwayat imunisasi: %v", payload.IdAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		peringatan, alasan, ok := validasiDosisImunisasi(ctx, c, dbpool, tx, payload.IdAnak, payload.IdMasterImunisasi, 0, tglImunisasi,
+			payload.KonfirmasiPeringatan, payload.AlasanKonfirmasi)
+		if !ok {
+			return
+		}
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO riwayat_imunisasi (id_anak, id_master_imunisasi, tanggal_imunisasi, catatan, id_kader_pencatat, id_posyandu, peringatan, alasan_konfirmasi, id_batch_vaksin)
+            VALUES ($1, $2, $3, $4, $5, (SELECT id_posyandu FROM kader WHERE id = $5), $6, $7, $8) RETURNING id`,
+			payload.IdAnak, payload.IdMasterImunisasi, tglImunisasi, payload.Catatan, kaderId, peringatan, alasan, payload.IdBatchVaksin).Scan(&id)
 
 		if err != nil {
 			log.Printf("ERROR inserting riwayat imunisasi by kader %d: %v", kaderId, err)
@@ -259,6 +322,8 @@ func TambahRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 					c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
 				} else if pgErr.ConstraintName == "riwayat_imunisasi_id_master_imunisasi_fkey" {
 					c.JSON(http.StatusNotFound, gin.H{"error": "ID Master Imunisasi tidak ditemukan."})
+				} else if pgErr.ConstraintName == "riwayat_imunisasi_id_batch_vaksin_fkey" {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Batch vaksin tidak ditemukan."})
 				} else {
 					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan: Relasi data tidak valid."})
 				}
@@ -267,7 +332,23 @@ func TambahRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
 			return
 		}
-		c.JSON(http.StatusCreated, gin.H{"message": "Riwayat imunisasi berhasil dicatat!"})
+
+		respons := gin.H{"message": "Riwayat imunisasi berhasil dicatat!", "id": id}
+		if payload.IdBatchVaksin != nil {
+			peringatanBatch, ok := pakaiBatchVaksin(ctx, c, tx, *payload.IdBatchVaksin, payload.IdMasterImunisasi, id, kaderId, tglImunisasi)
+			if !ok {
+				return
+			}
+			if peringatanBatch != nil {
+				respons["peringatan_batch"] = *peringatanBatch
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing riwayat imunisasi by kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		c.JSON(http.StatusCreated, respons)
 	}
 }
 
@@ -281,16 +362,20 @@ func GetRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		baseQuery := `
             SELECT
                 r.id, r.id_anak, r.id_master_imunisasi, r.id_kader_pencatat, r.id_kader_updater,
-                r.tanggal_imunisasi, r.catatan, r.created_at, r.updated_at,
+                r.tanggal_imunisasi, r.catatan, r.peringatan, r.alasan_konfirmasi, r.id_posyandu, r.id_batch_vaksin, r.created_at, r.updated_at,
                 a.nama_anak, a.nik_anak,
                 m.nama_imunisasi,
                 kp.nama_lengkap AS nama_kader,
-                ku.nama_lengkap AS nama_kader_updater
+                ku.nama_lengkap AS nama_kader_updater,
+                ps.nama AS nama_posyandu,
+                bv.nomor_batch
             FROM riwayat_imunisasi r
             JOIN anak a ON r.id_anak = a.id
             JOIN master_imunisasi m ON r.id_master_imunisasi = m.id
             LEFT JOIN kader kp ON r.id_kader_pencatat = kp.id
-            LEFT JOIN kader ku ON r.id_kader_updater = ku.id`
+            LEFT JOIN kader ku ON r.id_kader_updater = ku.id
+            LEFT JOIN posyandu ps ON r.id_posyandu = ps.id
+            LEFT JOIN batch_vaksin bv ON r.id_batch_vaksin = bv.id`
 
 		var args []interface{}
 		var conditions []string
@@ -311,6 +396,9 @@ func GetRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 				args = append(args, idAnak)
 				argCounter++
 			}
+		} else if !sertakanNonaktif(c) {
+			// Riwayat satu anak tetap ditampilkan apa pun statusnya
+			conditions = append(conditions, kondisiAnakAktif)
 		}
 		// --- END BLOK TAMBAHAN ---
 
@@ -333,10 +421,10 @@ func GetRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			var r models.RiwayatImunisasi
 			if err := rows.Scan(
 				&r.ID, &r.IdAnak, &r.IdMasterImunisasi, &r.IdKaderPencatat, &r.IdKaderUpdater,
-				&r.TanggalDiberikan, &r.Catatan, &r.CreatedAt, &r.UpdatedAt,
+				&r.TanggalDiberikan, &r.Catatan, &r.Peringatan, &r.AlasanKonfirmasi, &r.IdPosyandu, &r.IdBatchVaksin, &r.CreatedAt, &r.UpdatedAt,
 				&r.NamaAnak, &r.NikAnak,
 				&r.NamaImunisasi,
-				&r.NamaKader, &r.NamaKaderUpdater,
+				&r.NamaKader, &r.NamaKaderUpdater, &r.NamaPosyandu, &r.NomorBatch,
 			); err != nil {
 				log.Printf("ERROR scanning riwayat_imunisasi: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
@@ -368,24 +456,28 @@ func GetRiwayatImunisasiByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		query := `
             SELECT
                 r.id, r.id_anak, r.id_master_imunisasi, r.id_kader_pencatat, r.id_kader_updater,
-                r.tanggal_imunisasi, r.catatan, r.created_at, r.updated_at,
+                r.tanggal_imunisasi, r.catatan, r.peringatan, r.alasan_konfirmasi, r.id_posyandu, r.id_batch_vaksin, r.created_at, r.updated_at,
                 a.nama_anak, a.nik_anak,
                 m.nama_imunisasi,
                 kp.nama_lengkap AS nama_kader,
-                ku.nama_lengkap AS nama_kader_updater
+                ku.nama_lengkap AS nama_kader_updater,
+                ps.nama AS nama_posyandu,
+                bv.nomor_batch
             FROM riwayat_imunisasi r
             JOIN anak a ON r.id_anak = a.id
             JOIN master_imunisasi m ON r.id_master_imunisasi = m.id
             LEFT JOIN kader kp ON r.id_kader_pencatat = kp.id
             LEFT JOIN kader ku ON r.id_kader_updater = ku.id
+            LEFT JOIN posyandu ps ON r.id_posyandu = ps.id
+            LEFT JOIN batch_vaksin bv ON r.id_batch_vaksin = bv.id
             WHERE r.id = $1`
 
 		err = dbpool.QueryRow(context.Background(), query, id).Scan(
 			&r.ID, &r.IdAnak, &r.IdMasterImunisasi, &r.IdKaderPencatat, &r.IdKaderUpdater,
-			&r.TanggalDiberikan, &r.Catatan, &r.CreatedAt, &r.UpdatedAt,
+			&r.TanggalDiberikan, &r.Catatan, &r.Peringatan, &r.AlasanKonfirmasi, &r.IdPosyandu, &r.IdBatchVaksin, &r.CreatedAt, &r.UpdatedAt,
 			&r.NamaAnak, &r.NikAnak,
 			&r.NamaImunisasi,
-			&r.NamaKader, &r.NamaKaderUpdater,
+			&r.NamaKader, &r.NamaKaderUpdater, &r.NamaPosyandu, &r.NomorBatch,
 		)
 
 		if err != nil {
@@ -430,9 +522,54 @@ func UpdateRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(),
-			`UPDATE riwayat_imunisasi SET id_anak = $1, id_master_imunisasi = $2, tanggal_imunisasi = $3, catatan = $4, id_kader_updater = $5, updated_at = NOW() WHERE id = $6`,
-			payload.IdAnak, payload.IdMasterImunisasi, tglImunisasi, payload.Catatan, kaderId, id)
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for riwayat_imunisasi ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		// Anak lama dan anak baru dikunci sebelum baris riwayat agar urutan kunci sama dengan pencatatan baru
+		var idAnakLama int
+		err = tx.QueryRow(ctx, "SELECT id_anak FROM riwayat_imunisasi WHERE id = $1", id).Scan(&idAnakLama)
+		if err == nil {
+			err = kunciAnak(ctx, tx, idAnakLama, payload.IdAnak)
+		}
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan."})
+			} else {
+				log.Printf("ERROR locking anak for riwayat_imunisasi ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			}
+			return
+		}
+		peringatan, alasan, ok := validasiDosisImunisasi(ctx, c, dbpool, tx, payload.IdAnak, payload.IdMasterImunisasi, id, tglImunisasi,
+			payload.KonfirmasiPeringatan, payload.AlasanKonfirmasi)
+		if !ok {
+			return
+		}
+
+		var idBatchLama *int
+		var idMasterLama int
+		var tanggalLama time.Time
+		err = tx.QueryRow(ctx, "SELECT id_batch_vaksin, id_master_imunisasi, tanggal_imunisasi FROM riwayat_imunisasi WHERE id = $1 FOR UPDATE", id).Scan(&idBatchLama, &idMasterLama, &tanggalLama)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying riwayat_imunisasi ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			}
+			return
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE riwayat_imunisasi SET id_anak = $1, id_master_imunisasi = $2, tanggal_imunisasi = $3, catatan = $4, id_kader_updater = $5,
+                peringatan = $6, alasan_konfirmasi = $7, id_batch_vaksin = $8, updated_at = NOW() WHERE id = $9`,
+			payload.IdAnak, payload.IdMasterImunisasi, tglImunisasi, payload.Catatan, kaderId, peringatan, alasan, payload.IdBatchVaksin, id)
 
 		if err != nil {
 			log.Printf("ERROR updating riwayat_imunisasi ID %d by kader %d: %v", id, kaderId, err)
@@ -442,6 +579,8 @@ func UpdateRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 					c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
 				} else if pgErr.ConstraintName == "riwayat_imunisasi_id_master_imunisasi_fkey" {
 					c.JSON(http.StatusNotFound, gin.H{"error": "ID Master Imunisasi tidak ditemukan."})
+				} else if pgErr.ConstraintName == "riwayat_imunisasi_id_batch_vaksin_fkey" {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Batch vaksin tidak ditemukan."})
 				} else {
 					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update: Relasi data tidak valid."})
 				}
@@ -450,7 +589,34 @@ func UpdateRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "Riwayat imunisasi berhasil diperbarui!"})
+
+		respons := gin.H{"message": "Riwayat imunisasi berhasil diperbarui!"}
+		// Batch, vaksin dan tanggal tidak berganti: pemakaian stok tetap. Selain itu pemakaian lama
+		// dibatalkan dan batch diperiksa ulang, termasuk kedaluwarsa pada tanggal imunisasi yang baru.
+		batchSama := idBatchLama != nil && payload.IdBatchVaksin != nil && *idBatchLama == *payload.IdBatchVaksin &&
+			idMasterLama == payload.IdMasterImunisasi && tanggalLama.Equal(tglImunisasi)
+		if !batchSama {
+			if err := kembalikanBatchVaksin(ctx, tx, id); err != nil {
+				log.Printf("ERROR updating pemakaian vaksin for riwayat_imunisasi ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+				return
+			}
+		}
+		if !batchSama && payload.IdBatchVaksin != nil {
+			peringatanBatch, ok := pakaiBatchVaksin(ctx, c, tx, *payload.IdBatchVaksin, payload.IdMasterImunisasi, id, kaderId, tglImunisasi)
+			if !ok {
+				return
+			}
+			if peringatanBatch != nil {
+				respons["peringatan_batch"] = *peringatanBatch
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing riwayat_imunisasi ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		c.JSON(http.StatusOK, respons)
 	}
 }
 
@@ -464,9 +630,32 @@ func DeleteRiwayatImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(), "DELETE FROM riwayat_imunisasi WHERE id = $1", id)
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for deleting riwayat_imunisasi ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		// Dosis yang dihapus mengembalikan stok batch yang dipakainya
+		if err := kembalikanBatchVaksin(ctx, tx, id); err != nil {
+			log.Printf("ERROR restoring stok vaksin for riwayat_imunisasi ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM riwayat_imunisasi WHERE id = $1", id)
 		if err != nil {
 			log.Printf("ERROR deleting riwayat_imunisasi ID %d: %v", id, err)
+			if tanggapiGalatKipiRiwayat(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing delete riwayat_imunisasi ID %d: %v", id, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
 			return
 		}
@@ -0,0 +1,368 @@
+// handlers/jadwal_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// ambilAntigen mengambil seluruh master imunisasi (draf jadwal yang belum tentu diterbitkan) sebagai antigen
+func ambilAntigen(ctx context.Context, dbpool *pgxpool.Pool) ([]imunisasi.Antigen, error) {
+	rows, err := dbpool.Query(ctx,
+		`SELECT id, nama_imunisasi, usia_ideal_bulan, seri, dosis_ke, usia_min_hari, usia_max_hari, interval_min_hari, termasuk_idl
+        FROM master_imunisasi ORDER BY usia_ideal_bulan ASC, seri ASC NULLS FIRST, dosis_ke ASC, nama_imunisasi ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var daftar []imunisasi.Antigen
+	for rows.Next() {
+		var a imunisasi.Antigen
+		if err := rows.Scan(&a.ID, &a.Nama, &a.UsiaIdealBulan, &a.Seri, &a.DosisKe, &a.UsiaMinHari, &a.UsiaMaxHari, &a.IntervalMinHari, &a.TermasukIDL); err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, a)
+	}
+	return daftar, rows.Err()
+}
+
+// ambilKalenderImunisasi mengambil seluruh versi jadwal imunisasi beserta dosisnya. Bila belum ada versi
+// yang diterbitkan, master imunisasi dipakai sebagai satu-satunya versi.
+func ambilKalenderImunisasi(ctx context.Context, dbpool *pgxpool.Pool) (imunisasi.Kalender, error) {
+	rows, err := dbpool.Query(ctx, "SELECT id, nama, berlaku_mulai FROM versi_jadwal_imunisasi ORDER BY berlaku_mulai ASC")
+	if err != nil {
+		return nil, err
+	}
+	var kalender imunisasi.Kalender
+	indeks := make(map[int]int) // id versi -> indeks di kalender
+	for rows.Next() {
+		var v imunisasi.Versi
+		if err := rows.Scan(&v.ID, &v.Nama, &v.BerlakuMulai); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		indeks[v.ID] = len(kalender)
+		kalender = append(kalender, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(kalender) == 0 {
+		antigen, err := ambilAntigen(ctx, dbpool)
+		if err != nil {
+			return nil, err
+		}
+		return imunisasi.Kalender{{Nama: "Master imunisasi", Antigen: antigen}}, nil
+	}
+
+	rows, err = dbpool.Query(ctx,
+		`SELECT d.id_versi, m.id, m.nama_imunisasi, d.usia_ideal_bulan, m.seri, m.dosis_ke, d.usia_min_hari, d.usia_max_hari, d.interval_min_hari, d.termasuk_idl
+        FROM dosis_versi_jadwal_imunisasi d
+        JOIN master_imunisasi m ON d.id_master_imunisasi = m.id
+        ORDER BY d.usia_ideal_bulan ASC, m.seri ASC NULLS FIRST, m.dosis_ke ASC, m.nama_imunisasi ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var idVersi int
+		var a imunisasi.Antigen
+		if err := rows.Scan(&idVersi, &a.ID, &a.Nama, &a.UsiaIdealBulan, &a.Seri, &a.DosisKe, &a.UsiaMinHari, &a.UsiaMaxHari, &a.IntervalMinHari, &a.TermasukIDL); err != nil {
+			return nil, err
+		}
+		v := &kalender[indeks[idVersi]]
+		v.Antigen = append(v.Antigen, a)
+	}
+	return kalender, rows.Err()
+}
+
+// ambilImunisasiDiberikan mengambil tanggal pemberian pertama setiap antigen untuk sejumlah anak:
+// id anak -> id master imunisasi -> tanggal
+func ambilImunisasiDiberikan(ctx context.Context, dbpool *pgxpool.Pool, idAnak []int) (map[int]map[int]time.Time, error) {
+	rows, err := dbpool.Query(ctx,
+		`SELECT id_anak, id_master_imunisasi, MIN(tanggal_imunisasi) FROM riwayat_imunisasi
+        WHERE id_anak = ANY($1) GROUP BY id_anak, id_master_imunisasi`, idAnak)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	diberikan := make(map[int]map[int]time.Time)
+	for rows.Next() {
+		var anak, antigen int
+		var tanggal time.Time
+		if err := rows.Scan(&anak, &antigen, &tanggal); err != nil {
+			return nil, err
+		}
+		if diberikan[anak] == nil {
+			diberikan[anak] = make(map[int]time.Time)
+		}
+		diberikan[anak][antigen] = tanggal
+	}
+	return diberikan, rows.Err()
+}
+
+// jadwalImunisasiAnak menyusun jadwal imunisasi seorang anak pada tanggal acuan menurut versi jadwal
+// yang berlaku bagi kohort kelahirannya
+func jadwalImunisasiAnak(ctx context.Context, dbpool *pgxpool.Pool, idAnak int, tanggalLahir, acuan time.Time) ([]imunisasi.Jadwal, imunisasi.Versi, error) {
+	kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+	if err != nil {
+		return nil, imunisasi.Versi{}, err
+	}
+	diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, []int{idAnak})
+	if err != nil {
+		return nil, imunisasi.Versi{}, err
+	}
+	versi := kalender.UntukKelahiran(tanggalLahir)
+	return imunisasi.Susun(tanggalLahir, versi.Antigen, diberikan[idAnak], acuan), versi, nil
+}
+
+// modelJadwalImunisasi mengubah jadwal hasil perhitungan menjadi bentuk respons
+func modelJadwalImunisasi(j imunisasi.Jadwal) models.JadwalImunisasi {
+	m := models.JadwalImunisasi{
+		IdMasterImunisasi: j.ID,
+		NamaImunisasi:     j.Nama,
+		UsiaIdealBulan:    j.UsiaIdealBulan,
+		Seri:              j.Seri,
+		DosisKe:           j.DosisKe,
+		TanggalJadwal:     j.TanggalJadwal,
+		Status:            j.Status,
+		TanggalImunisasi:  j.TanggalDiberikan,
+	}
+	if j.Status == imunisasi.Terlambat {
+		hari := j.HariTerlambat
+		m.HariTerlambat = &hari
+	}
+	return m
+}
+
+// GetJadwalImunisasiAnakHandler menangani jadwal imunisasi seorang anak: setiap antigen berstatus
+// selesai (dengan tanggal), jatuh tempo, akan datang (dengan tanggal jadwal) atau terlambat (dengan jumlah hari)
+func GetJadwalImunisasiAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		hasil := models.JadwalImunisasiAnak{IdAnak: id, TanggalAcuan: tanggalHariIni(), Jadwal: make([]models.JadwalImunisasi, 0)}
+		err = dbpool.QueryRow(ctx, "SELECT nama_anak, tanggal_lahir FROM anak WHERE id = $1", id).Scan(&hasil.NamaAnak, &hasil.TanggalLahir)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR fetching anak %d for jadwal imunisasi: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			}
+			return
+		}
+
+		jadwal, versi, err := jadwalImunisasiAnak(ctx, dbpool, id, hasil.TanggalLahir, hasil.TanggalAcuan)
+		if err != nil {
+			log.Printf("ERROR building jadwal imunisasi for anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun jadwal imunisasi."})
+			return
+		}
+		hasil.IdVersiJadwal, hasil.NamaVersiJadwal = versi.ID, versi.Nama
+		hasil.Jumlah = map[string]int{imunisasi.Selesai: 0, imunisasi.JatuhTempo: 0, imunisasi.AkanDatang: 0, imunisasi.Terlambat: 0}
+		for _, j := range jadwal {
+			hasil.Jumlah[j.Status]++
+			hasil.Jadwal = append(hasil.Jadwal, modelJadwalImunisasi(j))
+		}
+		c.JSON(http.StatusOK, hasil)
+	}
+}
+
+// GetJatuhTempoImunisasiHandler menangani daftar anak yang perlu diimunisasi pada minggu posyandu mendatang:
+// antigen yang jatuh tempo atau terlambat pada awal minggu, serta yang jadwalnya jatuh dalam minggu tersebut.
+// Query: minggu=YYYY-MM-DD (hari pertama minggu, default hari ini), id_posyandu (opsional).
+func GetJatuhTempoImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mulai := tanggalHariIni()
+		if mingguQuery := c.Query("minggu"); mingguQuery != "" {
+			tgl, err := time.Parse("2006-01-02", mingguQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format minggu salah (YYYY-MM-DD)."})
+				return
+			}
+			mulai = tgl
+		}
+		selesai := mulai.AddDate(0, 0, 6)
+		ctx := context.Background()
+
+		// Balita aktif yang sudah lahir sebelum minggu tersebut berakhir
+		query := `SELECT a.id, a.nama_anak, a.nik_anak, a.tanggal_lahir, i.nama_lengkap, a.id_posyandu, ps.nama
+            FROM anak a
+            JOIN ibu i ON a.id_ibu = i.id
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id
+            WHERE ` + kondisiAnakAktif + ` AND a.tanggal_lahir <= $2 AND a.tanggal_lahir > ($1::date - INTERVAL '60 months')`
+		args := []interface{}{mulai, selesai}
+		if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+			idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+				return
+			}
+			query += " AND a.id_posyandu = $3"
+			args = append(args, idPosyandu)
+		}
+		query += " ORDER BY ps.nama ASC NULLS LAST, a.nama_anak ASC"
+
+		rows, err := dbpool.Query(ctx, query, args...)
+		if err != nil {
+			log.Printf("ERROR querying anak for jatuh tempo imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			return
+		}
+		var daftarAnak []models.AnakJatuhTempoImunisasi
+		var idAnak []int
+		for rows.Next() {
+			var a models.AnakJatuhTempoImunisasi
+			if err := rows.Scan(&a.IdAnak, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.NamaIbu, &a.IdPosyandu, &a.NamaPosyandu); err != nil {
+				rows.Close()
+				log.Printf("ERROR scanning anak for jatuh tempo imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data anak."})
+				return
+			}
+			daftarAnak = append(daftarAnak, a)
+			idAnak = append(idAnak, a.IdAnak)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating anak for jatuh tempo imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data anak."})
+			return
+		}
+
+		kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+		if err != nil {
+			log.Printf("ERROR fetching jadwal imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jadwal imunisasi."})
+			return
+		}
+		diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, idAnak)
+		if err != nil {
+			log.Printf("ERROR fetching riwayat imunisasi for jatuh tempo: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat imunisasi."})
+			return
+		}
+
+		hasil := models.JatuhTempoImunisasi{TanggalMulai: mulai, TanggalSelesai: selesai, Anak: make([]models.AnakJatuhTempoImunisasi, 0)}
+		for _, a := range daftarAnak {
+			for _, j := range imunisasi.Susun(a.TanggalLahir, kalender.UntukKelahiran(a.TanggalLahir).Antigen, diberikan[a.IdAnak], mulai) {
+				if j.Status == imunisasi.Selesai || (j.Status == imunisasi.AkanDatang && j.TanggalJadwal.After(selesai)) {
+					continue
+				}
+				a.Imunisasi = append(a.Imunisasi, modelJadwalImunisasi(j))
+			}
+			if len(a.Imunisasi) > 0 {
+				hasil.Anak = append(hasil.Anak, a)
+			}
+		}
+		hasil.JumlahAnak = len(hasil.Anak)
+		c.JSON(http.StatusOK, hasil)
+	}
+}
+
+// validasiDosisImunisasi memeriksa dosis yang akan dicatat dan menulis respon error bila dosis ditolak
+// atau peringatannya belum dikonfirmasi. idRiwayat adalah catatan yang sedang diubah (0 untuk catatan baru).
+// Dipanggil di dalam transaksi penyimpanan setelah baris anak dikunci dengan kunciAnak, sehingga dua
+// pencatatan bersamaan untuk anak yang sama tidak sama-sama lolos pemeriksaan dosis ganda.
+// Mengembalikan peringatan dan alasan yang akan disimpan; ok bernilai false jika respon sudah dikirim.
+func validasiDosisImunisasi(ctx context.Context, c *gin.Context, dbpool *pgxpool.Pool, tx pgx.Tx, idAnak, idMaster, idRiwayat int, tanggal time.Time,
+	konfirmasi bool, alasanKonfirmasi *string) (peringatan []string, alasan *string, ok bool) {
+	var tanggalLahir time.Time
+	if err := tx.QueryRow(ctx, "SELECT tanggal_lahir FROM anak WHERE id = $1", idAnak).Scan(&tanggalLahir); err != nil {
+		if err.Error() == "no rows in result set" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
+		} else {
+			log.Printf("ERROR fetching anak %d for dose check: %v", idAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+		}
+		return nil, nil, false
+	}
+	kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+	if err != nil {
+		log.Printf("ERROR fetching jadwal imunisasi for dose check: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+		return nil, nil, false
+	}
+	cari := func(a imunisasi.Antigen) bool { return a.ID == idMaster }
+	antigen := kalender.UntukKelahiran(tanggalLahir).Antigen
+	idx := slices.IndexFunc(antigen, cari)
+	if idx < 0 {
+		// Dosis di luar versi jadwal anak (mis. antigen lama) diperiksa dengan parameter master
+		antigen, err = ambilAntigen(ctx, dbpool)
+		if err != nil {
+			log.Printf("ERROR fetching master imunisasi for dose check: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+			return nil, nil, false
+		}
+		if idx = slices.IndexFunc(antigen, cari); idx < 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ID Master Imunisasi tidak ditemukan."})
+			return nil, nil, false
+		}
+	}
+
+	rows, err := tx.Query(ctx, "SELECT id_master_imunisasi, tanggal_imunisasi FROM riwayat_imunisasi WHERE id_anak = $1 AND id <> $2", idAnak, idRiwayat)
+	if err != nil {
+		log.Printf("ERROR fetching riwayat imunisasi for dose check: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+		return nil, nil, false
+	}
+	var riwayat []imunisasi.Pemberian
+	for rows.Next() {
+		var p imunisasi.Pemberian
+		if err := rows.Scan(&p.IdAntigen, &p.Tanggal); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning riwayat imunisasi for dose check: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+			return nil, nil, false
+		}
+		riwayat = append(riwayat, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating riwayat imunisasi for dose check: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data imunisasi."})
+		return nil, nil, false
+	}
+
+	hasil := imunisasi.PeriksaDosis(tanggalLahir, antigen[idx], tanggal, time.Now(), antigen, riwayat)
+	if hasil.Ganda {
+		c.JSON(http.StatusConflict, gin.H{"error": hasil.Galat})
+		return nil, nil, false
+	}
+	if hasil.Galat != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": hasil.Galat})
+		return nil, nil, false
+	}
+	if len(hasil.Peringatan) == 0 {
+		return []string{}, nil, true
+	}
+	if !konfirmasi || alasanKonfirmasi == nil || strings.TrimSpace(*alasanKonfirmasi) == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "Dosis perlu diperiksa ulang. Kirim ulang dengan konfirmasi_peringatan dan alasan_konfirmasi jika data sudah benar.",
+			"peringatan":       hasil.Peringatan,
+			"perlu_konfirmasi": true,
+		})
+		return nil, nil, false
+	}
+	return hasil.Peringatan, alasanKonfirmasi, true
+}
@@ -33,11 +33,15 @@ func RegisterKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		_, err = dbpool.Exec(context.Background(),
-			`INSERT INTO kader (nama_lengkap, nik, no_telepon, username, password) VALUES ($1, $2, $3, $4, $5)`,
-			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Username, string(hashedPassword))
+			`INSERT INTO kader (nama_lengkap, nik, no_telepon, username, password, id_posyandu) VALUES ($1, $2, $3, $4, $5, $6)`,
+			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Username, string(hashedPassword), payload.IdPosyandu)
 
 		if err != nil {
 			log.Printf("ERROR inserting kader: %v", err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" && pgErr.ConstraintName == "kader_id_posyandu_fkey" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "ID Posyandu tidak ditemukan."})
+				return
+			}
 			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 				// ... (handle constraint errors) ...
 				switch pgErr.ConstraintName {
@@ -62,14 +66,14 @@ func GetKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var daftarKader []models.Kader
 		searchQuery := c.Query("search")
-		baseQuery := "SELECT id, nama_lengkap, nik, no_telepon, username, created_at, updated_at FROM kader"
+		baseQuery := "SELECT k.id, k.nama_lengkap, k.nik, k.no_telepon, k.username, k.id_posyandu, ps.nama AS nama_posyandu, k.peran, k.created_at, k.updated_at FROM kader k LEFT JOIN posyandu ps ON k.id_posyandu = ps.id"
 		var args []interface{}
 		query := baseQuery
 		if searchQuery != "" {
-			query += " WHERE nama_lengkap ILIKE $1 OR nik ILIKE $1 OR username ILIKE $1"
+			query += " WHERE k.nama_lengkap ILIKE $1 OR k.nik ILIKE $1 OR k.username ILIKE $1"
 			args = append(args, fmt.Sprintf("%%%s%%", searchQuery))
 		}
-		query += " ORDER BY nama_lengkap ASC"
+		query += " ORDER BY k.nama_lengkap ASC"
 
 		rows, err := dbpool.Query(context.Background(), query, args...)
 		if err != nil {
@@ -81,7 +85,7 @@ func GetKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		for rows.Next() {
 			var k models.Kader
-			if err := rows.Scan(&k.ID, &k.NamaLengkap, &k.NIK, &k.NoTelepon, &k.Username, &k.CreatedAt, &k.UpdatedAt); err != nil {
+			if err := rows.Scan(&k.ID, &k.NamaLengkap, &k.NIK, &k.NoTelepon, &k.Username, &k.IdPosyandu, &k.NamaPosyandu, &k.Peran, &k.CreatedAt, &k.UpdatedAt); err != nil {
 				log.Printf("ERROR scanning kader row: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data kader."})
 				return
@@ -119,11 +123,15 @@ func UpdateKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		_, err = dbpool.Exec(context.Background(),
-			`UPDATE kader SET nama_lengkap = $1, nik = $2, no_telepon = $3, username = $4, updated_at = NOW() WHERE id = $5`,
-			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Username, id)
+			`UPDATE kader SET nama_lengkap = $1, nik = $2, no_telepon = $3, username = $4, id_posyandu = $5, updated_at = NOW() WHERE id = $6`,
+			payload.NamaLengkap, payload.NIK, payload.NoTelepon, payload.Username, payload.IdPosyandu, id)
 
 		if err != nil {
 			log.Printf("ERROR updating kader ID %d: %v", id, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" && pgErr.ConstraintName == "kader_id_posyandu_fkey" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "ID Posyandu tidak ditemukan."})
+				return
+			}
 			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 				// ... (handle constraint errors) ...
 				switch pgErr.ConstraintName {
@@ -145,6 +153,40 @@ func UpdateKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	}
 }
 
+// UbahPeranKaderHandler menangani perubahan peran kader (kader, bidan, admin)
+func UbahPeranKaderHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kader tidak valid"})
+			return
+		}
+		var payload models.UbahPeranKaderPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Peran harus kader, bidan atau admin."})
+			return
+		}
+		// Admin tidak dapat menurunkan perannya sendiri agar selalu ada yang dapat mengelola peran
+		if kaderId, _ := c.Get("kaderId"); kaderId == id && payload.Peran != "admin" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tidak dapat mengubah peran akun sendiri."})
+			return
+		}
+
+		commandTag, err := dbpool.Exec(context.Background(), "UPDATE kader SET peran = $1, updated_at = NOW() WHERE id = $2", payload.Peran, id)
+		if err != nil {
+			log.Printf("ERROR updating peran for kader ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah peran kader."})
+			return
+		}
+		if commandTag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kader tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Peran kader berhasil diubah!", "peran": payload.Peran})
+	}
+}
+
 // ChangePasswordHandler handles changing kader password
 func ChangePasswordHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 	return func(c *gin.Context) {
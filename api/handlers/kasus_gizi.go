@@ -0,0 +1,465 @@
+// handlers/kasus_gizi.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/growth"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// Jarak tindak lanjut kasus (hari): wasting dipantau lebih rapat daripada stunting
+var intervalTindakLanjutHari = map[string]int{
+	"stunting": 30,
+	"wasting":  14,
+}
+
+const kasusGiziSelect = `SELECT kg.id, kg.id_anak, kg.jenis, kg.tingkat, kg.sumber, kg.id_perkembangan_deteksi, kg.tanggal_deteksi, kg.z_score_deteksi, kg.status,
+        kg.dirujuk, kg.tanggal_rujukan, kg.tempat_rujukan, kg.tanggal_tindak_lanjut, kg.id_kader_penanggung_jawab, kg.catatan,
+        kg.hasil_penutupan, kg.tanggal_penutupan, kg.catatan_penutupan, kg.created_at, kg.updated_at, a.nama_anak, kd.nama_lengkap AS nama_kader
+    FROM kasus_gizi kg
+    JOIN anak a ON kg.id_anak = a.id
+    LEFT JOIN kader kd ON kg.id_kader_penanggung_jawab = kd.id`
+
+const pesanKasusGiziTidakDitemukan = "Data kasus gizi tidak ditemukan."
+
+// bukaKasusGiziOtomatis membuka kasus stunting (PB/U atau TB/U < -2 SD) dan wasting (IMT/U < -2 SD, dipakai
+// selama tabel resmi BB/PB-TB belum tersedia) dari hasil klasifikasi sebuah perkembangan.
+// Kasus yang masih terbuka untuk jenis yang sama tidak diduplikasi.
+func bukaKasusGiziOtomatis(ctx context.Context, dbpool *pgxpool.Pool, idAnak, idPerkembangan int, tanggal time.Time, gizi growth.Hasil) error {
+	deteksi := []struct {
+		jenis string
+		z     *float64
+	}{
+		{"stunting", gizi.ZTBU},
+		{"wasting", gizi.ZIMTU},
+	}
+	for _, d := range deteksi {
+		if d.z == nil || *d.z >= -2 {
+			continue
+		}
+		tingkat := "sedang"
+		if *d.z < -3 {
+			tingkat = "berat"
+		}
+		jatuhTempo := tanggal.AddDate(0, 0, intervalTindakLanjutHari[d.jenis])
+		_, err := dbpool.Exec(ctx,
+			`INSERT INTO kasus_gizi (id_anak, jenis, tingkat, sumber, id_perkembangan_deteksi, tanggal_deteksi, z_score_deteksi, tanggal_tindak_lanjut, id_kader_penanggung_jawab)
+            SELECT $1, $2, $3, 'otomatis', p.id, $5, $6, $7, p.id_kader_pencatat FROM perkembangan p WHERE p.id = $4
+            ON CONFLICT (id_anak, jenis) WHERE status = 'terbuka' DO NOTHING`,
+			idAnak, d.jenis, tingkat, idPerkembangan, tanggal, *d.z, jatuhTempo)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BukaKasusGiziHandler menangani pembukaan kasus stunting/wasting secara manual
+func BukaKasusGiziHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.BukaKasusGiziPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		tglDeteksi := tanggalHariIni()
+		if payload.TanggalDeteksi != nil {
+			t, err := time.Parse("2006-01-02", *payload.TanggalDeteksi)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal deteksi salah (YYYY-MM-DD)."})
+				return
+			}
+			tglDeteksi = t
+		}
+		tglTindakLanjut := tglDeteksi.AddDate(0, 0, intervalTindakLanjutHari[payload.Jenis])
+		if payload.TanggalTindakLanjut != nil {
+			t, err := time.Parse("2006-01-02", *payload.TanggalTindakLanjut)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal tindak lanjut salah (YYYY-MM-DD)."})
+				return
+			}
+			tglTindakLanjut = t
+		}
+		tingkat := "sedang"
+		if payload.Tingkat != nil {
+			tingkat = *payload.Tingkat
+		}
+		penanggungJawab := kaderId
+		if payload.IdKaderPenanggungJawab != nil {
+			penanggungJawab = *payload.IdKaderPenanggungJawab
+		}
+
+		var id int
+		err := dbpool.QueryRow(context.Background(),
+			`INSERT INTO kasus_gizi (id_anak, jenis, tingkat, sumber, tanggal_deteksi, tanggal_tindak_lanjut, id_kader_penanggung_jawab, catatan) VALUES ($1, $2, $3, 'manual', $4, $5, $6, $7) RETURNING id`,
+			payload.IdAnak, payload.Jenis, tingkat, tglDeteksi, tglTindakLanjut, penanggungJawab, payload.Catatan).Scan(&id)
+
+		if err != nil {
+			log.Printf("ERROR inserting kasus_gizi by kader %d: %v", kaderId, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok {
+				if pgErr.Code == "23505" && pgErr.ConstraintName == "kasus_gizi_terbuka_key" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Anak ini sudah memiliki kasus " + payload.Jenis + " yang masih terbuka."})
+					return
+				}
+				if pgErr.Code == "23503" {
+					switch pgErr.ConstraintName {
+					case "kasus_gizi_id_anak_fkey":
+						c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
+						return
+					case "kasus_gizi_id_kader_penanggung_jawab_fkey":
+						c.JSON(http.StatusNotFound, gin.H{"error": "Kader penanggung jawab tidak ditemukan."})
+						return
+					}
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan kasus gizi."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Kasus gizi berhasil dibuka!", "id": id})
+	}
+}
+
+// GetKasusGiziHandler menangani pengambilan daftar kasus gizi.
+// Query: status, jenis, id_anak, id_kader (penanggung jawab), include_inactive.
+func GetKasusGiziHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		daftarKasus := make([]models.KasusGizi, 0)
+		query := kasusGiziSelect
+		var args []interface{}
+		var conditions []string
+		argCounter := 1
+
+		for _, filter := range []struct{ param, kolom string }{{"status", "kg.status"}, {"jenis", "kg.jenis"}} {
+			if nilai := c.Query(filter.param); nilai != "" {
+				conditions = append(conditions, fmt.Sprintf("%s = $%d", filter.kolom, argCounter))
+				args = append(args, nilai)
+				argCounter++
+			}
+		}
+		for _, filter := range []struct{ param, kolom string }{{"id_anak", "kg.id_anak"}, {"id_kader", "kg.id_kader_penanggung_jawab"}} {
+			if nilai := c.Query(filter.param); nilai != "" {
+				id, err := strconv.Atoi(nilai)
+				if err == nil && id > 0 {
+					conditions = append(conditions, fmt.Sprintf("%s = $%d", filter.kolom, argCounter))
+					args = append(args, id)
+					argCounter++
+				}
+			}
+		}
+		if c.Query("id_anak") == "" && !sertakanNonaktif(c) {
+			// Kasus satu anak tetap ditampilkan apa pun statusnya
+			conditions = append(conditions, kondisiAnakAktif)
+		}
+
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY kg.status DESC, kg.tanggal_tindak_lanjut ASC, a.nama_anak ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying kasus_gizi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kasus gizi."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			k, err := scanKasusGizi(rows)
+			if err != nil {
+				log.Printf("ERROR scanning kasus_gizi row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data kasus gizi."})
+				return
+			}
+			daftarKasus = append(daftarKasus, k)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating kasus_gizi rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar kasus gizi."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarKasus)
+	}
+}
+
+// GetKasusGiziByIdHandler menangani pengambilan detail kasus gizi beserta riwayat tindak lanjutnya
+func GetKasusGiziByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kasus tidak valid"})
+			return
+		}
+
+		ctx := context.Background()
+		k, err := scanKasusGizi(dbpool.QueryRow(ctx, kasusGiziSelect+" WHERE kg.id = $1", id))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": pesanKasusGiziTidakDitemukan})
+			} else {
+				log.Printf("ERROR querying kasus_gizi by ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			}
+			return
+		}
+
+		rows, err := dbpool.Query(ctx,
+			`SELECT t.id, t.id_kasus, t.tanggal, t.jenis, t.catatan, t.id_kader, kd.nama_lengkap, t.created_at
+            FROM tindak_lanjut_kasus_gizi t LEFT JOIN kader kd ON t.id_kader = kd.id
+            WHERE t.id_kasus = $1 ORDER BY t.tanggal ASC, t.id ASC`, id)
+		if err != nil {
+			log.Printf("ERROR querying tindak lanjut of kasus %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data tindak lanjut."})
+			return
+		}
+		defer rows.Close()
+
+		k.TindakLanjut = make([]models.TindakLanjutKasusGizi, 0)
+		for rows.Next() {
+			var t models.TindakLanjutKasusGizi
+			if err := rows.Scan(&t.ID, &t.IdKasus, &t.Tanggal, &t.Jenis, &t.Catatan, &t.IdKader, &t.NamaKader, &t.CreatedAt); err != nil {
+				log.Printf("ERROR scanning tindak lanjut row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data tindak lanjut."})
+				return
+			}
+			k.TindakLanjut = append(k.TindakLanjut, t)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating tindak lanjut rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data tindak lanjut."})
+			return
+		}
+		c.JSON(http.StatusOK, k)
+	}
+}
+
+// TambahTindakLanjutKasusGiziHandler mencatat tindak lanjut (rujukan, PMT, konseling, dll.)
+// dan memperbarui jatuh tempo tindak lanjut berikutnya
+func TambahTindakLanjutKasusGiziHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idKasus, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kasus tidak valid"})
+			return
+		}
+
+		var payload models.TambahTindakLanjutPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+		tanggal, err := time.Parse("2006-01-02", payload.Tanggal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for tindak lanjut kasus %d: %v", idKasus, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan tindak lanjut."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var jenisKasus, status string
+		err = tx.QueryRow(ctx, "SELECT jenis, status FROM kasus_gizi WHERE id = $1 FOR UPDATE", idKasus).Scan(&jenisKasus, &status)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": pesanKasusGiziTidakDitemukan})
+			} else {
+				log.Printf("ERROR querying kasus_gizi %d: %v", idKasus, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan tindak lanjut."})
+			}
+			return
+		}
+		if status != "terbuka" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Kasus ini sudah ditutup."})
+			return
+		}
+
+		jatuhTempo := tanggal.AddDate(0, 0, intervalTindakLanjutHari[jenisKasus])
+		if payload.TanggalTindakLanjut != nil {
+			t, err := time.Parse("2006-01-02", *payload.TanggalTindakLanjut)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal tindak lanjut salah (YYYY-MM-DD)."})
+				return
+			}
+			jatuhTempo = t
+		}
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO tindak_lanjut_kasus_gizi (id_kasus, tanggal, jenis, catatan, id_kader) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			idKasus, tanggal, payload.Jenis, payload.Catatan, kaderId).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR inserting tindak lanjut kasus %d: %v", idKasus, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan tindak lanjut."})
+			return
+		}
+
+		if payload.Jenis == "rujukan" {
+			_, err = tx.Exec(ctx,
+				`UPDATE kasus_gizi SET dirujuk = TRUE, tanggal_rujukan = $1, tempat_rujukan = COALESCE($2, tempat_rujukan), tanggal_tindak_lanjut = $3, updated_at = NOW() WHERE id = $4`,
+				tanggal, payload.TempatRujukan, jatuhTempo, idKasus)
+		} else {
+			_, err = tx.Exec(ctx, `UPDATE kasus_gizi SET tanggal_tindak_lanjut = $1, updated_at = NOW() WHERE id = $2`, jatuhTempo, idKasus)
+		}
+		if err != nil {
+			log.Printf("ERROR updating kasus_gizi %d after tindak lanjut: %v", idKasus, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan tindak lanjut."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing tindak lanjut kasus %d: %v", idKasus, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan tindak lanjut."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Tindak lanjut berhasil dicatat!", "id": id, "tanggal_tindak_lanjut": jatuhTempo.Format("2006-01-02")})
+	}
+}
+
+// TutupKasusGiziHandler menangani penutupan kasus beserta hasil akhirnya
+func TutupKasusGiziHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kasus tidak valid"})
+			return
+		}
+
+		var payload models.TutupKasusGiziPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hasil penutupan wajib diisi dengan nilai yang valid."})
+			return
+		}
+		tanggal := tanggalHariIni()
+		if payload.Tanggal != nil {
+			t, err := time.Parse("2006-01-02", *payload.Tanggal)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+				return
+			}
+			tanggal = t
+		}
+
+		tag, err := dbpool.Exec(context.Background(),
+			`UPDATE kasus_gizi SET status = 'ditutup', hasil_penutupan = $1, tanggal_penutupan = $2, catatan_penutupan = $3, updated_at = NOW() WHERE id = $4 AND status = 'terbuka'`,
+			payload.Hasil, tanggal, payload.Catatan, id)
+		if err != nil {
+			log.Printf("ERROR closing kasus_gizi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menutup kasus."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kasus tidak ditemukan atau sudah ditutup."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Kasus gizi berhasil ditutup!"})
+	}
+}
+
+// GetKasusGiziTerlambatHandler menangani daftar kasus terbuka yang melewati jatuh tempo tindak lanjut,
+// dikelompokkan per kader penanggung jawab. Query id_kader untuk membatasi satu kader;
+// anak yang tidak aktif lagi hanya ditampilkan dengan include_inactive=true.
+func GetKasusGiziTerlambatHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := kasusGiziSelect + " WHERE kg.status = 'terbuka' AND kg.tanggal_tindak_lanjut < CURRENT_DATE"
+		if !sertakanNonaktif(c) {
+			query += " AND " + kondisiAnakAktif
+		}
+		var args []interface{}
+		if idKaderQuery := c.Query("id_kader"); idKaderQuery != "" {
+			idKader, err := strconv.Atoi(idKaderQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID kader tidak valid"})
+				return
+			}
+			query += " AND kg.id_kader_penanggung_jawab = $1"
+			args = append(args, idKader)
+		}
+		query += " ORDER BY kd.nama_lengkap ASC NULLS LAST, kg.tanggal_tindak_lanjut ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying overdue kasus_gizi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kasus gizi."})
+			return
+		}
+		defer rows.Close()
+
+		hariIni := tanggalHariIni()
+		hasil := make([]models.KasusTerlambatKader, 0)
+		indeks := make(map[int]int) // id kader (0 = tanpa penanggung jawab) -> indeks di hasil
+		for rows.Next() {
+			k, err := scanKasusGizi(rows)
+			if err != nil {
+				log.Printf("ERROR scanning overdue kasus_gizi row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data kasus gizi."})
+				return
+			}
+			terlambat := int(hariIni.Sub(k.TanggalTindakLanjut).Hours() / 24)
+			k.HariTerlambat = &terlambat
+
+			kunci := 0
+			if k.IdKaderPenanggungJawab != nil {
+				kunci = *k.IdKaderPenanggungJawab
+			}
+			i, ok := indeks[kunci]
+			if !ok {
+				hasil = append(hasil, models.KasusTerlambatKader{IdKader: k.IdKaderPenanggungJawab, NamaKader: k.NamaKader, Kasus: make([]models.KasusGizi, 0)})
+				i = len(hasil) - 1
+				indeks[kunci] = i
+			}
+			hasil[i].Kasus = append(hasil[i].Kasus, k)
+			hasil[i].Jumlah++
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating overdue kasus_gizi rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar kasus gizi."})
+			return
+		}
+		c.JSON(http.StatusOK, hasil)
+	}
+}
+
+// scanKasusGizi memindai satu baris hasil kasusGiziSelect
+func scanKasusGizi(row interface{ Scan(dest ...any) error }) (models.KasusGizi, error) {
+	var k models.KasusGizi
+	err := row.Scan(&k.ID, &k.IdAnak, &k.Jenis, &k.Tingkat, &k.Sumber, &k.IdPerkembanganDeteksi, &k.TanggalDeteksi, &k.ZScoreDeteksi, &k.Status,
+		&k.Dirujuk, &k.TanggalRujukan, &k.TempatRujukan, &k.TanggalTindakLanjut, &k.IdKaderPenanggungJawab, &k.Catatan,
+		&k.HasilPenutupan, &k.TanggalPenutupan, &k.CatatanPenutupan, &k.CreatedAt, &k.UpdatedAt, &k.NamaAnak, &k.NamaKader)
+	return k, err
+}
@@ -0,0 +1,601 @@
+// handlers/kehamilan.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// Batas-batas faktor risiko kehamilan (Buku KIA Kemenkes)
+const (
+	lamaKehamilanHari        = 280  // Naegele: HPL = HPHT + 280 hari
+	toleransiLewatHPLHari    = 14   // Lewat 42 minggu dianggap serotinus
+	batasLilaKEKCm           = 23.5 // LILA < 23,5 cm = Kurang Energi Kronis
+	batasSistolikHipertensi  = 140
+	batasDiastolikHipertensi = 90
+	batasTinggiBadanIbuCm    = 145.0
+	batasParaTerlaluBanyak   = 4
+)
+
+const kehamilanSelect = `SELECT k.id, k.id_ibu, k.hpht, k.hpl, k.gravida, k.para, k.abortus, k.tinggi_badan_cm, k.status, k.catatan, k.id_kader_pencatat, k.created_at, k.updated_at, i.nama_lengkap AS nama_ibu, kd.nama_lengkap AS nama_kader,
+        COALESCE((SELECT BOOL_OR(v.lila_cm < %[1]v) FROM kunjungan_anc v WHERE v.id_kehamilan = k.id), FALSE) AS ada_kek,
+        COALESCE((SELECT BOOL_OR(v.tekanan_darah_sistolik >= %[2]d OR v.tekanan_darah_diastolik >= %[3]d) FROM kunjungan_anc v WHERE v.id_kehamilan = k.id), FALSE) AS ada_hipertensi
+    FROM kehamilan k
+    JOIN ibu i ON k.id_ibu = i.id
+    LEFT JOIN kader kd ON k.id_kader_pencatat = kd.id`
+
+const kunjunganANCSelect = `SELECT v.id, v.id_kehamilan, v.tanggal_kunjungan, v.bb_kg, v.lila_cm, v.tekanan_darah_sistolik, v.tekanan_darah_diastolik, v.tablet_fe, v.catatan, v.id_kader_pencatat, v.created_at, v.updated_at, i.nama_lengkap AS nama_ibu, kd.nama_lengkap AS nama_kader, k.hpht
+    FROM kunjungan_anc v
+    JOIN kehamilan k ON v.id_kehamilan = k.id
+    JOIN ibu i ON k.id_ibu = i.id
+    LEFT JOIN kader kd ON v.id_kader_pencatat = kd.id`
+
+// --- Kehamilan Handlers ---
+
+// TambahKehamilanHandler menangani pencatatan kehamilan baru
+func TambahKehamilanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.TambahKehamilanPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		hpht, err := time.Parse("2006-01-02", payload.HPHT)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format HPHT salah (YYYY-MM-DD)."})
+			return
+		}
+		if hpht.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "HPHT tidak boleh di masa depan."})
+			return
+		}
+
+		var id int
+		err = dbpool.QueryRow(context.Background(),
+			`INSERT INTO kehamilan (id_ibu, hpht, hpl, gravida, para, abortus, tinggi_badan_cm, catatan, id_kader_pencatat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			payload.IdIbu, hpht, hitungHPL(hpht), payload.Gravida, payload.Para, payload.Abortus, payload.TinggiBadanCm, payload.Catatan, kaderId).Scan(&id)
+
+		if err != nil {
+			log.Printf("ERROR inserting kehamilan by kader %d: %v", kaderId, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				if pgErr.ConstraintName == "kehamilan_id_ibu_fkey" {
+					c.JSON(http.StatusNotFound, gin.H{"error": "ID Ibu tidak ditemukan."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data kehamilan."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Data kehamilan berhasil dicatat!", "id": id})
+	}
+}
+
+// GetKehamilanHandler menangani pengambilan daftar kehamilan
+func GetKehamilanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		daftarKehamilan := make([]models.Kehamilan, 0)
+		searchQuery := c.Query("search")
+		idIbuQuery := c.Query("id_ibu")
+		statusQuery := c.Query("status")
+
+		var args []interface{}
+		var conditions []string
+		argCounter := 1
+		query := fmt.Sprintf(kehamilanSelect, batasLilaKEKCm, batasSistolikHipertensi, batasDiastolikHipertensi)
+
+		if searchQuery != "" {
+			conditions = append(conditions, fmt.Sprintf("(i.nama_lengkap ILIKE $%d OR i.nik ILIKE $%d)", argCounter, argCounter))
+			args = append(args, fmt.Sprintf("%%%s%%", searchQuery))
+			argCounter++
+		}
+
+		if idIbuQuery != "" {
+			idIbu, err := strconv.Atoi(idIbuQuery)
+			if err == nil && idIbu > 0 {
+				conditions = append(conditions, fmt.Sprintf("k.id_ibu = $%d", argCounter))
+				args = append(args, idIbu)
+				argCounter++
+			}
+		}
+
+		if statusQuery != "" {
+			conditions = append(conditions, fmt.Sprintf("k.status = $%d", argCounter))
+			args = append(args, statusQuery)
+			argCounter++
+		}
+
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY k.hpl ASC, i.nama_lengkap ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying kehamilan: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kehamilan."})
+			return
+		}
+		defer rows.Close()
+
+		hariIni := time.Now()
+		for rows.Next() {
+			var k models.Kehamilan
+			var adaKEK, adaHipertensi bool
+			if err := rows.Scan(&k.ID, &k.IdIbu, &k.HPHT, &k.HPL, &k.Gravida, &k.Para, &k.Abortus, &k.TinggiBadanCm, &k.Status, &k.Catatan, &k.IdKaderPencatat, &k.CreatedAt, &k.UpdatedAt, &k.NamaIbu, &k.NamaKader, &adaKEK, &adaHipertensi); err != nil {
+				log.Printf("ERROR scanning kehamilan row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			lengkapiKehamilan(&k, adaKEK, adaHipertensi, hariIni)
+			daftarKehamilan = append(daftarKehamilan, k)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating kehamilan rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarKehamilan)
+	}
+}
+
+// GetKehamilanByIdHandler menangani pengambilan detail kehamilan beserta kunjungan ANC-nya
+func GetKehamilanByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		var k models.Kehamilan
+		var adaKEK, adaHipertensi bool
+		query := fmt.Sprintf(kehamilanSelect, batasLilaKEKCm, batasSistolikHipertensi, batasDiastolikHipertensi) + " WHERE k.id = $1"
+		err = dbpool.QueryRow(context.Background(), query, id).Scan(&k.ID, &k.IdIbu, &k.HPHT, &k.HPL, &k.Gravida, &k.Para, &k.Abortus, &k.TinggiBadanCm, &k.Status, &k.Catatan, &k.IdKaderPencatat, &k.CreatedAt, &k.UpdatedAt, &k.NamaIbu, &k.NamaKader, &adaKEK, &adaHipertensi)
+
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data kehamilan tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying kehamilan by ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			}
+			return
+		}
+		lengkapiKehamilan(&k, adaKEK, adaHipertensi, time.Now())
+
+		rows, err := dbpool.Query(context.Background(), kunjunganANCSelect+" WHERE v.id_kehamilan = $1 ORDER BY v.tanggal_kunjungan ASC", id)
+		if err != nil {
+			log.Printf("ERROR querying kunjungan_anc for kehamilan %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kunjungan."})
+			return
+		}
+		defer rows.Close()
+
+		k.Kunjungan = make([]models.KunjunganANC, 0)
+		for rows.Next() {
+			v, err := scanKunjunganANC(rows)
+			if err != nil {
+				log.Printf("ERROR scanning kunjungan_anc row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data kunjungan."})
+				return
+			}
+			k.Kunjungan = append(k.Kunjungan, v)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating kunjungan_anc rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses data kunjungan."})
+			return
+		}
+
+		k.Persalinan, err = getPersalinanByKehamilan(context.Background(), dbpool, id)
+		if err != nil {
+			log.Printf("ERROR querying persalinan for kehamilan %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data persalinan."})
+			return
+		}
+		c.JSON(http.StatusOK, k)
+	}
+}
+
+// UpdateKehamilanHandler menangani pembaruan data kehamilan
+func UpdateKehamilanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		var payload models.UpdateKehamilanPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap."})
+			return
+		}
+
+		hpht, err := time.Parse("2006-01-02", payload.HPHT)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format HPHT salah (YYYY-MM-DD)."})
+			return
+		}
+		if hpht.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "HPHT tidak boleh di masa depan."})
+			return
+		}
+
+		if payload.Status == "aktif" {
+			// Kehamilan yang persalinannya sudah dicatat tidak dapat dibuka kembali
+			var adaPersalinan bool
+			if err := dbpool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM persalinan WHERE id_kehamilan = $1)", id).Scan(&adaPersalinan); err != nil {
+				log.Printf("ERROR checking persalinan for kehamilan ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+				return
+			}
+			if adaPersalinan {
+				c.JSON(http.StatusConflict, gin.H{"error": "Kehamilan ini sudah memiliki data persalinan dan tidak dapat diaktifkan kembali."})
+				return
+			}
+		}
+
+		cmdTag, err := dbpool.Exec(context.Background(),
+			`UPDATE kehamilan SET id_ibu = $1, hpht = $2, hpl = $3, gravida = $4, para = $5, abortus = $6, tinggi_badan_cm = $7, status = $8, catatan = $9, updated_at = NOW() WHERE id = $10`,
+			payload.IdIbu, hpht, hitungHPL(hpht), payload.Gravida, payload.Para, payload.Abortus, payload.TinggiBadanCm, payload.Status, payload.Catatan, id)
+
+		if err != nil {
+			log.Printf("ERROR updating kehamilan ID %d: %v", id, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				if pgErr.ConstraintName == "kehamilan_id_ibu_fkey" {
+					c.JSON(http.StatusNotFound, gin.H{"error": "ID Ibu tidak ditemukan."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if cmdTag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Data kehamilan tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Data kehamilan berhasil diperbarui!"})
+	}
+}
+
+// DeleteKehamilanHandler menangani penghapusan data kehamilan
+func DeleteKehamilanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		_, err = dbpool.Exec(context.Background(), "DELETE FROM kehamilan WHERE id = $1", id)
+		if err != nil {
+			log.Printf("ERROR deleting kehamilan ID %d: %v", id, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+				if pgErr.ConstraintName == "kunjungan_anc_id_kehamilan_fkey" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Kehamilan tidak bisa dihapus karena masih memiliki data kunjungan ANC."})
+					return
+				}
+				if pgErr.ConstraintName == "persalinan_id_kehamilan_fkey" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Kehamilan tidak bisa dihapus karena sudah memiliki data persalinan."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Data kehamilan berhasil dihapus!"})
+	}
+}
+
+// --- Kunjungan ANC Handlers ---
+
+// TambahKunjunganANCHandler menangani pencatatan kunjungan ANC baru
+func TambahKunjunganANCHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.TambahKunjunganANCPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		tglKunjungan, err := time.Parse("2006-01-02", payload.TanggalKunjungan)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+			return
+		}
+		if msg := validasiKunjunganANC(dbpool, payload.IdKehamilan, tglKunjungan, payload.TekananDarahSistolik, payload.TekananDarahDiastolik); msg != "" {
+			status := http.StatusBadRequest
+			if msg == pesanKehamilanTidakDitemukan {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+
+		var id int
+		err = dbpool.QueryRow(context.Background(),
+			`INSERT INTO kunjungan_anc (id_kehamilan, tanggal_kunjungan, bb_kg, lila_cm, tekanan_darah_sistolik, tekanan_darah_diastolik, tablet_fe, catatan, id_kader_pencatat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			payload.IdKehamilan, tglKunjungan, payload.BbKg, payload.LilaCm, payload.TekananDarahSistolik, payload.TekananDarahDiastolik, payload.TabletFe, payload.Catatan, kaderId).Scan(&id)
+
+		if err != nil {
+			log.Printf("ERROR inserting kunjungan_anc by kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data kunjungan."})
+			return
+		}
+
+		risiko := faktorRisikoKunjungan(payload.LilaCm, payload.TekananDarahSistolik, payload.TekananDarahDiastolik)
+		c.JSON(http.StatusCreated, gin.H{"message": "Kunjungan ANC berhasil dicatat!", "id": id, "faktor_risiko": risiko})
+	}
+}
+
+// GetKunjunganANCHandler menangani pengambilan daftar kunjungan ANC
+func GetKunjunganANCHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		daftarKunjungan := make([]models.KunjunganANC, 0)
+		searchQuery := c.Query("search")
+		idKehamilanQuery := c.Query("id_kehamilan")
+
+		var args []interface{}
+		var conditions []string
+		argCounter := 1
+		query := kunjunganANCSelect
+
+		if searchQuery != "" {
+			conditions = append(conditions, fmt.Sprintf("(i.nama_lengkap ILIKE $%d OR i.nik ILIKE $%d)", argCounter, argCounter))
+			args = append(args, fmt.Sprintf("%%%s%%", searchQuery))
+			argCounter++
+		}
+
+		if idKehamilanQuery != "" {
+			idKehamilan, err := strconv.Atoi(idKehamilanQuery)
+			if err == nil && idKehamilan > 0 {
+				conditions = append(conditions, fmt.Sprintf("v.id_kehamilan = $%d", argCounter))
+				args = append(args, idKehamilan)
+				argCounter++
+			}
+		}
+
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY v.tanggal_kunjungan DESC, i.nama_lengkap ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying kunjungan_anc: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kunjungan."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scanKunjunganANC(rows)
+			if err != nil {
+				log.Printf("ERROR scanning kunjungan_anc row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftarKunjungan = append(daftarKunjungan, v)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating kunjungan_anc rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarKunjungan)
+	}
+}
+
+// GetKunjunganANCByIdHandler menangani pengambilan kunjungan ANC berdasarkan ID
+func GetKunjunganANCByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		v, err := scanKunjunganANC(dbpool.QueryRow(context.Background(), kunjunganANCSelect+" WHERE v.id = $1", id))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying kunjungan_anc by ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, v)
+	}
+}
+
+// UpdateKunjunganANCHandler menangani pembaruan kunjungan ANC
+func UpdateKunjunganANCHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		var payload models.UpdateKunjunganANCPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap."})
+			return
+		}
+
+		tglKunjungan, err := time.Parse("2006-01-02", payload.TanggalKunjungan)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+			return
+		}
+		if msg := validasiKunjunganANC(dbpool, payload.IdKehamilan, tglKunjungan, payload.TekananDarahSistolik, payload.TekananDarahDiastolik); msg != "" {
+			status := http.StatusBadRequest
+			if msg == pesanKehamilanTidakDitemukan {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+
+		cmdTag, err := dbpool.Exec(context.Background(),
+			`UPDATE kunjungan_anc SET id_kehamilan = $1, tanggal_kunjungan = $2, bb_kg = $3, lila_cm = $4, tekanan_darah_sistolik = $5, tekanan_darah_diastolik = $6, tablet_fe = $7, catatan = $8, updated_at = NOW() WHERE id = $9`,
+			payload.IdKehamilan, tglKunjungan, payload.BbKg, payload.LilaCm, payload.TekananDarahSistolik, payload.TekananDarahDiastolik, payload.TabletFe, payload.Catatan, id)
+
+		if err != nil {
+			log.Printf("ERROR updating kunjungan_anc ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if cmdTag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Data kunjungan ANC tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Kunjungan ANC berhasil diperbarui!"})
+	}
+}
+
+// DeleteKunjunganANCHandler menangani penghapusan kunjungan ANC
+func DeleteKunjunganANCHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		_, err = dbpool.Exec(context.Background(), "DELETE FROM kunjungan_anc WHERE id = $1", id)
+		if err != nil {
+			log.Printf("ERROR deleting kunjungan_anc ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Kunjungan ANC berhasil dihapus!"})
+	}
+}
+
+// --- Helper Kehamilan ---
+
+const pesanKehamilanTidakDitemukan = "ID Kehamilan tidak ditemukan."
+
+// hitungHPL menghitung hari perkiraan lahir dari HPHT (rumus Naegele)
+func hitungHPL(hpht time.Time) time.Time {
+	return hpht.AddDate(0, 0, lamaKehamilanHari)
+}
+
+// hitungUsiaKehamilanMinggu menghitung usia kehamilan (minggu penuh) pada tanggal tertentu
+func hitungUsiaKehamilanMinggu(hpht, tanggal time.Time) int {
+	hari := int(tanggal.Sub(hpht).Hours() / 24)
+	if hari < 0 {
+		return 0
+	}
+	return hari / 7
+}
+
+// validasiKunjunganANC memeriksa kehamilan yang dirujuk dan isian kunjungan.
+// Mengembalikan pesan error (kosong jika valid).
+func validasiKunjunganANC(dbpool *pgxpool.Pool, idKehamilan int, tglKunjungan time.Time, sistolik, diastolik *int) string {
+	if (sistolik == nil) != (diastolik == nil) {
+		return "Tekanan darah sistolik dan diastolik harus diisi bersamaan."
+	}
+	var hpht time.Time
+	err := dbpool.QueryRow(context.Background(), "SELECT hpht FROM kehamilan WHERE id = $1", idKehamilan).Scan(&hpht)
+	if err != nil {
+		if err.Error() != "no rows in result set" {
+			log.Printf("ERROR checking kehamilan %d: %v", idKehamilan, err)
+		}
+		return pesanKehamilanTidakDitemukan
+	}
+	if tglKunjungan.Before(hpht) {
+		return "Tanggal kunjungan tidak boleh sebelum HPHT."
+	}
+	return ""
+}
+
+// faktorRisikoKunjungan menentukan faktor risiko dari hasil satu kunjungan ANC
+func faktorRisikoKunjungan(lilaCm *float64, sistolik, diastolik *int) []string {
+	risiko := make([]string, 0)
+	if lilaCm != nil && *lilaCm < batasLilaKEKCm {
+		risiko = append(risiko, "kek")
+	}
+	if (sistolik != nil && *sistolik >= batasSistolikHipertensi) || (diastolik != nil && *diastolik >= batasDiastolikHipertensi) {
+		risiko = append(risiko, "hipertensi")
+	}
+	return risiko
+}
+
+// lengkapiKehamilan mengisi usia kehamilan dan faktor risiko yang dihitung dari data kehamilan
+// serta ringkasan hasil kunjungan ANC (adaKEK, adaHipertensi).
+func lengkapiKehamilan(k *models.Kehamilan, adaKEK, adaHipertensi bool, hariIni time.Time) {
+	k.FaktorRisiko = make([]string, 0)
+	if k.Para >= batasParaTerlaluBanyak {
+		k.FaktorRisiko = append(k.FaktorRisiko, "terlalu_banyak_anak")
+	}
+	if k.Abortus > 0 {
+		k.FaktorRisiko = append(k.FaktorRisiko, "riwayat_keguguran")
+	}
+	if k.TinggiBadanCm != nil && *k.TinggiBadanCm < batasTinggiBadanIbuCm {
+		k.FaktorRisiko = append(k.FaktorRisiko, "tinggi_badan_kurang")
+	}
+	if adaKEK {
+		k.FaktorRisiko = append(k.FaktorRisiko, "kek")
+	}
+	if adaHipertensi {
+		k.FaktorRisiko = append(k.FaktorRisiko, "hipertensi")
+	}
+	if k.Status == "aktif" {
+		usia := hitungUsiaKehamilanMinggu(k.HPHT, hariIni)
+		k.UsiaKehamilanMinggu = &usia
+		if hariIni.After(k.HPL.AddDate(0, 0, toleransiLewatHPLHari)) {
+			k.FaktorRisiko = append(k.FaktorRisiko, "lewat_waktu")
+		}
+	}
+}
+
+// scanKunjunganANC memindai satu baris hasil kunjunganANCSelect dan melengkapi field turunannya
+func scanKunjunganANC(row interface{ Scan(dest ...any) error }) (models.KunjunganANC, error) {
+	var v models.KunjunganANC
+	var hpht time.Time
+	err := row.Scan(&v.ID, &v.IdKehamilan, &v.TanggalKunjungan, &v.BbKg, &v.LilaCm, &v.TekananDarahSistolik, &v.TekananDarahDiastolik, &v.TabletFe, &v.Catatan, &v.IdKaderPencatat, &v.CreatedAt, &v.UpdatedAt, &v.NamaIbu, &v.NamaKader, &hpht)
+	if err != nil {
+		return v, err
+	}
+	v.UsiaKehamilanMinggu = hitungUsiaKehamilanMinggu(hpht, v.TanggalKunjungan)
+	v.FaktorRisiko = faktorRisikoKunjungan(v.LilaCm, v.TekananDarahSistolik, v.TekananDarahDiastolik)
+	return v, nil
+}
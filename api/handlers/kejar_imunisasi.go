@@ -0,0 +1,103 @@
+// handlers/kejar_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// urutanStatusKejar menempatkan dosis yang masih perlu diberikan di awal rencana
+var urutanStatusKejar = map[string]int{
+	imunisasi.KejarBisaDiberikan:      0,
+	imunisasi.KejarMenunggu:           1,
+	imunisasi.KejarDiberikan:          2,
+	imunisasi.KejarTidakDiindikasikan: 3,
+}
+
+// modelDosisKejar mengubah satu dosis rencana kejar menjadi bentuk respons API
+func modelDosisKejar(d imunisasi.DosisKejar) models.DosisKejarImunisasi {
+	m := models.DosisKejarImunisasi{
+		IdMasterImunisasi: d.ID,
+		NamaImunisasi:     d.Nama,
+		Seri:              d.Seri,
+		DosisKe:           d.DosisKe,
+		Status:            d.Status,
+		TanggalImunisasi:  d.TanggalDiberikan,
+		TanggalTercepat:   d.TanggalTercepat,
+		BatasTanggal:      d.BatasTanggal,
+	}
+	if d.Alasan != "" {
+		alasan := d.Alasan
+		m.Alasan = &alasan
+	}
+	return m
+}
+
+// GetKejarImunisasiAnakHandler menyusun rencana imunisasi kejar untuk anak yang terlambat memulai
+// atau melewatkan dosis, menurut umur anak hari ini dan riwayat imunisasinya: tanggal tercepat
+// setiap dosis yang tersisa, dan dosis yang sudah tidak diindikasikan karena melewati umur maksimal
+func GetKejarImunisasiAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		hasil := models.KejarImunisasiAnak{IdAnak: id, TanggalAcuan: tanggalHariIni(), Dosis: make([]models.DosisKejarImunisasi, 0)}
+		err = dbpool.QueryRow(ctx, "SELECT nama_anak, tanggal_lahir FROM anak WHERE id = $1", id).Scan(&hasil.NamaAnak, &hasil.TanggalLahir)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR fetching anak %d for kejar imunisasi: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			}
+			return
+		}
+		hasil.UsiaHari = int(hasil.TanggalAcuan.Sub(hasil.TanggalLahir).Hours() / 24)
+
+		kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+		if err != nil {
+			log.Printf("ERROR fetching jadwal imunisasi for kejar imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jadwal imunisasi."})
+			return
+		}
+		diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, []int{id})
+		if err != nil {
+			log.Printf("ERROR fetching riwayat imunisasi for kejar imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat imunisasi."})
+			return
+		}
+		versi := kalender.UntukKelahiran(hasil.TanggalLahir)
+		hasil.IdVersiJadwal, hasil.NamaVersiJadwal = versi.ID, versi.Nama
+
+		rencana := imunisasi.Kejar(hasil.TanggalLahir, versi.Antigen, diberikan[id], hasil.TanggalAcuan)
+		sort.SliceStable(rencana, func(i, j int) bool {
+			a, b := rencana[i], rencana[j]
+			if urutanStatusKejar[a.Status] != urutanStatusKejar[b.Status] {
+				return urutanStatusKejar[a.Status] < urutanStatusKejar[b.Status]
+			}
+			if a.TanggalTercepat != nil && b.TanggalTercepat != nil {
+				return a.TanggalTercepat.Before(*b.TanggalTercepat)
+			}
+			return false
+		})
+		hasil.Jumlah = map[string]int{imunisasi.KejarBisaDiberikan: 0, imunisasi.KejarMenunggu: 0, imunisasi.KejarDiberikan: 0, imunisasi.KejarTidakDiindikasikan: 0}
+		for _, d := range rencana {
+			hasil.Jumlah[d.Status]++
+			hasil.Dosis = append(hasil.Dosis, modelDosisKejar(d))
+		}
+		c.JSON(http.StatusOK, hasil)
+	}
+}
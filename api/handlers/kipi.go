@@ -0,0 +1,391 @@
+// handlers/kipi.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+const kipiSelect = `SELECT k.id, k.id_riwayat_imunisasi, k.gejala, k.waktu_mulai, k.keparahan, k.hasil, k.serius, k.tindakan,
+        k.dirujuk, k.tanggal_rujukan, k.tempat_rujukan, k.id_kader_pelapor, k.id_kader_updater, k.created_at, k.updated_at,
+        r.id_anak, a.nama_anak, r.id_master_imunisasi, m.nama_imunisasi, r.tanggal_imunisasi, r.id_batch_vaksin, bv.nomor_batch,
+        kp.nama_lengkap AS nama_kader_pelapor
+    FROM kipi k
+    JOIN riwayat_imunisasi r ON k.id_riwayat_imunisasi = r.id
+    JOIN anak a ON r.id_anak = a.id
+    JOIN master_imunisasi m ON r.id_master_imunisasi = m.id
+    LEFT JOIN batch_vaksin bv ON r.id_batch_vaksin = bv.id
+    LEFT JOIN kader kp ON k.id_kader_pelapor = kp.id`
+
+const pesanKipiTidakDitemukan = "Laporan KIPI tidak ditemukan."
+
+// Peran yang menerima notifikasi KIPI serius
+var penerimaNotifikasiKipi = []string{"admin", "bidan"}
+
+// kipiSerius menandai KIPI berat atau yang berakibat rawat inap, gejala sisa atau kematian
+func kipiSerius(keparahan, hasil string) bool {
+	return keparahan == "berat" || hasil == "rawat_inap" || hasil == "gejala_sisa" || hasil == "meninggal"
+}
+
+func scanKipi(row pgx.Row) (models.Kipi, error) {
+	var k models.Kipi
+	err := row.Scan(&k.ID, &k.IdRiwayatImunisasi, &k.Gejala, &k.WaktuMulai, &k.Keparahan, &k.Hasil, &k.Serius, &k.Tindakan,
+		&k.Dirujuk, &k.TanggalRujukan, &k.TempatRujukan, &k.IdKaderPelapor, &k.IdKaderUpdater, &k.CreatedAt, &k.UpdatedAt,
+		&k.IdAnak, &k.NamaAnak, &k.IdMasterImunisasi, &k.NamaImunisasi, &k.TanggalImunisasi, &k.IdBatchVaksin, &k.NomorBatch,
+		&k.NamaKaderPelapor)
+	return k, err
+}
+
+// nilaiKipi memvalidasi payload KIPI terhadap riwayat imunisasinya. Riwayat dikunci selama transaksi.
+// ok bernilai false bila respons galat sudah dikirim.
+func nilaiKipi(ctx context.Context, c *gin.Context, tx pgx.Tx, payload models.KipiPayload) (waktuMulai time.Time, tanggalRujukan *time.Time, hasil string, ok bool) {
+	waktuMulai, err := time.ParseInLocation("2006-01-02T15:04", payload.WaktuMulai, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format waktu mulai gejala salah (YYYY-MM-DDTHH:MM)."})
+		return
+	}
+	if payload.TanggalRujukan != nil {
+		t, err := time.Parse("2006-01-02", *payload.TanggalRujukan)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal rujukan salah (YYYY-MM-DD)."})
+			return
+		}
+		tanggalRujukan = &t
+	}
+	hasil = payload.Hasil
+	if hasil == "" {
+		hasil = "belum_diketahui"
+	}
+
+	var tanggalImunisasi time.Time
+	err = tx.QueryRow(ctx, "SELECT tanggal_imunisasi FROM riwayat_imunisasi WHERE id = $1 FOR SHARE", payload.IdRiwayatImunisasi).Scan(&tanggalImunisasi)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Riwayat imunisasi tidak ditemukan."})
+		} else {
+			log.Printf("ERROR querying riwayat_imunisasi %d for kipi: %v", payload.IdRiwayatImunisasi, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+		}
+		return
+	}
+	awalHariImunisasi := time.Date(tanggalImunisasi.Year(), tanggalImunisasi.Month(), tanggalImunisasi.Day(), 0, 0, 0, 0, time.Local)
+	if waktuMulai.Before(awalHariImunisasi) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Gejala tidak mungkin muncul sebelum imunisasi (%s).", tanggalImunisasi.Format("2006-01-02"))})
+		return
+	}
+	if waktuMulai.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Waktu mulai gejala tidak boleh di masa depan."})
+		return
+	}
+	return waktuMulai, tanggalRujukan, hasil, true
+}
+
+// notifikasiKipiSerius mengirim notifikasi KIPI serius ke admin dan bidan
+func notifikasiKipiSerius(ctx context.Context, tx pgx.Tx, k models.Kipi) error {
+	imunisasi := k.NamaImunisasi
+	if k.NomorBatch != nil {
+		imunisasi += " batch " + *k.NomorBatch
+	}
+	judul := fmt.Sprintf("KIPI serius: %s", k.NamaAnak)
+	pesan := fmt.Sprintf("%s mengalami KIPI (%s, hasil %s) setelah imunisasi %s tanggal %s. Gejala: %s.",
+		k.NamaAnak, k.Keparahan, strings.ReplaceAll(k.Hasil, "_", " "), imunisasi, k.TanggalImunisasi.Format("2006-01-02"), strings.Join(k.Gejala, ", "))
+	return kirimNotifikasiPeran(ctx, tx, penerimaNotifikasiKipi, "kipi_serius", judul, pesan, k.ID)
+}
+
+// TambahKipiHandler mencatat laporan KIPI untuk sebuah riwayat imunisasi.
+// KIPI serius langsung dinotifikasikan ke admin dan bidan.
+func TambahKipiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.KipiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for kipi by kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		waktuMulai, tanggalRujukan, hasil, ok := nilaiKipi(ctx, c, tx, payload)
+		if !ok {
+			return
+		}
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO kipi (id_riwayat_imunisasi, gejala, waktu_mulai, keparahan, hasil, serius, tindakan, dirujuk, tanggal_rujukan, tempat_rujukan, id_kader_pelapor)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+			payload.IdRiwayatImunisasi, payload.Gejala, waktuMulai, payload.Keparahan, hasil, kipiSerius(payload.Keparahan, hasil),
+			payload.Tindakan, payload.Dirujuk, tanggalRujukan, payload.TempatRujukan, kaderId).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR inserting kipi by kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+			return
+		}
+
+		k, err := scanKipi(tx.QueryRow(ctx, kipiSelect+" WHERE k.id = $1", id))
+		if err != nil {
+			log.Printf("ERROR fetching kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+			return
+		}
+		if k.Serius {
+			if err := notifikasiKipiSerius(ctx, tx, k); err != nil {
+				log.Printf("ERROR sending notifikasi for kipi %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+				return
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan laporan KIPI."})
+			return
+		}
+		c.JSON(http.StatusCreated, k)
+	}
+}
+
+// GetKipiHandler menampilkan daftar laporan KIPI, terbaru lebih dulu.
+// Query: vaksin (nama imunisasi atau seri), nomor_batch, id_batch, id_master_imunisasi, id_anak, serius=true,
+// start dan end (YYYY-MM-DD, waktu mulai gejala), include_inactive.
+func GetKipiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		daftar := make([]models.Kipi, 0)
+		query := kipiSelect
+		var args []interface{}
+		var conditions []string
+		argCounter := 1
+
+		if vaksin := c.Query("vaksin"); vaksin != "" {
+			conditions = append(conditions, fmt.Sprintf("(m.nama_imunisasi ILIKE $%d OR m.seri ILIKE $%d)", argCounter, argCounter))
+			args = append(args, fmt.Sprintf("%%%s%%", vaksin))
+			argCounter++
+		}
+		if nomorBatch := c.Query("nomor_batch"); nomorBatch != "" {
+			conditions = append(conditions, fmt.Sprintf("LOWER(bv.nomor_batch) = LOWER($%d)", argCounter))
+			args = append(args, nomorBatch)
+			argCounter++
+		}
+		for _, filter := range []struct{ param, kolom string }{{"id_batch", "r.id_batch_vaksin"}, {"id_master_imunisasi", "r.id_master_imunisasi"}, {"id_anak", "r.id_anak"}} {
+			if nilai := c.Query(filter.param); nilai != "" {
+				id, err := strconv.Atoi(nilai)
+				if err == nil && id > 0 {
+					conditions = append(conditions, fmt.Sprintf("%s = $%d", filter.kolom, argCounter))
+					args = append(args, id)
+					argCounter++
+				}
+			}
+		}
+		if c.Query("serius") == "true" {
+			conditions = append(conditions, "k.serius")
+		}
+		if c.Query("id_anak") == "" && c.Query("id_batch") == "" && c.Query("nomor_batch") == "" && !sertakanNonaktif(c) {
+			// Laporan satu anak atau satu batch (penelusuran KIPI) tetap ditampilkan apa pun status anaknya
+			conditions = append(conditions, kondisiAnakAktif)
+		}
+		for _, filter := range []struct{ param, operator string }{{"start", ">="}, {"end", "<"}} {
+			if nilai := c.Query(filter.param); nilai != "" {
+				t, err := time.Parse("2006-01-02", nilai)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal tidak valid (YYYY-MM-DD)"})
+					return
+				}
+				if filter.param == "end" {
+					t = t.AddDate(0, 0, 1) // Inklusif sampai akhir hari
+				}
+				conditions = append(conditions, fmt.Sprintf("k.waktu_mulai::date %s $%d", filter.operator, argCounter))
+				args = append(args, t)
+				argCounter++
+			}
+		}
+
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY k.waktu_mulai DESC, k.id DESC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying kipi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			k, err := scanKipi(rows)
+			if err != nil {
+				log.Printf("ERROR scanning kipi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, k)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating kipi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// GetKipiByIdHandler menampilkan satu laporan KIPI
+func GetKipiByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		k, err := scanKipi(dbpool.QueryRow(context.Background(), kipiSelect+" WHERE k.id = $1", id))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": pesanKipiTidakDitemukan})
+			} else {
+				log.Printf("ERROR querying kipi %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, k)
+	}
+}
+
+// UpdateKipiHandler memperbarui laporan KIPI (mis. hasil akhir atau rujukan).
+// Laporan yang baru menjadi serius ikut dinotifikasikan.
+func UpdateKipiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+		var payload models.KipiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var seriusLama bool
+		if err := tx.QueryRow(ctx, "SELECT serius FROM kipi WHERE id = $1 FOR UPDATE", id).Scan(&seriusLama); err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": pesanKipiTidakDitemukan})
+			} else {
+				log.Printf("ERROR querying kipi %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			}
+			return
+		}
+		waktuMulai, tanggalRujukan, hasil, ok := nilaiKipi(ctx, c, tx, payload)
+		if !ok {
+			return
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE kipi SET id_riwayat_imunisasi = $1, gejala = $2, waktu_mulai = $3, keparahan = $4, hasil = $5, serius = $6, tindakan = $7,
+                dirujuk = $8, tanggal_rujukan = $9, tempat_rujukan = $10, id_kader_updater = $11, updated_at = NOW() WHERE id = $12`,
+			payload.IdRiwayatImunisasi, payload.Gejala, waktuMulai, payload.Keparahan, hasil, kipiSerius(payload.Keparahan, hasil),
+			payload.Tindakan, payload.Dirujuk, tanggalRujukan, payload.TempatRujukan, kaderId, id)
+		if err != nil {
+			log.Printf("ERROR updating kipi %d by kader %d: %v", id, kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+
+		k, err := scanKipi(tx.QueryRow(ctx, kipiSelect+" WHERE k.id = $1", id))
+		if err != nil {
+			log.Printf("ERROR fetching kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if k.Serius && !seriusLama {
+			if err := notifikasiKipiSerius(ctx, tx, k); err != nil {
+				log.Printf("ERROR sending notifikasi for kipi %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+				return
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		c.JSON(http.StatusOK, k)
+	}
+}
+
+// DeleteKipiHandler menghapus laporan KIPI
+func DeleteKipiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+			return
+		}
+
+		tag, err := dbpool.Exec(context.Background(), "DELETE FROM kipi WHERE id = $1", id)
+		if err != nil {
+			log.Printf("ERROR deleting kipi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": pesanKipiTidakDitemukan})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Laporan KIPI berhasil dihapus!"})
+	}
+}
+
+// tanggapiGalatKipiRiwayat menangani penghapusan riwayat imunisasi yang masih memiliki laporan KIPI
+func tanggapiGalatKipiRiwayat(c *gin.Context, err error) bool {
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" && pgErr.ConstraintName == "kipi_id_riwayat_imunisasi_fkey" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Riwayat imunisasi ini memiliki laporan KIPI dan tidak dapat dihapus."})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// handlers/kms.go
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nadhifhafizp/api/growth"
+)
+
+// kunciAnak mengunci baris anak (urut id agar dua transaksi tidak saling menunggu) sebelum
+// perkembangannya ditulis, sehingga evaluasi KMS di transaksi yang sama tidak perlu menaikkan
+// kunci foreign key yang sudah diambil penyimpanan lain menjadi FOR UPDATE
+func kunciAnak(ctx context.Context, tx pgx.Tx, ids ...int) error {
+	_, err := tx.Exec(ctx, "SELECT id FROM anak WHERE id = ANY($1) ORDER BY id FOR UPDATE", ids)
+	return err
+}
+
+// evaluasiKMSAnak menghitung ulang status KMS seluruh penimbangan seorang anak secara kronologis.
+// Dipanggil di dalam transaksi yang menambah, mengubah atau menghapus perkembangan anak, sehingga
+// penimbangan yang disisipkan di tengah riwayat ikut memperbarui penilaian penimbangan sesudahnya
+// dan penyimpanan gagal seluruhnya bila evaluasi gagal.
+func evaluasiKMSAnak(ctx context.Context, tx pgx.Tx, idAnak int) error {
+	var anak dataAnakGizi
+	// Kunci baris anak agar dua penyimpanan bersamaan tidak saling menimpa hasil evaluasi
+	if err := tx.QueryRow(ctx, "SELECT tanggal_lahir, jenis_kelamin, usia_kehamilan_lahir_minggu FROM anak WHERE id = $1 FOR UPDATE", idAnak).Scan(&anak.TanggalLahir, &anak.JenisKelamin, &anak.UsiaKehamilanLahirMinggu); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, tanggal_pemeriksaan, bb_kg, zs_bbu FROM perkembangan WHERE id_anak = $1 ORDER BY tanggal_pemeriksaan ASC, id ASC`, idAnak)
+	if err != nil {
+		return err
+	}
+	type baris struct {
+		id      int
+		tanggal time.Time
+		bbKg    *float64
+		zBBU    *float64
+	}
+	var daftar []baris
+	for rows.Next() {
+		var b baris
+		if err := rows.Scan(&b.id, &b.tanggal, &b.bbKg, &b.zBBU); err != nil {
+			rows.Close()
+			return err
+		}
+		daftar = append(daftar, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var sebelumnya *growth.PenimbanganKMS
+	var hasilSebelumnya *growth.HasilKMS
+	for _, b := range daftar {
+		if b.bbKg == nil {
+			// Kunjungan tanpa penimbangan tidak dinilai dan tidak menjadi pembanding
+			if _, err := tx.Exec(ctx, `UPDATE perkembangan SET kms_status = NULL, kms_2t = FALSE, kms_bgm = FALSE, kenaikan_bb_gram = NULL, kbm_gram = NULL WHERE id = $1`, b.id); err != nil {
+				return err
+			}
+			continue
+		}
+		kini := growth.PenimbanganKMS{
+			Tahun:    b.tanggal.Year(),
+			Bulan:    int(b.tanggal.Month()),
+			UsiaHari: anak.usiaPenilaian(b.tanggal),
+			BbKg:     *b.bbKg,
+			ZBBU:     b.zBBU,
+		}
+		hasil := growth.EvaluasiKMS(kini, sebelumnya, hasilSebelumnya)
+		if _, err := tx.Exec(ctx,
+			`UPDATE perkembangan SET kms_status = $1, kms_2t = $2, kms_bgm = $3, kenaikan_bb_gram = $4, kbm_gram = $5 WHERE id = $6`,
+			hasil.Status, hasil.DuaT, hasil.BGM, hasil.KenaikanGram, hasil.KBMGram, b.id); err != nil {
+			return err
+		}
+		sebelumnya = &kini
+		hasilSebelumnya = &hasil
+	}
+	return nil
+}
@@ -51,6 +51,14 @@ func GetLaporanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			handleLaporanPerkembangan(c, dbpool, startDate, endDate)
 		case "imunisasi":
 			handleLaporanImunisasi(c, dbpool, startDate, endDate)
+		case "skdn":
+			handleLaporanSKDN(c, dbpool)
+		case "idl":
+			handleLaporanIDL(c, dbpool)
+		case "stok-vaksin":
+			handleLaporanStokVaksin(c, dbpool)
+		case "cakupan-imunisasi":
+			handleLaporanCakupanImunisasi(c, dbpool)
 		default:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Tipe laporan tidak valid."})
 		}
@@ -60,7 +68,7 @@ func GetLaporanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 // handleLaporanWali mengambil data laporan wali
 func handleLaporanWali(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate time.Time) {
 	var daftarIbu []models.Ibu
-	query := `SELECT id, nama_lengkap, nik, no_telepon, alamat, id_kader_pendaftar, created_at, updated_at FROM ibu`
+	query := `SELECT id, nama_lengkap, nik, no_telepon, alamat, latitude, longitude, id_kader_pendaftar, created_at, updated_at FROM ibu`
 	var args []interface{}
 	var conditions []string
 	argCounter := 1
@@ -92,7 +100,7 @@ func handleLaporanWali(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate
 
 	for rows.Next() {
 		var i models.Ibu
-		if err := rows.Scan(&i.ID, &i.NamaLengkap, &i.NIK, &i.NoTelepon, &i.Alamat, &i.IdKaderPendaftar, &i.CreatedAt, &i.UpdatedAt); err != nil {
+		if err := rows.Scan(&i.ID, &i.NamaLengkap, &i.NIK, &i.NoTelepon, &i.Alamat, &i.Latitude, &i.Longitude, &i.IdKaderPendaftar, &i.CreatedAt, &i.UpdatedAt); err != nil {
 			log.Printf("ERROR scanning report ibu: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 			return
@@ -111,7 +119,7 @@ func handleLaporanWali(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate
 // handleLaporanAnak mengambil data laporan anak
 func handleLaporanAnak(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate time.Time) {
 	var daftarAnak []models.Anak
-	query := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id`
+	query := `SELECT a.id, a.id_ibu, a.nama_anak, a.nik_anak, a.tanggal_lahir, a.jenis_kelamin, a.anak_ke, a.berat_lahir_kg, a.tinggi_lahir_cm, a.status, a.status_tanggal, a.status_alasan, a.id_posyandu, ps.nama AS nama_posyandu, a.punya_kms, a.usia_kehamilan_lahir_minggu, a.created_at, a.updated_at, i.nama_lengkap AS nama_ibu FROM anak a LEFT JOIN ibu i ON a.id_ibu = i.id LEFT JOIN posyandu ps ON a.id_posyandu = ps.id`
 	var args []interface{}
 	var conditions []string
 	argCounter := 1
@@ -127,6 +135,9 @@ func handleLaporanAnak(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate
 		args = append(args, endDate)
 		argCounter++
 	}
+	if !sertakanNonaktif(c) {
+		conditions = append(conditions, kondisiAnakAktif)
+	}
 
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
@@ -143,11 +154,12 @@ func handleLaporanAnak(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate
 
 	for rows.Next() {
 		var a models.Anak
-		if err := rows.Scan(&a.ID, &a.IdIbu, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.JenisKelamin, &a.AnakKe, &a.BeratLahirKg, &a.TinggiLahirCm, &a.CreatedAt, &a.UpdatedAt, &a.NamaIbu); err != nil {
+		if err := rows.Scan(&a.ID, &a.IdIbu, &a.NamaAnak, &a.NikAnak, &a.TanggalLahir, &a.JenisKelamin, &a.AnakKe, &a.BeratLahirKg, &a.TinggiLahirCm, &a.Status, &a.StatusTanggal, &a.StatusAlasan, &a.IdPosyandu, &a.NamaPosyandu, &a.PunyaKms, &a.UsiaKehamilanLahirMinggu, &a.CreatedAt, &a.UpdatedAt, &a.NamaIbu); err != nil {
 			log.Printf("ERROR scanning report anak: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 			return
 		}
+		a.UsiaHari, a.UsiaKoreksiHari = usiaKronologisDanKoreksi(a.TanggalLahir, a.UsiaKehamilanLahirMinggu, time.Now())
 		daftarAnak = append(daftarAnak, a)
 	}
 
@@ -163,14 +175,15 @@ func handleLaporanAnak(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate
 func handleLaporanPerkembangan(c *gin.Context, dbpool *pgxpool.Pool, startDate, endDate time.Time) {
 	var daftarPerkembangan []models.LaporanPerkembangan // Menggunakan struct LaporanPerkembangan
 	query := `SELECT
-                p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.lk_cm, p.ll_cm,
-                p.status_gizi, p.saran, p.id_kader_pencatat, p.created_at, p.updated_at,
+                p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.cara_ukur, p.tb_cm_standar, p.lk_cm, p.ll_cm,
+                p.status_gizi, p.zs_bbu, p.zs_tbu, p.zs_bbtb, p.zs_imtu, p.zs_lku, p.zs_lilau, p.status_bbu, p.status_tbu, p.status_bbtb, p.status_imtu, p.status_lku, p.kms_status, p.kms_2t, p.kms_bgm, p.kenaikan_bb_gram, p.kbm_gram, p.peringatan, p.alasan_konfirmasi, p.saran, p.status_asi, p.id_kader_pencatat, p.id_posyandu, p.created_at, p.updated_at, a.tanggal_lahir, a.usia_kehamilan_lahir_minggu,
                 a.nama_anak, k.nama_lengkap AS nama_kader, a.nik_anak, i.nama_lengkap AS nama_ibu,
-                i.nik AS nik_ibu
+                ps.nama AS nama_posyandu, i.nik AS nik_ibu
             FROM perkembangan p
             JOIN anak a ON p.id_anak = a.id
             JOIN ibu i ON a.id_ibu = i.id
-            LEFT JOIN kader k ON p.id_kader_pencatat = k.id`
+            LEFT JOIN kader k ON p.id_kader_pencatat = k.id
+            LEFT JOIN posyandu ps ON p.id_posyandu = ps.id`
 	var args []interface{}
 	var conditions []string
 	argCounter := 1
@@ -186,6 +199,9 @@ func handleLaporanPerkembangan(c *gin.Context, dbpool *pgxpool.Pool, startDate,
 		args = append(args, endDate)
 		argCounter++
 	}
+	if !sertakanNonaktif(c) {
+		conditions = append(conditions, kondisiAnakAktif)
+	}
 
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
@@ -202,17 +218,20 @@ func handleLaporanPerkembangan(c *gin.Context, dbpool *pgxpool.Pool, startDate,
 
 	for rows.Next() {
 		var p models.LaporanPerkembangan // Gunakan struct baru
+		var tanggalLahir time.Time
+		var usiaKehamilan *int
 		// Sesuaikan Scan untuk menyertakan nik_ibu di akhir
 		if err := rows.Scan(
-			&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.LkCm, &p.LlCm,
-			&p.StatusGizi, &p.Saran, &p.IdKaderPencatat, &p.CreatedAt, &p.UpdatedAt,
+			&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.CaraUkur, &p.TbCmStandar, &p.LkCm, &p.LlCm,
+			&p.StatusGizi, &p.ZsBbu, &p.ZsTbu, &p.ZsBbtb, &p.ZsImtu, &p.ZsLku, &p.ZsLilau, &p.StatusBbu, &p.StatusTbu, &p.StatusBbtb, &p.StatusImtu, &p.StatusLku, &p.KmsStatus, &p.Kms2T, &p.KmsBgm, &p.KenaikanBbGram, &p.KbmGram, &p.Peringatan, &p.AlasanKonfirmasi, &p.Saran, &p.StatusAsi, &p.IdKaderPencatat, &p.IdPosyandu, &p.CreatedAt, &p.UpdatedAt, &tanggalLahir, &usiaKehamilan,
 			&p.NamaAnak, &p.NamaKader, &p.NikAnak, &p.NamaIbu,
-			&p.NikIbu, // Scan NIK Ibu
+			&p.NamaPosyandu, &p.NikIbu, // Scan NIK Ibu
 		); err != nil {
 			log.Printf("ERROR scanning report perkembangan: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 			return
 		}
+		p.UsiaHari, p.UsiaKoreksiHari = usiaKronologisDanKoreksi(tanggalLahir, usiaKehamilan, p.TanggalPemeriksaan)
 		daftarPerkembangan = append(daftarPerkembangan, p)
 	}
 
@@ -229,16 +248,20 @@ func handleLaporanImunisasi(c *gin.Context, dbpool *pgxpool.Pool, startDate, end
 	var daftarImunisasi []models.LaporanImunisasi // Menggunakan struct LaporanImunisasi
 	query := `SELECT
                 r.id, r.id_anak, r.id_master_imunisasi, r.id_kader_pencatat, r.id_kader_updater,
-                r.tanggal_imunisasi, r.catatan, r.created_at, r.updated_at,
+                r.tanggal_imunisasi, r.catatan, r.peringatan, r.alasan_konfirmasi, r.id_posyandu, r.id_batch_vaksin, r.created_at, r.updated_at,
                 a.nama_anak, a.nik_anak,
                 m.nama_imunisasi,
                 kp.nama_lengkap AS nama_kader,
-                ku.nama_lengkap AS nama_kader_updater
+                ku.nama_lengkap AS nama_kader_updater,
+                ps.nama AS nama_posyandu,
+                bv.nomor_batch
             FROM riwayat_imunisasi r
             JOIN anak a ON r.id_anak = a.id
             JOIN master_imunisasi m ON r.id_master_imunisasi = m.id
             LEFT JOIN kader kp ON r.id_kader_pencatat = kp.id
-            LEFT JOIN kader ku ON r.id_kader_updater = ku.id`
+            LEFT JOIN kader ku ON r.id_kader_updater = ku.id
+            LEFT JOIN posyandu ps ON r.id_posyandu = ps.id
+            LEFT JOIN batch_vaksin bv ON r.id_batch_vaksin = bv.id`
 	var args []interface{}
 	var conditions []string
 	argCounter := 1
@@ -254,6 +277,9 @@ func handleLaporanImunisasi(c *gin.Context, dbpool *pgxpool.Pool, startDate, end
 		args = append(args, endDate)
 		argCounter++
 	}
+	if !sertakanNonaktif(c) {
+		conditions = append(conditions, kondisiAnakAktif)
+	}
 
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
@@ -272,10 +298,10 @@ func handleLaporanImunisasi(c *gin.Context, dbpool *pgxpool.Pool, startDate, end
 		var r models.LaporanImunisasi // Gunakan struct baru
 		if err := rows.Scan(
 			&r.ID, &r.IdAnak, &r.IdMasterImunisasi, &r.IdKaderPencatat, &r.IdKaderUpdater,
-			&r.TanggalDiberikan, &r.Catatan, &r.CreatedAt, &r.UpdatedAt,
+			&r.TanggalDiberikan, &r.Catatan, &r.Peringatan, &r.AlasanKonfirmasi, &r.IdPosyandu, &r.IdBatchVaksin, &r.CreatedAt, &r.UpdatedAt,
 			&r.NamaAnak, &r.NikAnak,
 			&r.NamaImunisasi,
-			&r.NamaKader, &r.NamaKaderUpdater,
+			&r.NamaKader, &r.NamaKaderUpdater, &r.NamaPosyandu, &r.NomorBatch,
 		); err != nil {
 			log.Printf("ERROR scanning report imunisasi: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
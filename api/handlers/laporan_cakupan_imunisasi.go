@@ -0,0 +1,395 @@
+// handlers/laporan_cakupan_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// targetUCIPersen adalah cakupan IDL minimal agar desa dinyatakan Universal Child Immunization
+const targetUCIPersen = 80.0
+
+// akumulasiCakupan menampung hitungan mentah satu posyandu, desa, atau total sebelum dihitung persennya
+type akumulasiCakupan struct {
+	bayi, baduta      int
+	target, terdaftar bool            // Sumber sasaran yang dipakai
+	dosis             map[int][12]int // id master imunisasi -> dosis diberikan per bulan
+	idl               int
+}
+
+func akumulasiBaru() *akumulasiCakupan {
+	return &akumulasiCakupan{dosis: make(map[int][12]int)}
+}
+
+func (a *akumulasiCakupan) tambah(b *akumulasiCakupan) {
+	a.bayi += b.bayi
+	a.baduta += b.baduta
+	a.target = a.target || b.target
+	a.terdaftar = a.terdaftar || b.terdaftar
+	a.idl += b.idl
+	for id, bulanan := range b.dosis {
+		jumlah := a.dosis[id]
+		for i := range bulanan {
+			jumlah[i] += bulanan[i]
+		}
+		a.dosis[id] = jumlah
+	}
+}
+
+func (a *akumulasiCakupan) totalDosis(idMaster int) int {
+	total := 0
+	for _, n := range a.dosis[idMaster] {
+		total += n
+	}
+	return total
+}
+
+// cariAntigen mencari dosis ke-n dari seri (atau nama imunisasi bila tanpa seri) yang memuat salah satu pola
+func cariAntigen(antigen []imunisasi.Antigen, dosisKe int, pola ...string) int {
+	for _, a := range antigen {
+		label := a.Nama
+		if a.Seri != nil {
+			label = *a.Seri
+		}
+		label = strings.ToUpper(label)
+		for _, p := range pola {
+			if a.DosisKe == dosisKe && strings.Contains(label, p) {
+				return a.ID
+			}
+		}
+	}
+	return 0
+}
+
+// hasil menghitung cakupan kumulatif bulanan per antigen, cakupan IDL dan angka drop-out
+func (a *akumulasiCakupan) hasil(antigen []imunisasi.Antigen, dpt1, dpt3, mr1 int) models.CakupanWilayah {
+	w := models.CakupanWilayah{
+		SasaranBayi:   a.bayi,
+		SasaranBaduta: a.baduta,
+		SumberSasaran: "terdaftar",
+		Antigen:       make([]models.CakupanAntigen, 0, len(antigen)),
+		IDL:           a.idl,
+		CakupanIDL:    persen(a.idl, a.bayi),
+	}
+	if a.target {
+		w.SumberSasaran = "target"
+		if a.terdaftar {
+			w.SumberSasaran = "campuran"
+		}
+	}
+	for _, ag := range antigen {
+		ca := models.CakupanAntigen{IdMasterImunisasi: ag.ID, NamaImunisasi: ag.Nama, Sasaran: a.bayi}
+		if ag.UsiaIdealBulan >= imunisasi.UsiaIDLBulan {
+			ca.Sasaran = a.baduta
+		}
+		jumlah := 0
+		for i, n := range a.dosis[ag.ID] {
+			jumlah += n
+			ca.Kumulatif[i] = jumlah
+			ca.Cakupan[i] = persen(jumlah, ca.Sasaran)
+		}
+		w.Antigen = append(w.Antigen, ca)
+	}
+	if dpt1 != 0 {
+		jumlahDPT1 := a.totalDosis(dpt1)
+		if dpt3 != 0 {
+			w.DropOutDPT1DPT3 = persen(jumlahDPT1-a.totalDosis(dpt3), jumlahDPT1)
+		}
+		if mr1 != 0 {
+			w.DropOutDPT1MR = persen(jumlahDPT1-a.totalDosis(mr1), jumlahDPT1)
+		}
+	}
+	return w
+}
+
+// handleLaporanCakupanImunisasi menyusun laporan cakupan imunisasi tahunan (PWS) per desa dan posyandu:
+// dosis kumulatif bulanan per antigen terhadap sasaran, cakupan IDL, status UCI desa dan drop-out.
+// Sasaran memakai target yang diatur per posyandu (sasaran_imunisasi); bila kosong, memakai kohort
+// kelahiran terdaftar: bayi lahir pada tahun laporan, baduta lahir pada tahun sebelumnya.
+// Dosis dan IDL dihitung menurut posyandu anak saat ini. Query: tahun=YYYY (default tahun berjalan), id_posyandu (opsional).
+func handleLaporanCakupanImunisasi(c *gin.Context, dbpool *pgxpool.Pool) {
+	tahun := time.Now().Year()
+	if tahunQuery := c.Query("tahun"); tahunQuery != "" {
+		t, err := strconv.Atoi(tahunQuery)
+		if err != nil || t < 2000 || t > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tahun tidak valid (YYYY)"})
+			return
+		}
+		tahun = t
+	}
+	awalTahun := time.Date(tahun, time.January, 1, 0, 0, 0, 0, time.UTC)
+	awalTahunBerikut := awalTahun.AddDate(1, 0, 0)
+	akhirTahun := awalTahunBerikut.AddDate(0, 0, -1)
+	ctx := context.Background()
+
+	var idPosyandu *int
+	if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+		id, err := strconv.Atoi(idPosyanduQuery)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+			return
+		}
+		idPosyandu = &id
+	}
+	// saringPosyandu menambahkan filter posyandu anak sebagai parameter ke-n
+	saringPosyandu := func(query string, args []interface{}) (string, []interface{}) {
+		if idPosyandu == nil {
+			return query, args
+		}
+		args = append(args, *idPosyandu)
+		return query + fmt.Sprintf(" AND a.id_posyandu = $%d", len(args)), args
+	}
+
+	// Posyandu (kunci 0 = anak tanpa posyandu, ditambahkan bila ada datanya)
+	type unit struct {
+		idPosyandu   *int
+		namaPosyandu *string
+		desa         *string
+		akumulasi    *akumulasiCakupan
+	}
+	var daftar []*unit
+	unitPosyandu := make(map[int]*unit)
+	query := "SELECT id, nama, desa FROM posyandu"
+	var args []interface{}
+	if idPosyandu != nil {
+		query += " WHERE id = $1"
+		args = append(args, *idPosyandu)
+	}
+	rows, err := dbpool.Query(ctx, query+" ORDER BY desa ASC NULLS LAST, nama ASC", args...)
+	if err != nil {
+		log.Printf("ERROR querying posyandu for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	for rows.Next() {
+		u := &unit{akumulasi: akumulasiBaru()}
+		var id int
+		if err := rows.Scan(&id, &u.namaPosyandu, &u.desa); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning posyandu for report cakupan imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		u.idPosyandu = &id
+		daftar = append(daftar, u)
+		unitPosyandu[id] = u
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating posyandu for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+	ambilUnit := func(kunci int) *unit {
+		u, ok := unitPosyandu[kunci]
+		if !ok {
+			u = &unit{akumulasi: akumulasiBaru()}
+			daftar = append(daftar, u)
+			unitPosyandu[kunci] = u
+		}
+		return u
+	}
+
+	// Sasaran: target yang diatur menggantikan kohort terdaftar posyandu tersebut
+	target := make(map[int][2]int)
+	rows, err = dbpool.Query(ctx, "SELECT id_posyandu, sasaran_bayi, sasaran_baduta FROM sasaran_imunisasi WHERE tahun = $1", tahun)
+	if err != nil {
+		log.Printf("ERROR querying sasaran imunisasi for report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil sasaran imunisasi."})
+		return
+	}
+	for rows.Next() {
+		var id, bayi, baduta int
+		if err := rows.Scan(&id, &bayi, &baduta); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning sasaran imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		target[id] = [2]int{bayi, baduta}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating sasaran imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+
+	// Kohort kelahiran terdaftar: bayi lahir pada tahun laporan, baduta lahir setahun sebelumnya
+	query, args = saringPosyandu(`SELECT COALESCE(a.id_posyandu, 0),
+                COUNT(*) FILTER (WHERE a.tanggal_lahir >= $1),
+                COUNT(*) FILTER (WHERE a.tanggal_lahir < $1)
+            FROM anak a
+            WHERE a.tanggal_lahir >= ($1::date - INTERVAL '1 year') AND a.tanggal_lahir < $2
+              AND (a.status = 'aktif' OR a.status_tanggal >= $1)`, []interface{}{awalTahun, awalTahunBerikut})
+	rows, err = dbpool.Query(ctx, query+" GROUP BY 1", args...)
+	if err != nil {
+		log.Printf("ERROR querying kohort kelahiran for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	terdaftar := make(map[int][2]int)
+	for rows.Next() {
+		var kunci, bayi, baduta int
+		if err := rows.Scan(&kunci, &bayi, &baduta); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning kohort kelahiran: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		terdaftar[kunci] = [2]int{bayi, baduta}
+		ambilUnit(kunci)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating kohort kelahiran: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+	for kunci, u := range unitPosyandu {
+		if t, ok := target[kunci]; ok && kunci != 0 {
+			u.akumulasi.bayi, u.akumulasi.baduta, u.akumulasi.target = t[0], t[1], true
+		} else {
+			t := terdaftar[kunci]
+			u.akumulasi.bayi, u.akumulasi.baduta, u.akumulasi.terdaftar = t[0], t[1], true
+		}
+	}
+
+	// Dosis yang diberikan per bulan pada tahun laporan
+	query, args = saringPosyandu(`SELECT COALESCE(a.id_posyandu, 0), r.id_master_imunisasi, EXTRACT(MONTH FROM r.tanggal_imunisasi)::int, COUNT(*)
+            FROM riwayat_imunisasi r
+            JOIN anak a ON r.id_anak = a.id
+            WHERE r.tanggal_imunisasi >= $1 AND r.tanggal_imunisasi < $2`, []interface{}{awalTahun, awalTahunBerikut})
+	rows, err = dbpool.Query(ctx, query+" GROUP BY 1, 2, 3", args...)
+	if err != nil {
+		log.Printf("ERROR querying dosis for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	for rows.Next() {
+		var kunci, idMaster, bulan, jumlah int
+		if err := rows.Scan(&kunci, &idMaster, &bulan, &jumlah); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning dosis for report cakupan imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		a := ambilUnit(kunci).akumulasi
+		bulanan := a.dosis[idMaster]
+		bulanan[bulan-1] += jumlah
+		a.dosis[idMaster] = bulanan
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating dosis for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+
+	// IDL: anak yang melengkapi imunisasi dasar pada tahun laporan, menurut versi jadwal kohort kelahirannya
+	query, args = saringPosyandu(`SELECT a.id, a.tanggal_lahir, COALESCE(a.id_posyandu, 0)
+            FROM anak a
+            WHERE a.tanggal_lahir <= $1 AND a.tanggal_lahir > ($1::date - INTERVAL '60 months')
+              AND (a.status = 'aktif' OR a.status_tanggal >= $2)`, []interface{}{akhirTahun, awalTahun})
+	rows, err = dbpool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("ERROR querying anak for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	type anakIDL struct {
+		id, kunci    int
+		tanggalLahir time.Time
+	}
+	var daftarAnak []anakIDL
+	var idAnak []int
+	for rows.Next() {
+		var b anakIDL
+		if err := rows.Scan(&b.id, &b.tanggalLahir, &b.kunci); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning anak for report cakupan imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		daftarAnak = append(daftarAnak, b)
+		idAnak = append(idAnak, b.id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating anak for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+	kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+	if err != nil {
+		log.Printf("ERROR fetching jadwal imunisasi for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jadwal imunisasi."})
+		return
+	}
+	diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, idAnak)
+	if err != nil {
+		log.Printf("ERROR fetching riwayat imunisasi for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat imunisasi."})
+		return
+	}
+	for _, b := range daftarAnak {
+		s := imunisasi.HitungIDL(b.tanggalLahir, kalender.UntukKelahiran(b.tanggalLahir).Antigen, diberikan[b.id], akhirTahun)
+		if s.TanggalLengkap != nil && !s.TanggalLengkap.Before(awalTahun) {
+			ambilUnit(b.kunci).akumulasi.idl++
+		}
+	}
+
+	antigen, err := ambilAntigen(ctx, dbpool)
+	if err != nil {
+		log.Printf("ERROR fetching master imunisasi for report cakupan imunisasi: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil master imunisasi."})
+		return
+	}
+	dpt1, dpt3 := cariAntigen(antigen, 1, "DPT"), cariAntigen(antigen, 3, "DPT")
+	mr1 := cariAntigen(antigen, 1, "MR", "CAMPAK")
+
+	laporan := models.LaporanCakupanImunisasi{Tahun: tahun, TargetUCI: targetUCIPersen, Desa: make([]models.CakupanDesa, 0)}
+	total := akumulasiBaru()
+	var akumulasiDesa []*akumulasiCakupan
+	indeks := make(map[string]int) // nama desa ("" = tanpa desa) -> indeks di laporan.Desa
+	for _, u := range daftar {
+		kunci := ""
+		if u.desa != nil {
+			kunci = *u.desa
+		}
+		i, ok := indeks[kunci]
+		if !ok {
+			laporan.Desa = append(laporan.Desa, models.CakupanDesa{Desa: u.desa, Posyandu: make([]models.CakupanPosyandu, 0)})
+			akumulasiDesa = append(akumulasiDesa, akumulasiBaru())
+			i = len(laporan.Desa) - 1
+			indeks[kunci] = i
+		}
+		akumulasiDesa[i].tambah(u.akumulasi)
+		total.tambah(u.akumulasi)
+		laporan.Desa[i].Posyandu = append(laporan.Desa[i].Posyandu, models.CakupanPosyandu{
+			IdPosyandu:     u.idPosyandu,
+			NamaPosyandu:   u.namaPosyandu,
+			CakupanWilayah: u.akumulasi.hasil(antigen, dpt1, dpt3, mr1),
+		})
+	}
+	for i := range laporan.Desa {
+		d := &laporan.Desa[i]
+		d.CakupanWilayah = akumulasiDesa[i].hasil(antigen, dpt1, dpt3, mr1)
+		d.UCI = d.CakupanIDL != nil && *d.CakupanIDL >= targetUCIPersen
+		if d.UCI {
+			laporan.DesaUCI++
+		}
+	}
+	laporan.Total = total.hasil(antigen, dpt1, dpt3, mr1)
+	c.JSON(http.StatusOK, laporan)
+}
@@ -0,0 +1,140 @@
+// handlers/laporan_idl.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// tambahIDL mencatat satu anak ke dalam indikator IDL bulan laporan
+func tambahIDL(ind *models.IndikatorIDL, tanggalLahir time.Time, s imunisasi.StatusIDL, awalBulan, awalBulanBerikut time.Time) {
+	ulangTahun := imunisasi.TambahBulan(tanggalLahir, imunisasi.UsiaIDLBulan)
+	if !ulangTahun.Before(awalBulan) && ulangTahun.Before(awalBulanBerikut) {
+		ind.Sasaran++
+		if s.Status == imunisasi.IDLTepatWaktu {
+			ind.SasaranIDL++
+		}
+	}
+	if s.TanggalLengkap != nil && !s.TanggalLengkap.Before(awalBulan) && s.TanggalLengkap.Before(awalBulanBerikut) {
+		ind.Lengkap++
+		if s.Status == imunisasi.IDLTepatWaktu {
+			ind.TepatWaktu++
+		} else {
+			ind.TidakTepatWaktu++
+		}
+	}
+}
+
+// handleLaporanIDL menyusun laporan Imunisasi Dasar Lengkap bulanan per posyandu.
+// Query: bulan=YYYY-MM (default bulan berjalan), id_posyandu (opsional).
+func handleLaporanIDL(c *gin.Context, dbpool *pgxpool.Pool) {
+	bulanQuery := c.DefaultQuery("bulan", time.Now().Format("2006-01"))
+	awalBulan, err := time.Parse("2006-01", bulanQuery)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format bulan tidak valid (YYYY-MM)"})
+		return
+	}
+	awalBulanBerikut := awalBulan.AddDate(0, 1, 0)
+	akhirBulan := awalBulanBerikut.AddDate(0, 0, -1)
+	ctx := context.Background()
+
+	// Balita yang masih aktif pada akhir bulan laporan (status berubah sesudahnya tetap dihitung)
+	query := `SELECT a.id, a.nama_anak, a.tanggal_lahir, a.id_posyandu, ps.nama
+            FROM anak a
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id
+            WHERE a.tanggal_lahir <= $1 AND a.tanggal_lahir > ($1::date - INTERVAL '60 months')
+              AND (a.status = 'aktif' OR a.status_tanggal > $1)`
+	args := []interface{}{akhirBulan}
+	if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+		idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+			return
+		}
+		query += " AND a.id_posyandu = $2"
+		args = append(args, idPosyandu)
+	}
+	query += " ORDER BY ps.nama ASC NULLS LAST, a.nama_anak ASC"
+
+	rows, err := dbpool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("ERROR querying report idl: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	type baris struct {
+		models.AnakIDL
+		idPosyandu   *int
+		namaPosyandu *string
+	}
+	var daftar []baris
+	var idAnak []int
+	for rows.Next() {
+		var b baris
+		if err := rows.Scan(&b.IdAnak, &b.NamaAnak, &b.TanggalLahir, &b.idPosyandu, &b.namaPosyandu); err != nil {
+			rows.Close()
+			log.Printf("ERROR scanning report idl: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		daftar = append(daftar, b)
+		idAnak = append(idAnak, b.IdAnak)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating report idl: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+
+	kalender, err := ambilKalenderImunisasi(ctx, dbpool)
+	if err != nil {
+		log.Printf("ERROR fetching jadwal imunisasi for report idl: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jadwal imunisasi."})
+		return
+	}
+	diberikan, err := ambilImunisasiDiberikan(ctx, dbpool, idAnak)
+	if err != nil {
+		log.Printf("ERROR fetching riwayat imunisasi for report idl: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat imunisasi."})
+		return
+	}
+
+	laporan := models.LaporanIDL{Bulan: awalBulan.Format("2006-01"), Posyandu: make([]models.IDLPosyandu, 0)}
+	indeks := make(map[int]int) // id posyandu (0 = tanpa posyandu) -> indeks di laporan.Posyandu
+	for _, b := range daftar {
+		kunci := 0
+		if b.idPosyandu != nil {
+			kunci = *b.idPosyandu
+		}
+		i, ok := indeks[kunci]
+		if !ok {
+			laporan.Posyandu = append(laporan.Posyandu, models.IDLPosyandu{IdPosyandu: b.idPosyandu, NamaPosyandu: b.namaPosyandu, Anak: make([]models.AnakIDL, 0)})
+			i = len(laporan.Posyandu) - 1
+			indeks[kunci] = i
+		}
+		p := &laporan.Posyandu[i]
+
+		s := imunisasi.HitungIDL(b.TanggalLahir, kalender.UntukKelahiran(b.TanggalLahir).Antigen, diberikan[b.IdAnak], akhirBulan)
+		tambahIDL(&p.IndikatorIDL, b.TanggalLahir, s, awalBulan, awalBulanBerikut)
+		tambahIDL(&laporan.Total, b.TanggalLahir, s, awalBulan, awalBulanBerikut)
+		if s.TanggalLengkap != nil && !s.TanggalLengkap.Before(awalBulan) {
+			b.Status, b.TanggalLengkap = s.Status, *s.TanggalLengkap
+			p.Anak = append(p.Anak, b.AnakIDL)
+		}
+	}
+	for i := range laporan.Posyandu {
+		p := &laporan.Posyandu[i]
+		p.Cakupan = persen(p.SasaranIDL, p.Sasaran)
+	}
+	laporan.Total.Cakupan = persen(laporan.Total.SasaranIDL, laporan.Total.Sasaran)
+	c.JSON(http.StatusOK, laporan)
+}
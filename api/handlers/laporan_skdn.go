@@ -0,0 +1,180 @@
+// handlers/laporan_skdn.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// kelompokUmurSKDN mengembalikan kelompok umur laporan SKDN untuk umur dalam bulan penuh
+func kelompokUmurSKDN(usiaBulan int) string {
+	switch {
+	case usiaBulan < 6:
+		return "0-5"
+	case usiaBulan < 12:
+		return "6-11"
+	case usiaBulan < 24:
+		return "12-23"
+	default:
+		return "24-59"
+	}
+}
+
+// tambahSKDN mencatat satu balita ke dalam indikator
+func tambahSKDN(ind *models.IndikatorSKDN, punyaKms, ditimbang bool, kmsStatus *string, duaT, bgm bool) {
+	ind.S++
+	if punyaKms {
+		ind.K++
+	}
+	if !ditimbang {
+		return
+	}
+	ind.D++
+	if kmsStatus != nil {
+		switch *kmsStatus {
+		case "N":
+			ind.N++
+		case "T":
+			ind.T++
+		case "O":
+			ind.O++
+		case "B":
+			ind.B++
+		}
+	}
+	if duaT {
+		ind.DuaT++
+	}
+	if bgm {
+		ind.BGM++
+	}
+}
+
+// persen menghitung rasio dalam persen dengan dua desimal (nil jika penyebut nol)
+func persen(pembilang, penyebut int) *float64 {
+	if penyebut == 0 {
+		return nil
+	}
+	p := math.Round(float64(pembilang)/float64(penyebut)*10000) / 100
+	return &p
+}
+
+// hitungRasioSKDN melengkapi rasio K/S, D/S dan N/D
+func hitungRasioSKDN(ind *models.IndikatorSKDN) {
+	ind.KS = persen(ind.K, ind.S)
+	ind.DS = persen(ind.D, ind.S)
+	ind.ND = persen(ind.N, ind.D)
+}
+
+// handleLaporanSKDN menyusun laporan SKDN bulanan per posyandu serta per jenis kelamin dan kelompok umur.
+// Query: bulan=YYYY-MM (default bulan berjalan), id_posyandu (opsional).
+func handleLaporanSKDN(c *gin.Context, dbpool *pgxpool.Pool) {
+	bulanQuery := c.DefaultQuery("bulan", time.Now().Format("2006-01"))
+	awalBulan, err := time.Parse("2006-01", bulanQuery)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format bulan tidak valid (YYYY-MM)"})
+		return
+	}
+	awalBulanBerikut := awalBulan.AddDate(0, 1, 0)
+	akhirBulan := awalBulanBerikut.AddDate(0, 0, -1)
+
+	// S: balita 0-59 bulan pada akhir bulan yang masih aktif saat itu (status berubah sesudah bulan laporan tetap dihitung).
+	// Penimbangan yang dihitung adalah penimbangan terakhir anak pada bulan laporan.
+	query := `SELECT a.id_posyandu, ps.nama, a.jenis_kelamin,
+                (EXTRACT(YEAR FROM AGE($2::date, a.tanggal_lahir)) * 12 + EXTRACT(MONTH FROM AGE($2::date, a.tanggal_lahir)))::int AS usia_bulan,
+                a.punya_kms, t.id IS NOT NULL AS ditimbang, t.kms_status, COALESCE(t.kms_2t, FALSE), COALESCE(t.kms_bgm, FALSE)
+            FROM anak a
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id
+            LEFT JOIN LATERAL (
+                SELECT p.id, p.kms_status, p.kms_2t, p.kms_bgm FROM perkembangan p
+                WHERE p.id_anak = a.id AND p.bb_kg IS NOT NULL AND p.tanggal_pemeriksaan >= $1 AND p.tanggal_pemeriksaan < $3
+                ORDER BY p.tanggal_pemeriksaan DESC, p.id DESC LIMIT 1
+            ) t ON TRUE
+            WHERE a.tanggal_lahir <= $2 AND a.tanggal_lahir > ($2::date - INTERVAL '60 months')
+              AND (a.status = 'aktif' OR a.status_tanggal > $2)`
+	args := []interface{}{awalBulan, akhirBulan, awalBulanBerikut}
+
+	if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+		idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+			return
+		}
+		query += " AND a.id_posyandu = $4"
+		args = append(args, idPosyandu)
+	}
+	query += " ORDER BY ps.nama ASC NULLS LAST, a.jenis_kelamin ASC, usia_bulan ASC"
+
+	rows, err := dbpool.Query(context.Background(), query, args...)
+	if err != nil {
+		log.Printf("ERROR querying report skdn: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	defer rows.Close()
+
+	laporan := models.LaporanSKDN{Bulan: awalBulan.Format("2006-01"), Posyandu: make([]models.SKDNPosyandu, 0)}
+	indeksPosyandu := make(map[int]int) // id_posyandu (0 = belum ditetapkan) -> indeks di laporan.Posyandu
+	indeksRincian := make(map[[3]string]int)
+
+	for rows.Next() {
+		var idPosyandu *int
+		var namaPosyandu, kmsStatus *string
+		var jenisKelamin string
+		var usiaBulan int
+		var punyaKms, ditimbang, duaT, bgm bool
+		if err := rows.Scan(&idPosyandu, &namaPosyandu, &jenisKelamin, &usiaBulan, &punyaKms, &ditimbang, &kmsStatus, &duaT, &bgm); err != nil {
+			log.Printf("ERROR scanning report skdn: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+
+		kunci := 0
+		if idPosyandu != nil {
+			kunci = *idPosyandu
+		}
+		ip, ok := indeksPosyandu[kunci]
+		if !ok {
+			laporan.Posyandu = append(laporan.Posyandu, models.SKDNPosyandu{IdPosyandu: idPosyandu, NamaPosyandu: namaPosyandu, Rincian: make([]models.RincianSKDN, 0)})
+			ip = len(laporan.Posyandu) - 1
+			indeksPosyandu[kunci] = ip
+		}
+		pos := &laporan.Posyandu[ip]
+
+		kelompok := kelompokUmurSKDN(usiaBulan)
+		kunciRincian := [3]string{strconv.Itoa(kunci), jenisKelamin, kelompok}
+		ir, ok := indeksRincian[kunciRincian]
+		if !ok {
+			pos.Rincian = append(pos.Rincian, models.RincianSKDN{JenisKelamin: jenisKelamin, KelompokUmur: kelompok})
+			ir = len(pos.Rincian) - 1
+			indeksRincian[kunciRincian] = ir
+		}
+
+		tambahSKDN(&laporan.Total, punyaKms, ditimbang, kmsStatus, duaT, bgm)
+		tambahSKDN(&pos.Total, punyaKms, ditimbang, kmsStatus, duaT, bgm)
+		tambahSKDN(&pos.Rincian[ir].IndikatorSKDN, punyaKms, ditimbang, kmsStatus, duaT, bgm)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating report skdn: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+
+	hitungRasioSKDN(&laporan.Total)
+	for i := range laporan.Posyandu {
+		hitungRasioSKDN(&laporan.Posyandu[i].Total)
+		for j := range laporan.Posyandu[i].Rincian {
+			hitungRasioSKDN(&laporan.Posyandu[i].Rincian[j].IndikatorSKDN)
+		}
+	}
+	c.JSON(http.StatusOK, laporan)
+}
@@ -0,0 +1,123 @@
+// handlers/laporan_stok_vaksin.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// tambahMutasiStok menjumlahkan mutasi satu batch ke rekap
+func tambahMutasiStok(total *models.MutasiStokVaksin, m models.MutasiStokVaksin) {
+	total.StokAwal += m.StokAwal
+	total.Diterima += m.Diterima
+	total.Dipakai += m.Dipakai
+	total.Terbuang += m.Terbuang
+	total.Penyesuaian += m.Penyesuaian
+	total.StokAkhir += m.StokAkhir
+	total.VialDibuka += m.VialDibuka
+}
+
+// handleLaporanStokVaksin menyusun laporan bulanan stok vaksin per lokasi dan batch:
+// stok awal, penerimaan, pemakaian, vaksin terbuang, penyesuaian dan stok akhir (dalam dosis).
+// Query: bulan=YYYY-MM (default bulan berjalan), id_posyandu (opsional).
+func handleLaporanStokVaksin(c *gin.Context, dbpool *pgxpool.Pool) {
+	bulanQuery := c.DefaultQuery("bulan", time.Now().Format("2006-01"))
+	awalBulan, err := time.Parse("2006-01", bulanQuery)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format bulan tidak valid (YYYY-MM)"})
+		return
+	}
+	awalBulanBerikut := awalBulan.AddDate(0, 1, 0)
+
+	query := `SELECT b.id, b.id_posyandu, ps.nama, b.nama_vaksin, b.nomor_batch, b.tanggal_kedaluwarsa, b.status_vvm,
+                COALESCE(SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal < $1), 0),
+                COALESCE(SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal >= $1 AND t.jenis = 'penerimaan'), 0),
+                COALESCE(-SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal >= $1 AND t.jenis = 'pemakaian'), 0),
+                COALESCE(-SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal >= $1 AND t.jenis = 'terbuang'), 0),
+                COALESCE(SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal >= $1 AND t.jenis = 'penyesuaian'), 0),
+                COALESCE(SUM(t.jumlah_vial) FILTER (WHERE t.tanggal >= $1 AND t.jenis = 'buka_vial'), 0)
+            FROM batch_vaksin b
+            LEFT JOIN posyandu ps ON b.id_posyandu = ps.id
+            JOIN transaksi_vaksin t ON t.id_batch = b.id AND t.tanggal < $2`
+	args := []interface{}{awalBulan, awalBulanBerikut}
+	if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+		idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+			return
+		}
+		query += " WHERE b.id_posyandu = $3"
+		args = append(args, idPosyandu)
+	}
+	query += ` GROUP BY b.id, ps.nama
+            ORDER BY ps.nama ASC NULLS FIRST, b.nama_vaksin ASC, b.tanggal_kedaluwarsa ASC, b.id ASC`
+
+	rows, err := dbpool.Query(context.Background(), query, args...)
+	if err != nil {
+		log.Printf("ERROR querying report stok vaksin: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+		return
+	}
+	defer rows.Close()
+
+	laporan := models.LaporanStokVaksin{Bulan: awalBulan.Format("2006-01"), Vaksin: make([]models.RekapStokVaksin, 0), Posyandu: make([]models.StokVaksinPosyandu, 0)}
+	indeks := make(map[int]int) // id posyandu (0 = gudang puskesmas) -> indeks di laporan.Posyandu
+	indeksVaksin := make(map[string]int)
+	for rows.Next() {
+		var b models.StokBatchBulanan
+		var idPosyandu *int
+		var namaPosyandu *string
+		if err := rows.Scan(&b.IdBatch, &idPosyandu, &namaPosyandu, &b.NamaVaksin, &b.NomorBatch, &b.TanggalKedaluwarsa, &b.StatusVVM,
+			&b.StokAwal, &b.Diterima, &b.Dipakai, &b.Terbuang, &b.Penyesuaian, &b.VialDibuka); err != nil {
+			log.Printf("ERROR scanning report stok vaksin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+			return
+		}
+		// Batch yang sudah habis sebelum bulan laporan dan tidak bergerak tidak ditampilkan
+		if b.StokAwal == 0 && b.Diterima == 0 && b.Dipakai == 0 && b.Terbuang == 0 && b.Penyesuaian == 0 && b.VialDibuka == 0 {
+			continue
+		}
+		b.StokAkhir = b.StokAwal + b.Diterima - b.Dipakai - b.Terbuang + b.Penyesuaian
+		b.PersenTerbuang = persen(b.Terbuang, b.Dipakai+b.Terbuang)
+
+		kunci := 0
+		if idPosyandu != nil {
+			kunci = *idPosyandu
+		}
+		i, ok := indeks[kunci]
+		if !ok {
+			laporan.Posyandu = append(laporan.Posyandu, models.StokVaksinPosyandu{IdPosyandu: idPosyandu, NamaPosyandu: namaPosyandu, Batch: make([]models.StokBatchBulanan, 0)})
+			i = len(laporan.Posyandu) - 1
+			indeks[kunci] = i
+		}
+		laporan.Posyandu[i].Batch = append(laporan.Posyandu[i].Batch, b)
+
+		j, ok := indeksVaksin[b.NamaVaksin]
+		if !ok {
+			laporan.Vaksin = append(laporan.Vaksin, models.RekapStokVaksin{NamaVaksin: b.NamaVaksin})
+			j = len(laporan.Vaksin) - 1
+			indeksVaksin[b.NamaVaksin] = j
+		}
+		tambahMutasiStok(&laporan.Vaksin[j].MutasiStokVaksin, b.MutasiStokVaksin)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR iterating report stok vaksin: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses."})
+		return
+	}
+
+	for i := range laporan.Vaksin {
+		v := &laporan.Vaksin[i]
+		v.PersenTerbuang = persen(v.Terbuang, v.Dipakai+v.Terbuang)
+	}
+	sort.Slice(laporan.Vaksin, func(i, j int) bool { return laporan.Vaksin[i].NamaVaksin < laporan.Vaksin[j].NamaVaksin })
+	c.JSON(http.StatusOK, laporan)
+}
@@ -0,0 +1,320 @@
+// handlers/mutasi.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// AjukanMutasiHandler menangani pengajuan mutasi anak oleh posyandu asal
+func AjukanMutasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.AjukanMutasiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		ctx := context.Background()
+		idPosyanduKader, err := posyanduKader(ctx, dbpool, kaderId)
+		if err != nil {
+			log.Printf("ERROR querying posyandu of kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses mutasi."})
+			return
+		}
+		if idPosyanduKader == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Akun kader belum terhubung ke posyandu."})
+			return
+		}
+
+		var idPosyanduAnak *int
+		var statusAnak string
+		err = dbpool.QueryRow(ctx, "SELECT id_posyandu, status FROM anak WHERE id = $1", payload.IdAnak).Scan(&idPosyanduAnak, &statusAnak)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying anak %d for mutasi: %v", payload.IdAnak, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			}
+			return
+		}
+		// Anak lama yang belum punya posyandu dianggap milik posyandu kader pengaju
+		if idPosyanduAnak != nil && *idPosyanduAnak != *idPosyanduKader {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Mutasi hanya dapat diajukan oleh posyandu asal anak."})
+			return
+		}
+		if statusAnak != "aktif" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hanya anak berstatus aktif yang dapat dimutasi."})
+			return
+		}
+		if payload.IdPosyanduTujuan == *idPosyanduKader {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Posyandu tujuan harus berbeda dengan posyandu asal."})
+			return
+		}
+
+		var id int
+		err = dbpool.QueryRow(ctx,
+			`INSERT INTO mutasi_anak (id_anak, id_posyandu_asal, id_posyandu_tujuan, alasan, id_kader_pengaju) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			payload.IdAnak, *idPosyanduKader, payload.IdPosyanduTujuan, payload.Alasan, kaderId).Scan(&id)
+
+		if err != nil {
+			log.Printf("ERROR inserting mutasi anak %d by kader %d: %v", payload.IdAnak, kaderId, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok {
+				if pgErr.Code == "23505" && pgErr.ConstraintName == "mutasi_anak_pending_key" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Anak ini masih memiliki pengajuan mutasi yang belum diputuskan."})
+					return
+				}
+				if pgErr.Code == "23503" && pgErr.ConstraintName == "mutasi_anak_id_posyandu_tujuan_fkey" {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Posyandu tujuan tidak ditemukan."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan pengajuan mutasi."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Pengajuan mutasi berhasil dikirim!", "id": id})
+	}
+}
+
+// GetMutasiHandler menangani pengambilan daftar mutasi milik posyandu kader.
+// Query arah=masuk|keluar untuk membatasi arah mutasi, status untuk memfilter status.
+func GetMutasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idPosyanduKader, err := posyanduKader(context.Background(), dbpool, kaderId)
+		if err != nil {
+			log.Printf("ERROR querying posyandu of kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data mutasi."})
+			return
+		}
+		if idPosyanduKader == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Akun kader belum terhubung ke posyandu."})
+			return
+		}
+
+		daftarMutasi := make([]models.MutasiAnak, 0)
+		query := `SELECT m.id, m.id_anak, m.id_posyandu_asal, m.id_posyandu_tujuan, m.status, m.alasan, m.catatan_keputusan,
+                m.id_kader_pengaju, m.id_kader_pemutus, m.tanggal_pengajuan, m.tanggal_keputusan,
+                a.nama_anak, pa.nama AS nama_posyandu_asal, pt.nama AS nama_posyandu_tujuan,
+                kp.nama_lengkap AS nama_kader_pengaju, kk.nama_lengkap AS nama_kader_pemutus
+            FROM mutasi_anak m
+            JOIN anak a ON m.id_anak = a.id
+            JOIN posyandu pa ON m.id_posyandu_asal = pa.id
+            JOIN posyandu pt ON m.id_posyandu_tujuan = pt.id
+            LEFT JOIN kader kp ON m.id_kader_pengaju = kp.id
+            LEFT JOIN kader kk ON m.id_kader_pemutus = kk.id`
+
+		args := []interface{}{*idPosyanduKader}
+		var conditions []string
+		argCounter := 2
+
+		switch c.Query("arah") {
+		case "masuk":
+			conditions = append(conditions, "m.id_posyandu_tujuan = $1")
+		case "keluar":
+			conditions = append(conditions, "m.id_posyandu_asal = $1")
+		default:
+			conditions = append(conditions, "(m.id_posyandu_asal = $1 OR m.id_posyandu_tujuan = $1)")
+		}
+
+		if statusQuery := c.Query("status"); statusQuery != "" {
+			conditions = append(conditions, fmt.Sprintf("m.status = $%d", argCounter))
+			args = append(args, statusQuery)
+			argCounter++
+		}
+
+		query += " WHERE " + strings.Join(conditions, " AND ")
+		query += " ORDER BY m.tanggal_pengajuan DESC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying mutasi_anak: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data mutasi."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var m models.MutasiAnak
+			if err := rows.Scan(&m.ID, &m.IdAnak, &m.IdPosyanduAsal, &m.IdPosyanduTujuan, &m.Status, &m.Alasan, &m.CatatanKeputusan,
+				&m.IdKaderPengaju, &m.IdKaderPemutus, &m.TanggalPengajuan, &m.TanggalKeputusan,
+				&m.NamaAnak, &m.NamaPosyanduAsal, &m.NamaPosyanduTujuan,
+				&m.NamaKaderPengaju, &m.NamaKaderPemutus); err != nil {
+				log.Printf("ERROR scanning mutasi_anak row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data mutasi."})
+				return
+			}
+			daftarMutasi = append(daftarMutasi, m)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating mutasi_anak rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar mutasi."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarMutasi)
+	}
+}
+
+// TerimaMutasiHandler menangani penerimaan mutasi oleh posyandu tujuan.
+// Kepemilikan anak beserta seluruh riwayat perkembangan & imunisasinya berpindah ke posyandu tujuan,
+// sedangkan id_posyandu pada tiap catatan tetap menunjukkan tempat data tersebut dicatat.
+func TerimaMutasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return putuskanMutasi(dbpool, "diterima")
+}
+
+// TolakMutasiHandler menangani penolakan mutasi oleh posyandu tujuan
+func TolakMutasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return putuskanMutasi(dbpool, "ditolak")
+}
+
+// BatalkanMutasiHandler menangani pembatalan mutasi oleh posyandu asal
+func BatalkanMutasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return putuskanMutasi(dbpool, "dibatalkan")
+}
+
+// putuskanMutasi memproses keputusan atas pengajuan mutasi yang masih berstatus "diajukan"
+func putuskanMutasi(dbpool *pgxpool.Pool, statusBaru string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID mutasi tidak valid"})
+			return
+		}
+
+		var payload models.KeputusanMutasiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil && c.Request.ContentLength > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format data salah."})
+			return
+		}
+
+		ctx := context.Background()
+		idPosyanduKader, err := posyanduKader(ctx, dbpool, kaderId)
+		if err != nil {
+			log.Printf("ERROR querying posyandu of kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses mutasi."})
+			return
+		}
+		if idPosyanduKader == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Akun kader belum terhubung ke posyandu."})
+			return
+		}
+
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for mutasi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses mutasi."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var idAnak, idAsal, idTujuan int
+		var statusLama string
+		err = tx.QueryRow(ctx, "SELECT id_anak, id_posyandu_asal, id_posyandu_tujuan, status FROM mutasi_anak WHERE id = $1 FOR UPDATE", id).
+			Scan(&idAnak, &idAsal, &idTujuan, &statusLama)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data mutasi tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying mutasi %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data mutasi."})
+			}
+			return
+		}
+		if statusLama != "diajukan" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Mutasi ini sudah " + statusLama + "."})
+			return
+		}
+
+		// Terima/tolak oleh posyandu tujuan, batal oleh posyandu asal
+		berwenang := *idPosyanduKader == idTujuan
+		if statusBaru == "dibatalkan" {
+			berwenang = *idPosyanduKader == idAsal
+		}
+		if !berwenang {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Posyandu Anda tidak berwenang memutuskan mutasi ini."})
+			return
+		}
+
+		if statusBaru == "diterima" {
+			// Anak dikunci dan diperiksa ulang: bisa saja sudah dinonaktifkan atau dipindahkan sejak mutasi diajukan
+			var statusAnak string
+			var idPosyanduAnak *int
+			err = tx.QueryRow(ctx, "SELECT status, id_posyandu FROM anak WHERE id = $1 FOR UPDATE", idAnak).Scan(&statusAnak, &idPosyanduAnak)
+			if err != nil {
+				log.Printf("ERROR locking anak %d for mutasi %d: %v", idAnak, id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+				return
+			}
+			if statusAnak != "aktif" {
+				c.JSON(http.StatusConflict, gin.H{"error": "Anak sudah tidak berstatus aktif (" + statusAnak + "); mutasi tidak dapat diterima."})
+				return
+			}
+			if idPosyanduAnak != nil && *idPosyanduAnak != idAsal {
+				c.JSON(http.StatusConflict, gin.H{"error": "Anak sudah tidak terdaftar di posyandu asal; mutasi tidak dapat diterima."})
+				return
+			}
+
+			// Catatan lama tanpa posyandu ditandai sebagai catatan posyandu asal sebelum diserahkan
+			for _, tabel := range []string{"perkembangan", "riwayat_imunisasi"} {
+				if _, err := tx.Exec(ctx, "UPDATE "+tabel+" SET id_posyandu = $1 WHERE id_anak = $2 AND id_posyandu IS NULL", idAsal, idAnak); err != nil {
+					log.Printf("ERROR stamping %s for mutasi %d: %v", tabel, id, err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindahkan riwayat anak."})
+					return
+				}
+			}
+			if _, err := tx.Exec(ctx, "UPDATE anak SET id_posyandu = $1, updated_at = NOW() WHERE id = $2", idTujuan, idAnak); err != nil {
+				log.Printf("ERROR moving anak %d for mutasi %d: %v", idAnak, id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindahkan data anak."})
+				return
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE mutasi_anak SET status = $1, catatan_keputusan = $2, id_kader_pemutus = $3, tanggal_keputusan = NOW() WHERE id = $4`,
+			statusBaru, payload.Catatan, kaderId, id)
+		if err != nil {
+			log.Printf("ERROR updating mutasi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses mutasi."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing mutasi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses mutasi."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Mutasi berhasil " + statusBaru + "!"})
+	}
+}
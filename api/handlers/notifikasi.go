@@ -0,0 +1,131 @@
+// handlers/notifikasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// kirimNotifikasiPeran membuat notifikasi untuk setiap kader dengan salah satu peran yang diberikan.
+// Dijalankan di dalam transaksi pemicu sehingga notifikasi tersimpan bersamaan dengan datanya.
+func kirimNotifikasiPeran(ctx context.Context, tx pgx.Tx, daftarPeran []string, jenis, judul, pesan string, idReferensi int) error {
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO notifikasi (id_kader, jenis, judul, pesan, id_referensi)
+        SELECT id, $2, $3, $4, $5 FROM kader WHERE peran = ANY($1)`,
+		daftarPeran, jenis, judul, pesan, idReferensi)
+	if err == nil {
+		log.Printf("Notifikasi %s #%d dikirim ke %d kader", jenis, idReferensi, tag.RowsAffected())
+	}
+	return err
+}
+
+// GetNotifikasiHandler menampilkan 100 notifikasi terbaru milik kader yang sedang login.
+// Query: belum_dibaca=true untuk hanya menampilkan yang belum dibaca.
+func GetNotifikasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+		ctx := context.Background()
+
+		query := `SELECT id, jenis, judul, pesan, id_referensi, dibaca_at, created_at FROM notifikasi WHERE id_kader = $1`
+		if c.Query("belum_dibaca") == "true" {
+			query += " AND dibaca_at IS NULL"
+		}
+		query += " ORDER BY created_at DESC, id DESC LIMIT 100"
+
+		rows, err := dbpool.Query(ctx, query, kaderId)
+		if err != nil {
+			log.Printf("ERROR querying notifikasi for kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.Notifikasi, 0)
+		for rows.Next() {
+			var n models.Notifikasi
+			if err := rows.Scan(&n.ID, &n.Jenis, &n.Judul, &n.Pesan, &n.IdReferensi, &n.DibacaAt, &n.CreatedAt); err != nil {
+				log.Printf("ERROR scanning notifikasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, n)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating notifikasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+
+		var belumDibaca int
+		if err := dbpool.QueryRow(ctx, "SELECT COUNT(*) FROM notifikasi WHERE id_kader = $1 AND dibaca_at IS NULL", kaderId).Scan(&belumDibaca); err != nil {
+			log.Printf("ERROR counting notifikasi for kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"belum_dibaca": belumDibaca, "notifikasi": daftar})
+	}
+}
+
+// BacaNotifikasiHandler menandai satu notifikasi milik kader sebagai sudah dibaca
+func BacaNotifikasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID notifikasi tidak valid"})
+			return
+		}
+
+		tag, err := dbpool.Exec(context.Background(),
+			"UPDATE notifikasi SET dibaca_at = COALESCE(dibaca_at, NOW()) WHERE id = $1 AND id_kader = $2", id, kaderId)
+		if err != nil {
+			log.Printf("ERROR marking notifikasi %d as read: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notifikasi tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Notifikasi ditandai sudah dibaca."})
+	}
+}
+
+// BacaSemuaNotifikasiHandler menandai seluruh notifikasi kader sebagai sudah dibaca
+func BacaSemuaNotifikasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		tag, err := dbpool.Exec(context.Background(), "UPDATE notifikasi SET dibaca_at = NOW() WHERE id_kader = $1 AND dibaca_at IS NULL", kaderId)
+		if err != nil {
+			log.Printf("ERROR marking all notifikasi as read for kader %d: %v", kaderId, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Semua notifikasi ditandai sudah dibaca.", "jumlah": tag.RowsAffected()})
+	}
+}
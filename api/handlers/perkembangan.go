@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/growth"
 	"github.com/nadhifhafizp/api/models"
 )
 
@@ -37,9 +38,57 @@ func TambahPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(),
-			`INSERT INTO perkembangan (id_anak, tanggal_pemeriksaan, bb_kg, tb_cm, lk_cm, ll_cm, status_gizi, saran, id_kader_pencatat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-			payload.IdAnak, tglPemeriksaan, payload.BbKg, payload.TbCm, payload.LkCm, payload.LlCm, payload.StatusGizi, payload.Saran, kaderId)
+		ctx := context.Background()
+		anak, err := ambilDataAnakGizi(ctx, dbpool, payload.IdAnak)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying anak %d for perkembangan: %v", payload.IdAnak, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data perkembangan."})
+			}
+			return
+		}
+		if tglPemeriksaan.Before(anak.TanggalLahir) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal pemeriksaan tidak boleh sebelum tanggal lahir anak."})
+			return
+		}
+
+		// Status gizi dihitung dari pengukuran; isian kader hanya dipakai jika tidak dapat dihitung
+		tbStandar := panjangStandar(anak, tglPemeriksaan, payload.TbCm, payload.CaraUkur)
+		gizi := hitungGizi(anak, tglPemeriksaan, payload.BbKg, tbStandar, payload.LkCm, payload.LlCm)
+		statusGizi := payload.StatusGizi
+		if gizi.StatusGizi != nil {
+			statusGizi = gizi.StatusGizi
+		}
+
+		peringatan, alasan, ok := validasiKewajaranPerkembangan(ctx, c, dbpool, payload.IdAnak, 0, tglPemeriksaan,
+			payload.BbKg, payload.TbCm, tbStandar, payload.LkCm, payload.LlCm, gizi, payload.KonfirmasiPeringatan, payload.AlasanKonfirmasi)
+		if !ok {
+			return
+		}
+
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for perkembangan anak %d: %v", payload.IdAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data perkembangan."})
+			return
+		}
+		defer tx.Rollback(ctx)
+		if err := kunciAnak(ctx, tx, payload.IdAnak); err != nil {
+			log.Printf("ERROR locking anak %d for perkembangan: %v", payload.IdAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data perkembangan."})
+			return
+		}
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO perkembangan (id_anak, tanggal_pemeriksaan, bb_kg, tb_cm, lk_cm, ll_cm, status_gizi, saran, id_kader_pencatat, id_posyandu,
+                zs_bbu, zs_tbu, zs_bbtb, zs_imtu, zs_lku, zs_lilau, status_bbu, status_tbu, status_bbtb, status_imtu, status_lku, peringatan, alasan_konfirmasi, cara_ukur, tb_cm_standar, status_asi)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT id_posyandu FROM kader WHERE id = $9), $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25) RETURNING id`,
+			payload.IdAnak, tglPemeriksaan, payload.BbKg, payload.TbCm, payload.LkCm, payload.LlCm, statusGizi, payload.Saran, kaderId,
+			gizi.ZBBU, gizi.ZTBU, gizi.ZBBTB, gizi.ZIMTU, gizi.ZLKU, gizi.ZLILAU, gizi.StatusBBU, gizi.StatusTBU, gizi.StatusBBTB, gizi.StatusIMTU, gizi.StatusLKU,
+			peringatan, alasan, payload.CaraUkur, tbStandar, payload.StatusAsi).Scan(&id)
 
 		if err != nil {
 			log.Printf("ERROR inserting perkembangan by kader %d: %v", kaderId, err)
@@ -52,7 +101,41 @@ func TambahPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data perkembangan."})
 			return
 		}
-		c.JSON(http.StatusCreated, gin.H{"message": "Data perkembangan berhasil dicatat!"})
+
+		respon := gin.H{"message": "Data perkembangan berhasil dicatat!", "id": id, "status_gizi": statusGizi}
+		respon["usia_hari"], respon["usia_koreksi_hari"] = usiaKronologisDanKoreksi(anak.TanggalLahir, anak.UsiaKehamilanLahirMinggu, tglPemeriksaan)
+		if err := evaluasiKMSAnak(ctx, tx, payload.IdAnak); err != nil {
+			log.Printf("ERROR evaluating KMS for anak %d: %v", payload.IdAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menilai status KMS."})
+			return
+		}
+		var kmsStatus *string
+		var kms2T, kmsBgm bool
+		var kenaikan *int
+		if err := tx.QueryRow(ctx, "SELECT kms_status, kms_2t, kms_bgm, kenaikan_bb_gram FROM perkembangan WHERE id = $1", id).Scan(&kmsStatus, &kms2T, &kmsBgm, &kenaikan); err != nil {
+			log.Printf("ERROR querying KMS status of perkembangan %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menilai status KMS."})
+			return
+		}
+		respon["kms_status"] = kmsStatus
+		respon["kms_2t"] = kms2T
+		respon["kms_bgm"] = kmsBgm
+		respon["kenaikan_bb_gram"] = kenaikan
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing perkembangan anak %d: %v", payload.IdAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data perkembangan."})
+			return
+		}
+		if err := bukaKasusGiziOtomatis(ctx, dbpool, payload.IdAnak, id, tglPemeriksaan, gizi); err != nil {
+			log.Printf("ERROR opening kasus gizi for anak %d: %v", payload.IdAnak, err)
+		}
+		// Usulan saran dihitung setelah status KMS terisi; saran yang diketik kader tetap disimpan apa adanya
+		if usulan, err := susunSaran(ctx, dbpool, id); err != nil {
+			log.Printf("ERROR building saran for perkembangan %d: %v", id, err)
+		} else {
+			respon["saran_usulan"] = usulan
+		}
+		c.JSON(http.StatusCreated, respon)
 	}
 }
 
@@ -63,7 +146,7 @@ func GetPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		searchQuery := c.Query("search")
 		idAnakQuery := c.Query("id_anak")
 
-		baseQuery := `SELECT p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.lk_cm, p.ll_cm, p.status_gizi, p.saran, p.id_kader_pencatat, p.created_at, p.updated_at, a.nama_anak, k.nama_lengkap AS nama_kader, a.nik_anak, i.nama_lengkap AS nama_ibu FROM perkembangan p JOIN anak a ON p.id_anak = a.id JOIN ibu i ON a.id_ibu = i.id LEFT JOIN kader k ON p.id_kader_pencatat = k.id`
+		baseQuery := `SELECT p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.cara_ukur, p.tb_cm_standar, p.lk_cm, p.ll_cm, p.status_gizi, p.zs_bbu, p.zs_tbu, p.zs_bbtb, p.zs_imtu, p.zs_lku, p.zs_lilau, p.status_bbu, p.status_tbu, p.status_bbtb, p.status_imtu, p.status_lku, p.kms_status, p.kms_2t, p.kms_bgm, p.kenaikan_bb_gram, p.kbm_gram, p.peringatan, p.alasan_konfirmasi, p.saran, p.status_asi, p.id_kader_pencatat, p.id_posyandu, p.created_at, p.updated_at, a.tanggal_lahir, a.usia_kehamilan_lahir_minggu, a.nama_anak, k.nama_lengkap AS nama_kader, a.nik_anak, i.nama_lengkap AS nama_ibu, ps.nama AS nama_posyandu FROM perkembangan p JOIN anak a ON p.id_anak = a.id JOIN ibu i ON a.id_ibu = i.id LEFT JOIN kader k ON p.id_kader_pencatat = k.id LEFT JOIN posyandu ps ON p.id_posyandu = ps.id`
 
 		var args []interface{}
 		var conditions []string
@@ -83,6 +166,9 @@ func GetPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 				args = append(args, idAnak)
 				argCounter++
 			}
+		} else if !sertakanNonaktif(c) {
+			// Riwayat satu anak tetap ditampilkan apa pun statusnya
+			conditions = append(conditions, kondisiAnakAktif)
 		}
 
 		if len(conditions) > 0 {
@@ -101,11 +187,14 @@ func GetPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 
 		for rows.Next() {
 			var p models.Perkembangan
-			if err := rows.Scan(&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.LkCm, &p.LlCm, &p.StatusGizi, &p.Saran, &p.IdKaderPencatat, &p.CreatedAt, &p.UpdatedAt, &p.NamaAnak, &p.NamaKader, &p.NikAnak, &p.NamaIbu); err != nil {
+			var tanggalLahir time.Time
+			var usiaKehamilan *int
+			if err := rows.Scan(&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.CaraUkur, &p.TbCmStandar, &p.LkCm, &p.LlCm, &p.StatusGizi, &p.ZsBbu, &p.ZsTbu, &p.ZsBbtb, &p.ZsImtu, &p.ZsLku, &p.ZsLilau, &p.StatusBbu, &p.StatusTbu, &p.StatusBbtb, &p.StatusImtu, &p.StatusLku, &p.KmsStatus, &p.Kms2T, &p.KmsBgm, &p.KenaikanBbGram, &p.KbmGram, &p.Peringatan, &p.AlasanKonfirmasi, &p.Saran, &p.StatusAsi, &p.IdKaderPencatat, &p.IdPosyandu, &p.CreatedAt, &p.UpdatedAt, &tanggalLahir, &usiaKehamilan, &p.NamaAnak, &p.NamaKader, &p.NikAnak, &p.NamaIbu, &p.NamaPosyandu); err != nil {
 				log.Printf("ERROR scanning perkembangan row: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
 				return
 			}
+			p.UsiaHari, p.UsiaKoreksiHari = usiaKronologisDanKoreksi(tanggalLahir, usiaKehamilan, p.TanggalPemeriksaan)
 			daftarPerkembangan = append(daftarPerkembangan, p)
 		}
 
@@ -129,8 +218,10 @@ func GetPerkembanganByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		var p models.Perkembangan
-		query := `SELECT p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.lk_cm, p.ll_cm, p.status_gizi, p.saran, p.id_kader_pencatat, p.created_at, p.updated_at, a.nama_anak, k.nama_lengkap AS nama_kader, a.nik_anak, i.nama_lengkap AS nama_ibu FROM perkembangan p JOIN anak a ON p.id_anak = a.id JOIN ibu i ON a.id_ibu = i.id LEFT JOIN kader k ON p.id_kader_pencatat = k.id WHERE p.id = $1`
-		err = dbpool.QueryRow(context.Background(), query, id).Scan(&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.LkCm, &p.LlCm, &p.StatusGizi, &p.Saran, &p.IdKaderPencatat, &p.CreatedAt, &p.UpdatedAt, &p.NamaAnak, &p.NamaKader, &p.NikAnak, &p.NamaIbu)
+		var tanggalLahir time.Time
+		var usiaKehamilan *int
+		query := `SELECT p.id, p.id_anak, p.tanggal_pemeriksaan, p.bb_kg, p.tb_cm, p.cara_ukur, p.tb_cm_standar, p.lk_cm, p.ll_cm, p.status_gizi, p.zs_bbu, p.zs_tbu, p.zs_bbtb, p.zs_imtu, p.zs_lku, p.zs_lilau, p.status_bbu, p.status_tbu, p.status_bbtb, p.status_imtu, p.status_lku, p.kms_status, p.kms_2t, p.kms_bgm, p.kenaikan_bb_gram, p.kbm_gram, p.peringatan, p.alasan_konfirmasi, p.saran, p.status_asi, p.id_kader_pencatat, p.id_posyandu, p.created_at, p.updated_at, a.tanggal_lahir, a.usia_kehamilan_lahir_minggu, a.nama_anak, k.nama_lengkap AS nama_kader, a.nik_anak, i.nama_lengkap AS nama_ibu, ps.nama AS nama_posyandu FROM perkembangan p JOIN anak a ON p.id_anak = a.id JOIN ibu i ON a.id_ibu = i.id LEFT JOIN kader k ON p.id_kader_pencatat = k.id LEFT JOIN posyandu ps ON p.id_posyandu = ps.id WHERE p.id = $1`
+		err = dbpool.QueryRow(context.Background(), query, id).Scan(&p.ID, &p.IdAnak, &p.TanggalPemeriksaan, &p.BbKg, &p.TbCm, &p.CaraUkur, &p.TbCmStandar, &p.LkCm, &p.LlCm, &p.StatusGizi, &p.ZsBbu, &p.ZsTbu, &p.ZsBbtb, &p.ZsImtu, &p.ZsLku, &p.ZsLilau, &p.StatusBbu, &p.StatusTbu, &p.StatusBbtb, &p.StatusImtu, &p.StatusLku, &p.KmsStatus, &p.Kms2T, &p.KmsBgm, &p.KenaikanBbGram, &p.KbmGram, &p.Peringatan, &p.AlasanKonfirmasi, &p.Saran, &p.StatusAsi, &p.IdKaderPencatat, &p.IdPosyandu, &p.CreatedAt, &p.UpdatedAt, &tanggalLahir, &usiaKehamilan, &p.NamaAnak, &p.NamaKader, &p.NikAnak, &p.NamaIbu, &p.NamaPosyandu)
 
 		if err != nil {
 			if err.Error() == "no rows in result set" {
@@ -141,6 +232,7 @@ func GetPerkembanganByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			}
 			return
 		}
+		p.UsiaHari, p.UsiaKoreksiHari = usiaKronologisDanKoreksi(tanggalLahir, usiaKehamilan, p.TanggalPemeriksaan)
 		c.JSON(http.StatusOK, p)
 	}
 }
@@ -167,9 +259,68 @@ func UpdatePerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(),
-			`UPDATE perkembangan SET id_anak = $1, tanggal_pemeriksaan = $2, bb_kg = $3, tb_cm = $4, lk_cm = $5, ll_cm = $6, status_gizi = $7, saran = $8, updated_at = NOW() WHERE id = $9`,
-			payload.IdAnak, tglPemeriksaan, payload.BbKg, payload.TbCm, payload.LkCm, payload.LlCm, payload.StatusGizi, payload.Saran, id)
+		ctx := context.Background()
+		anak, err := ambilDataAnakGizi(ctx, dbpool, payload.IdAnak)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "ID Anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying anak %d for perkembangan %d: %v", payload.IdAnak, id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			}
+			return
+		}
+		if tglPemeriksaan.Before(anak.TanggalLahir) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal pemeriksaan tidak boleh sebelum tanggal lahir anak."})
+			return
+		}
+
+		var idAnakLama int
+		if err := dbpool.QueryRow(ctx, "SELECT id_anak FROM perkembangan WHERE id = $1", id).Scan(&idAnakLama); err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying perkembangan ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			}
+			return
+		}
+
+		tbStandar := panjangStandar(anak, tglPemeriksaan, payload.TbCm, payload.CaraUkur)
+		gizi := hitungGizi(anak, tglPemeriksaan, payload.BbKg, tbStandar, payload.LkCm, payload.LlCm)
+		statusGizi := payload.StatusGizi
+		if gizi.StatusGizi != nil {
+			statusGizi = gizi.StatusGizi
+		}
+
+		peringatan, alasan, ok := validasiKewajaranPerkembangan(ctx, c, dbpool, payload.IdAnak, id, tglPemeriksaan,
+			payload.BbKg, payload.TbCm, tbStandar, payload.LkCm, payload.LlCm, gizi, payload.KonfirmasiPeringatan, payload.AlasanKonfirmasi)
+		if !ok {
+			return
+		}
+
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for perkembangan ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		defer tx.Rollback(ctx)
+		if err := kunciAnak(ctx, tx, payload.IdAnak, idAnakLama); err != nil {
+			log.Printf("ERROR locking anak for perkembangan ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE perkembangan SET id_anak = $1, tanggal_pemeriksaan = $2, bb_kg = $3, tb_cm = $4, lk_cm = $5, ll_cm = $6, status_gizi = $7, saran = $8,
+                zs_bbu = $9, zs_tbu = $10, zs_bbtb = $11, zs_imtu = $12, zs_lku = $13, zs_lilau = $14,
+                status_bbu = $15, status_tbu = $16, status_bbtb = $17, status_imtu = $18, status_lku = $19,
+                peringatan = $20, alasan_konfirmasi = $21, cara_ukur = $22, tb_cm_standar = $23, status_asi = $24, updated_at = NOW() WHERE id = $25`,
+			payload.IdAnak, tglPemeriksaan, payload.BbKg, payload.TbCm, payload.LkCm, payload.LlCm, statusGizi, payload.Saran,
+			gizi.ZBBU, gizi.ZTBU, gizi.ZBBTB, gizi.ZIMTU, gizi.ZLKU, gizi.ZLILAU,
+			gizi.StatusBBU, gizi.StatusTBU, gizi.StatusBBTB, gizi.StatusIMTU, gizi.StatusLKU,
+			peringatan, alasan, payload.CaraUkur, tbStandar, payload.StatusAsi, id)
 
 		if err != nil {
 			log.Printf("ERROR updating perkembangan ID %d: %v", id, err)
@@ -182,7 +333,28 @@ func UpdatePerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "Data perkembangan berhasil diperbarui!"})
+
+		// Data dipindah ke anak lain: riwayat anak semula juga perlu dinilai ulang
+		idsAnak := []int{payload.IdAnak}
+		if idAnakLama != payload.IdAnak {
+			idsAnak = append(idsAnak, idAnakLama)
+		}
+		for _, idAnak := range idsAnak {
+			if err := evaluasiKMSAnak(ctx, tx, idAnak); err != nil {
+				log.Printf("ERROR evaluating KMS for anak %d: %v", idAnak, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menilai status KMS."})
+				return
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing perkembangan ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if err := bukaKasusGiziOtomatis(ctx, dbpool, payload.IdAnak, id, tglPemeriksaan, gizi); err != nil {
+			log.Printf("ERROR opening kasus gizi for anak %d: %v", payload.IdAnak, err)
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Data perkembangan berhasil diperbarui!", "status_gizi": statusGizi})
 	}
 }
 
@@ -196,12 +368,66 @@ func DeletePerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		_, err = dbpool.Exec(context.Background(), "DELETE FROM perkembangan WHERE id = $1", id)
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
 		if err != nil {
+			log.Printf("ERROR starting transaction for deleting perkembangan ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var idAnak int
+		err = tx.QueryRow(ctx, "DELETE FROM perkembangan WHERE id = $1 RETURNING id_anak", id).Scan(&idAnak)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan."})
+				return
+			}
 			log.Printf("ERROR deleting perkembangan ID %d: %v", id, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
 			return
 		}
+		// Penimbangan sesudahnya kini dibandingkan dengan penimbangan sebelum data yang dihapus
+		if err := evaluasiKMSAnak(ctx, tx, idAnak); err != nil {
+			log.Printf("ERROR evaluating KMS for anak %d: %v", idAnak, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menilai status KMS."})
+			return
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing deletion of perkembangan ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "Data perkembangan berhasil dihapus!"})
 	}
 }
+
+// validasiKewajaranPerkembangan menjalankan pemeriksaan kewajaran pengukuran dan menulis respon error bila
+// data ditolak atau peringatan belum dikonfirmasi. Mengembalikan peringatan dan alasan yang akan disimpan;
+// ok bernilai false jika respon sudah dikirim.
+func validasiKewajaranPerkembangan(ctx context.Context, c *gin.Context, dbpool *pgxpool.Pool, idAnak, idPerkembangan int, tanggal time.Time,
+	bbKg, tbCmUkur, tbCm, lkCm, llCm *float64, gizi growth.Hasil, konfirmasi bool, alasanKonfirmasi *string) (peringatan []string, alasan *string, ok bool) {
+	galat, peringatan, err := periksaPengukuran(ctx, dbpool, idAnak, idPerkembangan, tanggal, bbKg, tbCmUkur, tbCm, lkCm, llCm, gizi)
+	if err != nil {
+		log.Printf("ERROR checking plausibility for anak %d: %v", idAnak, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data pengukuran."})
+		return nil, nil, false
+	}
+	if galat != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": galat})
+		return nil, nil, false
+	}
+	if len(peringatan) == 0 {
+		return []string{}, nil, true
+	}
+	if !konfirmasi || alasanKonfirmasi == nil || strings.TrimSpace(*alasanKonfirmasi) == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "Pengukuran perlu diperiksa ulang. Kirim ulang dengan konfirmasi_peringatan dan alasan_konfirmasi jika data sudah benar.",
+			"peringatan":       peringatan,
+			"perlu_konfirmasi": true,
+		})
+		return nil, nil, false
+	}
+	return peringatan, alasanKonfirmasi, true
+}
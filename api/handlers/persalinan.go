@@ -0,0 +1,211 @@
+// handlers/persalinan.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// TambahPersalinanHandler mencatat hasil persalinan sebuah kehamilan, menandai kehamilan selesai,
+// dan mendaftarkan setiap bayi lahir hidup (termasuk kembar) sebagai anak dari ibu yang sama.
+func TambahPersalinanHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idKehamilan, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID kehamilan tidak valid"})
+			return
+		}
+
+		var payload models.TambahPersalinanPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+
+		tglPersalinan, err := time.Parse("2006-01-02", payload.TanggalPersalinan)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal persalinan salah (YYYY-MM-DD)."})
+			return
+		}
+		if tglPersalinan.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal persalinan tidak boleh di masa depan."})
+			return
+		}
+		if msg := validasiBayiPersalinan(payload.Hasil, payload.Bayi); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for persalinan kehamilan %d: %v", idKehamilan, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data persalinan."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		// Kunci baris kehamilan agar persalinan tidak tercatat dua kali secara bersamaan
+		var idIbu, para int
+		var hpht time.Time
+		var status string
+		err = tx.QueryRow(ctx, "SELECT id_ibu, hpht, para, status FROM kehamilan WHERE id = $1 FOR UPDATE", idKehamilan).Scan(&idIbu, &hpht, &para, &status)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data kehamilan tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying kehamilan %d for persalinan: %v", idKehamilan, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data kehamilan."})
+			}
+			return
+		}
+		if status != "aktif" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Kehamilan ini sudah selesai."})
+			return
+		}
+		if tglPersalinan.Before(hpht) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal persalinan tidak boleh sebelum HPHT."})
+			return
+		}
+
+		usiaKehamilan := hitungUsiaKehamilanMinggu(hpht, tglPersalinan)
+		if payload.UsiaKehamilanMinggu != nil {
+			usiaKehamilan = *payload.UsiaKehamilanMinggu
+		}
+		if usiaKehamilan > 45 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Usia kehamilan dari HPHT lebih dari 45 minggu. Periksa HPHT atau isi usia_kehamilan_minggu."})
+			return
+		}
+		if usiaKehamilan < 20 && payload.Hasil != "keguguran" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Usia kehamilan di bawah 20 minggu dicatat sebagai keguguran."})
+			return
+		}
+
+		var idPersalinan int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO persalinan (id_kehamilan, tanggal_persalinan, tempat, penolong, usia_kehamilan_minggu, hasil, jumlah_bayi, catatan, id_kader_pencatat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			idKehamilan, tglPersalinan, payload.Tempat, payload.Penolong, usiaKehamilan, payload.Hasil, len(payload.Bayi), payload.Catatan, kaderId).Scan(&idPersalinan)
+		if err != nil {
+			log.Printf("ERROR inserting persalinan for kehamilan %d: %v", idKehamilan, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == "persalinan_id_kehamilan_key" {
+				c.JSON(http.StatusConflict, gin.H{"error": "Persalinan untuk kehamilan ini sudah dicatat."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data persalinan."})
+			return
+		}
+
+		// Urutan anak: setelah paritas tercatat atau jumlah anak terdaftar, mana yang lebih besar
+		var jumlahAnak int
+		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM anak WHERE id_ibu = $1", idIbu).Scan(&jumlahAnak); err != nil {
+			log.Printf("ERROR counting anak of ibu %d: %v", idIbu, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data persalinan."})
+			return
+		}
+		anakKe := max(para, jumlahAnak)
+
+		// Usia kehamilan disalin ke anak untuk koreksi umur prematur; nilai di luar rentang wajar tidak disalin
+		var usiaKehamilanLahir *int
+		if usiaKehamilan >= 22 && usiaKehamilan <= 44 {
+			usiaKehamilanLahir = &usiaKehamilan
+		}
+
+		idAnakBaru := make([]int, 0, len(payload.Bayi))
+		for _, bayi := range payload.Bayi {
+			if bayi.LahirHidup != nil && !*bayi.LahirHidup {
+				continue
+			}
+			anakKe++
+			var idAnak int
+			err = tx.QueryRow(ctx,
+				`INSERT INTO anak (id_ibu, nama_anak, nik_anak, tanggal_lahir, jenis_kelamin, anak_ke, berat_lahir_kg, tinggi_lahir_cm, id_persalinan, id_posyandu, usia_kehamilan_lahir_minggu) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT id_posyandu FROM kader WHERE id = $10), $11) RETURNING id`,
+				idIbu, bayi.NamaAnak, bayi.NikAnak, tglPersalinan, bayi.JenisKelamin, anakKe, bayi.BeratLahirKg, bayi.TinggiLahirCm, idPersalinan, kaderId, usiaKehamilanLahir).Scan(&idAnak)
+			if err != nil {
+				log.Printf("ERROR inserting anak from persalinan %d: %v", idPersalinan, err)
+				if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" && pgErr.ConstraintName == "anak_nik_anak_key" {
+					c.JSON(http.StatusConflict, gin.H{"error": "NIK anak " + bayi.NamaAnak + " sudah terdaftar."})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mendaftarkan anak."})
+				return
+			}
+			idAnakBaru = append(idAnakBaru, idAnak)
+		}
+
+		if _, err := tx.Exec(ctx, "UPDATE kehamilan SET status = 'selesai', updated_at = NOW() WHERE id = $1", idKehamilan); err != nil {
+			log.Printf("ERROR closing kehamilan %d: %v", idKehamilan, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data persalinan."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing persalinan for kehamilan %d: %v", idKehamilan, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data persalinan."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Persalinan berhasil dicatat!", "id": idPersalinan, "id_anak": idAnakBaru})
+	}
+}
+
+// getPersalinanByKehamilan mengambil data persalinan sebuah kehamilan (nil jika belum ada)
+func getPersalinanByKehamilan(ctx context.Context, dbpool *pgxpool.Pool, idKehamilan int) (*models.Persalinan, error) {
+	var p models.Persalinan
+	err := dbpool.QueryRow(ctx,
+		`SELECT p.id, p.id_kehamilan, p.tanggal_persalinan, p.tempat, p.penolong, p.usia_kehamilan_minggu, p.hasil, p.jumlah_bayi, p.catatan, p.id_kader_pencatat, p.created_at,
+            COALESCE((SELECT ARRAY_AGG(a.id ORDER BY a.anak_ke) FROM anak a WHERE a.id_persalinan = p.id), '{}')
+        FROM persalinan p WHERE p.id_kehamilan = $1`, idKehamilan).
+		Scan(&p.ID, &p.IdKehamilan, &p.TanggalPersalinan, &p.Tempat, &p.Penolong, &p.UsiaKehamilanMinggu, &p.Hasil, &p.JumlahBayi, &p.Catatan, &p.IdKaderPencatat, &p.CreatedAt, &p.IdAnak)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// validasiBayiPersalinan memeriksa kecocokan daftar bayi dengan hasil persalinan.
+// Mengembalikan pesan error (kosong jika valid).
+func validasiBayiPersalinan(hasil string, bayi []models.PersalinanBayiInput) string {
+	jumlahHidup := 0
+	for _, b := range bayi {
+		if b.NikAnak != nil && len(*b.NikAnak) > 16 {
+			return "NIK Anak max 16 karakter."
+		}
+		if b.LahirHidup == nil || *b.LahirHidup {
+			jumlahHidup++
+		}
+	}
+	switch hasil {
+	case "keguguran":
+		if len(bayi) > 0 {
+			return "Data bayi tidak perlu diisi untuk keguguran."
+		}
+	case "lahir_mati":
+		if jumlahHidup > 0 {
+			return "Hasil lahir mati tidak boleh memiliki bayi lahir hidup."
+		}
+	case "lahir_hidup":
+		if jumlahHidup == 0 {
+			return "Minimal satu bayi lahir hidup harus diisi."
+		}
+	}
+	return ""
+}
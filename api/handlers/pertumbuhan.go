@@ -0,0 +1,149 @@
+// handlers/pertumbuhan.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/growth"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// ukuranLalu adalah pengukuran sebelumnya yang dipakai untuk menghitung kecepatan
+type ukuranLalu struct {
+	usiaHari int // Umur kronologis, untuk menghitung jarak antar pengukuran
+	nilai    float64
+}
+
+// susunPertumbuhan menyusun deret pertumbuhan seorang anak: z-score setiap pengukuran,
+// kecepatan berat dan panjang badan, dan episode gagal tumbuh.
+// Z-score dihitung ulang dari ukuran mentah agar data lama tanpa z-score ikut terisi.
+func susunPertumbuhan(ctx context.Context, dbpool *pgxpool.Pool, idAnak int) (models.PertumbuhanAnak, error) {
+	p := models.PertumbuhanAnak{IdAnak: idAnak, Titik: make([]models.TitikPertumbuhan, 0), Faltering: make([]models.FalteringPertumbuhan, 0)}
+	err := dbpool.QueryRow(ctx, "SELECT nama_anak, jenis_kelamin, tanggal_lahir, usia_kehamilan_lahir_minggu FROM anak WHERE id = $1", idAnak).
+		Scan(&p.NamaAnak, &p.JenisKelamin, &p.TanggalLahir, &p.UsiaKehamilanLahirMinggu)
+	if err != nil {
+		return p, err
+	}
+	anak := dataAnakGizi{TanggalLahir: p.TanggalLahir, JenisKelamin: p.JenisKelamin, UsiaKehamilanLahirMinggu: p.UsiaKehamilanLahirMinggu}
+
+	rows, err := dbpool.Query(ctx,
+		`SELECT id, tanggal_pemeriksaan, bb_kg, tb_cm, cara_ukur, lk_cm, ll_cm, status_gizi
+        FROM perkembangan WHERE id_anak = $1 ORDER BY tanggal_pemeriksaan ASC, id ASC`, idAnak)
+	if err != nil {
+		return p, err
+	}
+	defer rows.Close()
+
+	var bbLalu *ukuranLalu
+	var tbLalu *ukuranLalu
+	for rows.Next() {
+		var t models.TitikPertumbuhan
+		var tbUkur, llCm *float64
+		var caraUkur, statusTersimpan *string
+		if err := rows.Scan(&t.IdPerkembangan, &t.TanggalPemeriksaan, &t.BbKg, &tbUkur, &caraUkur, &t.LkCm, &llCm, &statusTersimpan); err != nil {
+			return p, err
+		}
+		t.UsiaHari, t.UsiaKoreksiHari = usiaKronologisDanKoreksi(anak.TanggalLahir, anak.UsiaKehamilanLahirMinggu, t.TanggalPemeriksaan)
+		t.UsiaBulan = math.Round(float64(t.UsiaHari)/growth.HariPerBulan*10) / 10
+		if t.UsiaKoreksiHari != nil {
+			bulan := math.Round(float64(*t.UsiaKoreksiHari)/growth.HariPerBulan*10) / 10
+			t.UsiaKoreksiBulan = &bulan
+		}
+		t.TbCm = panjangStandar(anak, t.TanggalPemeriksaan, tbUkur, caraUkur)
+
+		gizi := hitungGizi(anak, t.TanggalPemeriksaan, t.BbKg, t.TbCm, t.LkCm, llCm)
+		t.ZsBBU, t.ZsTBU, t.ZsBBTB, t.ZsIMTU, t.ZsLKU = gizi.ZBBU, gizi.ZTBU, gizi.ZBBTB, gizi.ZIMTU, gizi.ZLKU
+		t.StatusGizi = gizi.StatusGizi
+		if t.StatusGizi == nil {
+			t.StatusGizi = statusTersimpan
+		}
+
+		if t.BbKg != nil {
+			if bbLalu != nil && t.UsiaHari > bbLalu.usiaHari {
+				hari := t.UsiaHari - bbLalu.usiaHari
+				gramBulan := math.Round((*t.BbKg - bbLalu.nilai) * 1000 * growth.HariPerBulan / float64(hari))
+				t.KecepatanBbGramBulan = &gramBulan
+			}
+			bbLalu = &ukuranLalu{usiaHari: t.UsiaHari, nilai: *t.BbKg}
+		}
+
+		if tbUkur != nil {
+			cara := ""
+			if caraUkur != nil {
+				cara = *caraUkur
+			}
+			// Kecepatan dihitung pada panjang setara telentang agar tidak meloncat 0,7 cm di umur 24 bulan
+			tb := ukuranLalu{usiaHari: t.UsiaHari, nilai: growth.PanjangTelentang(*tbUkur, anak.usiaPenilaian(t.TanggalPemeriksaan), cara)}
+			if tbLalu != nil && t.UsiaHari > tbLalu.usiaHari {
+				cmBulan := math.Round((tb.nilai-tbLalu.nilai)*growth.HariPerBulan/float64(t.UsiaHari-tbLalu.usiaHari)*100) / 100
+				t.KecepatanTbCmBulan = &cmBulan
+			}
+			tbLalu = &tb
+		}
+
+		p.Titik = append(p.Titik, t)
+	}
+	if err := rows.Err(); err != nil {
+		return p, err
+	}
+
+	for _, ind := range []struct {
+		kode string
+		z    func(t models.TitikPertumbuhan) *float64
+	}{
+		{string(growth.BBU), func(t models.TitikPertumbuhan) *float64 { return t.ZsBBU }},
+		{string(growth.TBU), func(t models.TitikPertumbuhan) *float64 { return t.ZsTBU }},
+	} {
+		var deret []float64
+		var indeksTitik []int
+		for i, t := range p.Titik {
+			if z := ind.z(t); z != nil {
+				deret = append(deret, *z)
+				indeksTitik = append(indeksTitik, i)
+			}
+		}
+		for _, f := range growth.DeteksiFaltering(deret) {
+			mulai := p.Titik[indeksTitik[f.IndeksMulai]]
+			p.Faltering = append(p.Faltering, models.FalteringPertumbuhan{
+				Indikator:      ind.kode,
+				IdPerkembangan: mulai.IdPerkembangan,
+				TanggalMulai:   mulai.TanggalPemeriksaan,
+				TanggalPuncak:  p.Titik[indeksTitik[f.IndeksPuncak]].TanggalPemeriksaan,
+				ZPuncak:        f.ZPuncak,
+				Z:              f.Z,
+				Penurunan:      f.Penurunan,
+			})
+		}
+	}
+	return p, nil
+}
+
+// GetPertumbuhanAnakHandler menangani pengambilan deret pertumbuhan anak untuk grafik dan ekspor
+func GetPertumbuhanAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+
+		pertumbuhan, err := susunPertumbuhan(context.Background(), dbpool, id)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR building growth series for anak %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pertumbuhan."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, pertumbuhan)
+	}
+}
@@ -0,0 +1,126 @@
+// handlers/posyandu.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// TambahPosyanduHandler menangani penambahan posyandu baru
+func TambahPosyanduHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload models.PosyanduPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nama posyandu wajib diisi."})
+			return
+		}
+
+		_, err := dbpool.Exec(context.Background(),
+			`INSERT INTO posyandu (nama, desa, kecamatan) VALUES ($1, $2, $3)`,
+			payload.Nama, payload.Desa, payload.Kecamatan)
+
+		if err != nil {
+			log.Printf("ERROR inserting posyandu: %v", err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				if pgErr.ConstraintName == "posyandu_nama_desa_key" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Posyandu dengan nama ini sudah ada di desa tersebut."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan posyandu."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Posyandu berhasil ditambahkan!"})
+	}
+}
+
+// GetPosyanduHandler menangani pengambilan daftar posyandu
+func GetPosyanduHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		daftarPosyandu := make([]models.Posyandu, 0)
+		searchQuery := c.Query("search")
+		query := "SELECT id, nama, desa, kecamatan, created_at, updated_at FROM posyandu"
+		var args []interface{}
+
+		if searchQuery != "" {
+			query += " WHERE nama ILIKE $1 OR desa ILIKE $1 OR kecamatan ILIKE $1"
+			args = append(args, fmt.Sprintf("%%%s%%", searchQuery))
+		}
+		query += " ORDER BY nama ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying posyandu: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data posyandu."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p models.Posyandu
+			if err := rows.Scan(&p.ID, &p.Nama, &p.Desa, &p.Kecamatan, &p.CreatedAt, &p.UpdatedAt); err != nil {
+				log.Printf("ERROR scanning posyandu row: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data posyandu."})
+				return
+			}
+			daftarPosyandu = append(daftarPosyandu, p)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR after iterating posyandu rows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar posyandu."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarPosyandu)
+	}
+}
+
+// UpdatePosyanduHandler menangani pembaruan data posyandu
+func UpdatePosyanduHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+			return
+		}
+
+		var payload models.PosyanduPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nama posyandu wajib diisi."})
+			return
+		}
+
+		_, err = dbpool.Exec(context.Background(),
+			`UPDATE posyandu SET nama = $1, desa = $2, kecamatan = $3, updated_at = NOW() WHERE id = $4`,
+			payload.Nama, payload.Desa, payload.Kecamatan, id)
+
+		if err != nil {
+			log.Printf("ERROR updating posyandu ID %d: %v", id, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				if pgErr.ConstraintName == "posyandu_nama_desa_key" {
+					c.JSON(http.StatusConflict, gin.H{"error": "Posyandu dengan nama ini sudah ada di desa tersebut."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui posyandu."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Posyandu berhasil diperbarui!"})
+	}
+}
+
+// posyanduKader mengambil ID posyandu tempat kader bertugas (nil jika belum ditetapkan)
+func posyanduKader(ctx context.Context, dbpool *pgxpool.Pool, kaderId int) (*int, error) {
+	var idPosyandu *int
+	err := dbpool.QueryRow(ctx, "SELECT id_posyandu FROM kader WHERE id = $1", kaderId).Scan(&idPosyandu)
+	return idPosyandu, err
+}
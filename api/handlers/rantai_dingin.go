@@ -0,0 +1,795 @@
+// handlers/rantai_dingin.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+	"github.com/nadhifhafizp/api/rantaidingin"
+)
+
+// maksBacaanUnggah membatasi jumlah baris satu berkas data logger
+const maksBacaanUnggah = 20000
+
+const ekskursiSuhuSelect = `SELECT e.id, e.id_alat, a.nama, a.id_posyandu, ps.nama, e.jenis, e.mulai, e.selesai, e.suhu_ekstrem, e.jumlah_bacaan,
+        e.status, e.tindak_lanjut, e.id_kader_peninjau, e.ditinjau_at, e.digantikan_oleh, e.digantikan_at, e.created_at,
+        (SELECT COUNT(*) FROM ekskursi_batch_vaksin eb WHERE eb.id_ekskursi = e.id),
+        (SELECT COUNT(*) FROM alat_rantai_dingin x WHERE x.id_posyandu IS NOT DISTINCT FROM a.id_posyandu)
+    FROM ekskursi_suhu e
+    JOIN alat_rantai_dingin a ON e.id_alat = a.id
+    LEFT JOIN posyandu ps ON a.id_posyandu = ps.id`
+
+func scanEkskursiSuhu(row pgx.Row) (models.EkskursiSuhu, error) {
+	var e models.EkskursiSuhu
+	err := row.Scan(&e.ID, &e.IdAlat, &e.NamaAlat, &e.IdPosyandu, &e.NamaPosyandu, &e.Jenis, &e.Mulai, &e.Selesai, &e.SuhuEkstrem, &e.JumlahBacaan,
+		&e.Status, &e.TindakLanjut, &e.IdKaderPeninjau, &e.DitinjauAt, &e.DigantikanOleh, &e.DigantikanAt, &e.CreatedAt, &e.JumlahBatch, &e.JumlahAlatLokasi)
+	e.KeteranganBatch = keteranganBatchEkskursi(e.JumlahAlatLokasi)
+	return e, err
+}
+
+// keteranganBatchEkskursi menjelaskan bahwa batch dihubungkan per lokasi, karena stok vaksin dicatat
+// per posyandu (atau gudang puskesmas) dan tidak per alat rantai dingin
+func keteranganBatchEkskursi(jumlahAlat int) string {
+	k := "Batch dihubungkan menurut lokasi penyimpanan alat, bukan alatnya, karena stok vaksin dicatat per lokasi."
+	if jumlahAlat > 1 {
+		k += fmt.Sprintf(" Lokasi ini memiliki %d alat; pastikan batch yang ditinjau memang tersimpan di alat ini.", jumlahAlat)
+	}
+	return k
+}
+
+// hubungkanBatchEkskursi mencatat batch vaksin di lokasi alat yang memiliki stok pada saat mana pun selama
+// ekskursi, dari hari mulai sampai hari selesai (hari ini bila masih berlangsung). Stok awal dihitung mundur
+// dari stok sekarang; stok_dosis berisi stok awal ditambah penerimaan selama ekskursi, yaitu seluruh dosis
+// yang sempat tersimpan di lokasi saat suhu di luar rentang. Stok tidak dicatat per alat, sehingga pada
+// lokasi dengan beberapa alat hasilnya adalah pendekatan (lihat keteranganBatchEkskursi).
+func hubungkanBatchEkskursi(ctx context.Context, tx pgx.Tx, idEkskursi int, idPosyandu *int, mulai time.Time, selesai *time.Time) (int64, error) {
+	if _, err := tx.Exec(ctx, "DELETE FROM ekskursi_batch_vaksin WHERE id_ekskursi = $1", idEkskursi); err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO ekskursi_batch_vaksin (id_ekskursi, id_batch_vaksin, stok_dosis)
+        SELECT $1, b.id, GREATEST(s.awal, 0) + s.masuk
+        FROM batch_vaksin b
+        CROSS JOIN LATERAL (
+            SELECT b.stok_dosis - COALESCE(SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal >= $3::date), 0) AS awal,
+                COALESCE(SUM(t.jumlah_dosis) FILTER (WHERE t.tanggal BETWEEN $3::date AND COALESCE($4, NOW())::date AND t.jumlah_dosis > 0), 0) AS masuk
+            FROM transaksi_vaksin t WHERE t.id_batch = b.id
+        ) s
+        WHERE b.id_posyandu IS NOT DISTINCT FROM $2 AND (s.awal > 0 OR s.masuk > 0)`,
+		idEkskursi, idPosyandu, mulai, selesai)
+	return tag.RowsAffected(), err
+}
+
+// samaWaktu membandingkan dua waktu selesai ekskursi; nil berarti masih berlangsung
+func samaWaktu(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// pesanEkskursi menyusun isi notifikasi ekskursi suhu
+func pesanEkskursi(namaAlat string, d rantaidingin.Ekskursi, suhuMin, suhuMax float64, jumlahBatch int64) string {
+	return fmt.Sprintf("Suhu %s mencapai %.1f°C (rentang %.1f-%.1f°C) sejak %s. %d batch vaksin di lokasi alat ini perlu ditinjau.",
+		namaAlat, d.SuhuEkstrem, suhuMin, suhuMax, d.Mulai.In(time.Local).Format("02-01-2006 15:04"), jumlahBatch)
+}
+
+// perbaruiEkskursi mendeteksi ulang ekskursi suhu alat mulai dari bacaan baru paling awal (atau awal
+// ekskursi yang masih berlangsung saat itu). Ekskursi tersimpan sejenis yang beririsan dengan hasil
+// deteksi diperbarui; bila sudah ditinjau lalu rentang, suhu ekstrem atau jumlah bacaannya berubah,
+// statusnya kembali baru dan dinotifikasikan ulang. Ekskursi baru dihubungkan dengan batch vaksin
+// dan dinotifikasikan ke bidan dan admin. Mengembalikan jumlah ekskursi baru.
+func perbaruiEkskursi(ctx context.Context, tx pgx.Tx, idAlat int, sejak time.Time) (int, error) {
+	var namaAlat string
+	var idPosyandu *int
+	var suhuMin, suhuMax float64
+	// FOR UPDATE agar unggahan bersamaan untuk alat yang sama diproses bergantian
+	err := tx.QueryRow(ctx, "SELECT nama, id_posyandu, suhu_min, suhu_max FROM alat_rantai_dingin WHERE id = $1 FOR UPDATE", idAlat).
+		Scan(&namaAlat, &idPosyandu, &suhuMin, &suhuMax)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.QueryRow(ctx,
+		"SELECT LEAST($2::timestamptz, MIN(mulai)) FROM ekskursi_suhu WHERE id_alat = $1 AND digantikan_at IS NULL AND (selesai IS NULL OR selesai >= $2)",
+		idAlat, sejak).Scan(&sejak); err != nil {
+		return 0, err
+	}
+
+	type tersimpan struct {
+		id           int
+		jenis        string
+		mulai        time.Time
+		selesai      *time.Time
+		suhuEkstrem  float64
+		jumlahBacaan int
+		status       string
+		dipakai      bool
+	}
+	var lama []*tersimpan
+	rows, err := tx.Query(ctx,
+		`SELECT id, jenis, mulai, selesai, suhu_ekstrem, jumlah_bacaan, status FROM ekskursi_suhu
+        WHERE id_alat = $1 AND digantikan_at IS NULL AND (selesai IS NULL OR selesai >= $2) ORDER BY mulai`,
+		idAlat, sejak)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		e := &tersimpan{}
+		if err := rows.Scan(&e.id, &e.jenis, &e.mulai, &e.selesai, &e.suhuEkstrem, &e.jumlahBacaan, &e.status); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		lama = append(lama, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var bacaan []rantaidingin.Bacaan
+	rows, err = tx.Query(ctx, "SELECT waktu, suhu FROM suhu_alat WHERE id_alat = $1 AND waktu >= $2 ORDER BY waktu", idAlat, sejak)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var b rantaidingin.Bacaan
+		if err := rows.Scan(&b.Waktu, &b.Suhu); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		bacaan = append(bacaan, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	type hasilDeteksi struct {
+		id int
+		d  rantaidingin.Ekskursi
+	}
+	var hasil []hasilDeteksi
+	baru := 0
+	for _, d := range rantaidingin.Deteksi(bacaan, suhuMin, suhuMax) {
+		var cocok *tersimpan
+		for _, e := range lama {
+			if !e.dipakai && e.jenis == d.Jenis && rantaidingin.Tumpang(e.mulai, e.selesai, d.Mulai, d.Selesai) {
+				cocok = e
+				break
+			}
+		}
+		if cocok != nil {
+			cocok.dipakai = true
+			hasil = append(hasil, hasilDeteksi{cocok.id, d})
+			berubah := !cocok.mulai.Equal(d.Mulai) || !samaWaktu(cocok.selesai, d.Selesai) ||
+				cocok.suhuEkstrem != d.SuhuEkstrem || cocok.jumlahBacaan != d.JumlahBacaan
+			if !berubah {
+				continue
+			}
+			// Ekskursi yang sudah ditinjau lalu meluas atau memburuk perlu ditinjau ulang; tindak lanjut
+			// sebelumnya tetap tersimpan sampai diganti peninjauan berikutnya
+			tinjauUlang := cocok.status == "ditinjau"
+			_, err := tx.Exec(ctx,
+				`UPDATE ekskursi_suhu SET mulai = $1, selesai = $2, suhu_ekstrem = $3, jumlah_bacaan = $4,
+                    status = CASE WHEN $5 THEN 'baru' ELSE status END, updated_at = NOW() WHERE id = $6`,
+				d.Mulai, d.Selesai, d.SuhuEkstrem, d.JumlahBacaan, tinjauUlang, cocok.id)
+			if err != nil {
+				return 0, err
+			}
+			// Batch dihubungkan ulang agar penerimaan selama rentang yang baru ikut tercatat
+			jumlahBatch, err := hubungkanBatchEkskursi(ctx, tx, cocok.id, idPosyandu, d.Mulai, d.Selesai)
+			if err != nil {
+				return 0, err
+			}
+			if tinjauUlang {
+				pesan := "Ekskursi yang sudah ditinjau berlanjut atau memburuk dan perlu ditinjau ulang. " + pesanEkskursi(namaAlat, d, suhuMin, suhuMax, jumlahBatch)
+				if err := kirimNotifikasiPeran(ctx, tx, []string{"bidan", "admin"}, "ekskursi_suhu", "Ekskursi suhu rantai dingin", pesan, cocok.id); err != nil {
+					return 0, err
+				}
+			}
+			continue
+		}
+
+		var id int
+		err := tx.QueryRow(ctx,
+			`INSERT INTO ekskursi_suhu (id_alat, jenis, mulai, selesai, suhu_ekstrem, jumlah_bacaan) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			idAlat, d.Jenis, d.Mulai, d.Selesai, d.SuhuEkstrem, d.JumlahBacaan).Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+		hasil = append(hasil, hasilDeteksi{id, d})
+		jumlahBatch, err := hubungkanBatchEkskursi(ctx, tx, id, idPosyandu, d.Mulai, d.Selesai)
+		if err != nil {
+			return 0, err
+		}
+		if err := kirimNotifikasiPeran(ctx, tx, []string{"bidan", "admin"}, "ekskursi_suhu", "Ekskursi suhu rantai dingin", pesanEkskursi(namaAlat, d, suhuMin, suhuMax, jumlahBatch), id); err != nil {
+			return 0, err
+		}
+		baru++
+	}
+
+	// Ekskursi lama yang tidak lagi terdeteksi (mis. tergabung dengan ekskursi lain) tidak dihapus agar tinjauan
+	// dan batch terkaitnya tetap tersimpan; ditandai digantikan oleh ekskursi sejenis yang beririsan bila ada
+	for _, e := range lama {
+		if e.dipakai {
+			continue
+		}
+		var pengganti *int
+		for _, h := range hasil {
+			if h.d.Jenis == e.jenis && rantaidingin.Tumpang(e.mulai, e.selesai, h.d.Mulai, h.d.Selesai) {
+				pengganti = &h.id
+				break
+			}
+		}
+		if _, err := tx.Exec(ctx, "UPDATE ekskursi_suhu SET digantikan_oleh = $1, digantikan_at = NOW(), updated_at = NOW() WHERE id = $2", pengganti, e.id); err != nil {
+			return 0, err
+		}
+	}
+	return baru, nil
+}
+
+// --- Alat Rantai Dingin Handlers ---
+
+// TambahAlatRantaiDinginHandler mendaftarkan lemari es, freezer, cold box atau vaccine carrier
+func TambahAlatRantaiDinginHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload models.AlatRantaiDinginPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nama dan jenis alat (lemari_es, freezer, cold_box, vaccine_carrier) wajib diisi."})
+			return
+		}
+		suhuMin, suhuMax := rantaidingin.SuhuMinBawaan, rantaidingin.SuhuMaxBawaan
+		if payload.SuhuMin != nil {
+			suhuMin = *payload.SuhuMin
+		}
+		if payload.SuhuMax != nil {
+			suhuMax = *payload.SuhuMax
+		}
+		aktif := payload.Aktif == nil || *payload.Aktif
+
+		var id int
+		err := dbpool.QueryRow(context.Background(),
+			`INSERT INTO alat_rantai_dingin (nama, jenis, id_posyandu, nomor_seri, suhu_min, suhu_max, aktif) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+			payload.Nama, payload.Jenis, payload.IdPosyandu, payload.NomorSeri, suhuMin, suhuMax, aktif).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR inserting alat rantai dingin: %v", err)
+			if tanggapiGalatAlatRantaiDingin(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Alat rantai dingin berhasil ditambahkan!", "id": id})
+	}
+}
+
+// tanggapiGalatAlatRantaiDingin menerjemahkan pelanggaran constraint alat rantai dingin menjadi pesan yang jelas
+func tanggapiGalatAlatRantaiDingin(c *gin.Context, err error) bool {
+	pgErr, ok := err.(*pgconn.PgError)
+	if !ok {
+		return false
+	}
+	switch pgErr.ConstraintName {
+	case "alat_rantai_dingin_suhu_check":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Suhu minimal harus lebih rendah dari suhu maksimal."})
+	case "alat_rantai_dingin_id_posyandu_fkey":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Posyandu tidak ditemukan."})
+	default:
+		return false
+	}
+	return true
+}
+
+// GetAlatRantaiDinginHandler menampilkan alat rantai dingin beserta bacaan terakhir, jumlah bacaan hari ini
+// dan ekskursi yang belum ditinjau. Query: id_posyandu (opsional; "gudang" untuk gudang puskesmas), semua=true untuk menyertakan alat nonaktif.
+func GetAlatRantaiDinginHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := `SELECT a.id, a.nama, a.jenis, a.id_posyandu, ps.nama, a.nomor_seri, a.suhu_min, a.suhu_max, a.aktif, a.created_at, a.updated_at,
+                (SELECT COUNT(*) FROM suhu_alat s WHERE s.id_alat = a.id AND s.waktu >= CURRENT_DATE AND s.waktu < CURRENT_DATE + 1),
+                t.suhu, t.waktu,
+                (SELECT COUNT(*) FROM ekskursi_suhu e WHERE e.id_alat = a.id AND e.status = 'baru' AND e.digantikan_at IS NULL)
+            FROM alat_rantai_dingin a
+            LEFT JOIN posyandu ps ON a.id_posyandu = ps.id
+            LEFT JOIN LATERAL (SELECT s.suhu, s.waktu FROM suhu_alat s WHERE s.id_alat = a.id ORDER BY s.waktu DESC LIMIT 1) t ON TRUE
+            WHERE TRUE`
+		var args []interface{}
+		if c.Query("semua") != "true" {
+			query += " AND a.aktif"
+		}
+		switch idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery {
+		case "":
+		case "gudang":
+			query += " AND a.id_posyandu IS NULL"
+		default:
+			idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+				return
+			}
+			args = append(args, idPosyandu)
+			query += " AND a.id_posyandu = $1"
+		}
+		query += " ORDER BY ps.nama ASC NULLS FIRST, a.nama ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying alat rantai dingin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.AlatRantaiDingin, 0)
+		for rows.Next() {
+			var a models.AlatRantaiDingin
+			if err := rows.Scan(&a.ID, &a.Nama, &a.Jenis, &a.IdPosyandu, &a.NamaPosyandu, &a.NomorSeri, &a.SuhuMin, &a.SuhuMax, &a.Aktif, &a.CreatedAt, &a.UpdatedAt,
+				&a.BacaanHariIni, &a.SuhuTerakhir, &a.WaktuTerakhir, &a.EkskursiBaru); err != nil {
+				log.Printf("ERROR scanning alat rantai dingin: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, a)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating alat rantai dingin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// UpdateAlatRantaiDinginHandler memperbarui data alat. Rentang suhu baru berlaku untuk deteksi bacaan berikutnya.
+func UpdateAlatRantaiDinginHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID alat tidak valid"})
+			return
+		}
+		var payload models.AlatRantaiDinginPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nama dan jenis alat (lemari_es, freezer, cold_box, vaccine_carrier) wajib diisi."})
+			return
+		}
+
+		tag, err := dbpool.Exec(context.Background(),
+			`UPDATE alat_rantai_dingin SET nama = $1, jenis = $2, id_posyandu = $3, nomor_seri = $4,
+                suhu_min = COALESCE($5, suhu_min), suhu_max = COALESCE($6, suhu_max), aktif = COALESCE($7, aktif), updated_at = NOW()
+            WHERE id = $8`,
+			payload.Nama, payload.Jenis, payload.IdPosyandu, payload.NomorSeri, payload.SuhuMin, payload.SuhuMax, payload.Aktif, id)
+		if err != nil {
+			log.Printf("ERROR updating alat rantai dingin %d: %v", id, err)
+			if tanggapiGalatAlatRantaiDingin(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Alat rantai dingin tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Alat rantai dingin berhasil diupdate!"})
+	}
+}
+
+// --- Suhu Alat Handlers ---
+
+// TambahSuhuAlatHandler mencatat bacaan suhu manual (pagi dan sore) lalu memeriksa ekskursi
+func TambahSuhuAlatHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idAlat, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID alat tidak valid"})
+			return
+		}
+		var payload models.TambahSuhuAlatPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Waktu dan suhu wajib diisi."})
+			return
+		}
+		waktu, err := time.ParseInLocation("2006-01-02T15:04", payload.Waktu, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format waktu salah (YYYY-MM-DDTHH:MM)."})
+			return
+		}
+		if waktu.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Waktu bacaan tidak boleh di masa depan."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for suhu alat: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulai transaksi."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`INSERT INTO suhu_alat (id_alat, waktu, suhu, sumber, id_kader, catatan) VALUES ($1, $2, $3, 'manual', $4, $5) RETURNING id`,
+			idAlat, waktu, *payload.Suhu, kaderId, payload.Catatan).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR inserting suhu alat %d: %v", idAlat, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok {
+				switch pgErr.ConstraintName {
+				case "suhu_alat_id_alat_waktu_key":
+					c.JSON(http.StatusConflict, gin.H{"error": "Bacaan suhu pada waktu tersebut sudah tercatat."})
+					return
+				case "suhu_alat_id_alat_fkey":
+					c.JSON(http.StatusNotFound, gin.H{"error": "Alat rantai dingin tidak ditemukan."})
+					return
+				}
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		ekskursiBaru, err := perbaruiEkskursi(ctx, tx, idAlat, waktu)
+		if err != nil {
+			log.Printf("ERROR detecting ekskursi for alat %d: %v", idAlat, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa ekskursi suhu."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing suhu alat: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Suhu berhasil dicatat!", "id": id, "ekskursi_baru": ekskursiBaru})
+	}
+}
+
+// UnggahSuhuAlatHandler menyimpan bacaan dari berkas CSV data logger (field "file") lalu memeriksa ekskursi.
+// Bacaan dengan waktu yang sudah tercatat dilewati.
+func UnggahSuhuAlatHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idAlat, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID alat tidak valid"})
+			return
+		}
+		berkas, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Berkas CSV data logger wajib diunggah (field file)."})
+			return
+		}
+		f, err := berkas.Open()
+		if err != nil {
+			log.Printf("ERROR opening uploaded suhu file: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membaca berkas."})
+			return
+		}
+		defer f.Close()
+
+		bacaan, galat, err := rantaidingin.BacaCSV(f, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Berkas bukan CSV yang valid: " + err.Error()})
+			return
+		}
+		if len(bacaan) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tidak ada bacaan suhu yang dapat dibaca.", "galat": galat})
+			return
+		}
+		if len(bacaan) > maksBacaanUnggah {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Berkas berisi lebih dari %d bacaan; unggah per periode yang lebih pendek.", maksBacaanUnggah)})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for unggah suhu: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulai transaksi."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var ada bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM alat_rantai_dingin WHERE id = $1)", idAlat).Scan(&ada); err != nil {
+			log.Printf("ERROR checking alat rantai dingin %d: %v", idAlat, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		if !ada {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Alat rantai dingin tidak ditemukan."})
+			return
+		}
+
+		hasil := models.HasilUnggahSuhu{Galat: galat}
+		if hasil.Galat == nil {
+			hasil.Galat = make([]string, 0)
+		}
+		sejak := bacaan[0].Waktu
+		for _, b := range bacaan {
+			tag, err := tx.Exec(ctx,
+				`INSERT INTO suhu_alat (id_alat, waktu, suhu, sumber, id_kader) VALUES ($1, $2, $3, 'logger', $4)
+                ON CONFLICT ON CONSTRAINT suhu_alat_id_alat_waktu_key DO NOTHING`,
+				idAlat, b.Waktu, b.Suhu, kaderId)
+			if err != nil {
+				log.Printf("ERROR inserting uploaded suhu for alat %d: %v", idAlat, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan bacaan suhu."})
+				return
+			}
+			if tag.RowsAffected() == 0 {
+				hasil.Duplikat++
+				continue
+			}
+			hasil.Tersimpan++
+			if b.Waktu.Before(sejak) {
+				sejak = b.Waktu
+			}
+		}
+		if hasil.Tersimpan > 0 {
+			hasil.EkskursiBaru, err = perbaruiEkskursi(ctx, tx, idAlat, sejak)
+			if err != nil {
+				log.Printf("ERROR detecting ekskursi for alat %d: %v", idAlat, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa ekskursi suhu."})
+				return
+			}
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing unggah suhu: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi."})
+			return
+		}
+		c.JSON(http.StatusCreated, hasil)
+	}
+}
+
+// GetSuhuAlatHandler menampilkan bacaan suhu alat, terbaru lebih dulu (maksimal 1000).
+// Query: start, end (YYYY-MM-DD, opsional).
+func GetSuhuAlatHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		idAlat, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID alat tidak valid"})
+			return
+		}
+
+		query := `SELECT s.id, s.id_alat, s.waktu, s.suhu, s.sumber, s.id_kader, k.nama_lengkap, s.catatan, s.created_at
+            FROM suhu_alat s
+            LEFT JOIN kader k ON s.id_kader = k.id
+            WHERE s.id_alat = $1`
+		args := []interface{}{idAlat}
+		if start := c.Query("start"); start != "" {
+			t, err := time.ParseInLocation("2006-01-02", start, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal tidak valid (YYYY-MM-DD)"})
+				return
+			}
+			args = append(args, t)
+			query += fmt.Sprintf(" AND s.waktu >= $%d", len(args))
+		}
+		if end := c.Query("end"); end != "" {
+			t, err := time.ParseInLocation("2006-01-02", end, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal tidak valid (YYYY-MM-DD)"})
+				return
+			}
+			args = append(args, t.AddDate(0, 0, 1))
+			query += fmt.Sprintf(" AND s.waktu < $%d", len(args))
+		}
+		query += " ORDER BY s.waktu DESC LIMIT 1000"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying suhu alat %d: %v", idAlat, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.SuhuAlat, 0)
+		for rows.Next() {
+			var s models.SuhuAlat
+			if err := rows.Scan(&s.ID, &s.IdAlat, &s.Waktu, &s.Suhu, &s.Sumber, &s.IdKader, &s.NamaKader, &s.Catatan, &s.CreatedAt); err != nil {
+				log.Printf("ERROR scanning suhu alat: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, s)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating suhu alat: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// --- Ekskursi Suhu Handlers ---
+
+// GetEkskursiSuhuHandler menampilkan ekskursi suhu, terbaru lebih dulu.
+// Query: status (baru, ditinjau), id_alat, id_posyandu (opsional), semua=true untuk menyertakan ekskursi yang sudah digantikan.
+func GetEkskursiSuhuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := ekskursiSuhuSelect + " WHERE TRUE"
+		if c.Query("semua") != "true" {
+			query += " AND e.digantikan_at IS NULL"
+		}
+		var args []interface{}
+		if status := c.Query("status"); status != "" {
+			args = append(args, status)
+			query += fmt.Sprintf(" AND e.status = $%d", len(args))
+		}
+		for _, filter := range []struct{ param, kolom string }{{"id_alat", "e.id_alat"}, {"id_posyandu", "a.id_posyandu"}} {
+			nilai := c.Query(filter.param)
+			if nilai == "" {
+				continue
+			}
+			id, err := strconv.Atoi(nilai)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": filter.param + " tidak valid"})
+				return
+			}
+			args = append(args, id)
+			query += fmt.Sprintf(" AND %s = $%d", filter.kolom, len(args))
+		}
+		query += " ORDER BY e.mulai DESC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying ekskursi suhu: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.EkskursiSuhu, 0)
+		for rows.Next() {
+			e, err := scanEkskursiSuhu(rows)
+			if err != nil {
+				log.Printf("ERROR scanning ekskursi suhu: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, e)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating ekskursi suhu: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// GetEkskursiSuhuByIdHandler menampilkan satu ekskursi beserta batch vaksin yang tersimpan saat itu,
+// stok sekarang, dan jumlah dosis dari batch tersebut yang sudah diberikan sejak ekskursi dimulai
+func GetEkskursiSuhuByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID ekskursi tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		e, err := scanEkskursiSuhu(dbpool.QueryRow(ctx, ekskursiSuhuSelect+" WHERE e.id = $1", id))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Ekskursi suhu tidak ditemukan."})
+				return
+			}
+			log.Printf("ERROR fetching ekskursi suhu %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+
+		rows, err := dbpool.Query(ctx,
+			`SELECT b.id, b.nama_vaksin, b.nomor_batch, b.tanggal_kedaluwarsa, b.status_vvm, eb.stok_dosis, b.stok_dosis,
+                (SELECT COUNT(*) FROM riwayat_imunisasi r WHERE r.id_batch_vaksin = b.id AND r.tanggal_imunisasi >= $2::date)
+            FROM ekskursi_batch_vaksin eb
+            JOIN batch_vaksin b ON eb.id_batch_vaksin = b.id
+            WHERE eb.id_ekskursi = $1
+            ORDER BY b.nama_vaksin ASC, b.tanggal_kedaluwarsa ASC`, id, e.Mulai)
+		if err != nil {
+			log.Printf("ERROR querying batch for ekskursi %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		e.Batch = make([]models.BatchEkskursi, 0)
+		for rows.Next() {
+			var b models.BatchEkskursi
+			if err := rows.Scan(&b.IdBatchVaksin, &b.NamaVaksin, &b.NomorBatch, &b.TanggalKedaluwarsa, &b.StatusVVM, &b.StokDosis, &b.StokDosisSekarang, &b.DosisDiberikan); err != nil {
+				log.Printf("ERROR scanning batch ekskursi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			e.Batch = append(e.Batch, b)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating batch ekskursi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, e)
+	}
+}
+
+// TinjauEkskursiSuhuHandler mencatat tindak lanjut ekskursi (mis. uji kocok, batch ditahan atau dibuang).
+// Penyesuaian stok dan VVM dicatat terpisah melalui transaksi batch vaksin.
+func TinjauEkskursiSuhuHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID ekskursi tidak valid"})
+			return
+		}
+		var payload models.TinjauEkskursiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tindak lanjut wajib diisi."})
+			return
+		}
+
+		ctx := context.Background()
+		tag, err := dbpool.Exec(ctx,
+			`UPDATE ekskursi_suhu SET status = 'ditinjau', tindak_lanjut = $1, id_kader_peninjau = $2, ditinjau_at = NOW(), updated_at = NOW()
+            WHERE id = $3 AND digantikan_at IS NULL`,
+			payload.TindakLanjut, kaderId, id)
+		if err != nil {
+			log.Printf("ERROR reviewing ekskursi suhu %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			var ada bool
+			if err := dbpool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM ekskursi_suhu WHERE id = $1)", id).Scan(&ada); err != nil {
+				log.Printf("ERROR checking ekskursi suhu %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+				return
+			}
+			if ada {
+				c.JSON(http.StatusConflict, gin.H{"error": "Ekskursi ini sudah digantikan dan tidak lagi terdeteksi; tinjau ekskursi penggantinya bila ada."})
+			} else {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Ekskursi suhu tidak ditemukan."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Ekskursi suhu berhasil ditinjau."})
+	}
+}
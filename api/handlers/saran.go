@@ -0,0 +1,350 @@
+// handlers/saran.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/growth"
+	"github.com/nadhifhafizp/api/imunisasi"
+	"github.com/nadhifhafizp/api/models"
+)
+
+const aturanSaranSelect = `SELECT id, nama, prioritas, aktif, usia_min_bulan, usia_max_bulan, indikator, status, kms_status, kms_2t,
+        imunisasi_tertunggak, status_asi, teks_saran, created_at, updated_at FROM aturan_saran`
+
+const pesanAturanSaranTidakDitemukan = "Aturan saran tidak ditemukan."
+
+// kondisiSaran adalah keadaan anak pada satu pemeriksaan yang dicocokkan dengan aturan saran
+type kondisiSaran struct {
+	namaAnak            string
+	usiaBulan           int                // Bulan penuh, umur koreksi untuk bayi prematur
+	status              map[string]*string // Kategori per indikator: bbu, tbu, bbtb, imtu, lku
+	kmsStatus           *string
+	kms2T               bool
+	imunisasiTertunggak []string
+	statusAsi           *string
+}
+
+// cocok memeriksa apakah semua kondisi aturan yang terisi terpenuhi
+func (k kondisiSaran) cocok(a models.AturanSaran) bool {
+	if a.UsiaMinBulan != nil && k.usiaBulan < *a.UsiaMinBulan {
+		return false
+	}
+	if a.UsiaMaxBulan != nil && k.usiaBulan >= *a.UsiaMaxBulan {
+		return false
+	}
+	if a.Indikator != nil {
+		status := k.status[*a.Indikator]
+		if status == nil || (a.Status != nil && !slices.Contains(a.Status, *status)) {
+			return false
+		}
+	}
+	if a.KmsStatus != nil && (k.kmsStatus == nil || !slices.Contains(a.KmsStatus, *k.kmsStatus)) {
+		return false
+	}
+	if a.Kms2T != nil && *a.Kms2T != k.kms2T {
+		return false
+	}
+	if a.ImunisasiTertunggak != nil && *a.ImunisasiTertunggak != (len(k.imunisasiTertunggak) > 0) {
+		return false
+	}
+	if a.StatusAsi != nil && (k.statusAsi == nil || !slices.Contains(a.StatusAsi, *k.statusAsi)) {
+		return false
+	}
+	return true
+}
+
+// teks mengisi placeholder pada teks saran
+func (k kondisiSaran) teks(a models.AturanSaran) string {
+	return strings.NewReplacer(
+		"{nama_anak}", k.namaAnak,
+		"{usia_bulan}", strconv.Itoa(k.usiaBulan),
+		"{imunisasi_tertunggak}", strings.Join(k.imunisasiTertunggak, ", "),
+	).Replace(a.TeksSaran)
+}
+
+// imunisasiTertunggak mengembalikan nama antigen yang sudah terlambat pada tanggal pemeriksaan
+func imunisasiTertunggak(ctx context.Context, dbpool *pgxpool.Pool, idAnak int, tanggalLahir, tanggal time.Time) ([]string, error) {
+	jadwal, _, err := jadwalImunisasiAnak(ctx, dbpool, idAnak, tanggalLahir, tanggal)
+	if err != nil {
+		return nil, err
+	}
+	daftar := make([]string, 0)
+	for _, j := range jadwal {
+		if j.Status == imunisasi.Terlambat {
+			daftar = append(daftar, j.Nama)
+		}
+	}
+	return daftar, nil
+}
+
+// susunSaran membentuk usulan saran konseling untuk satu perkembangan dari aturan saran yang aktif.
+// Teks setiap aturan yang cocok digabung berurutan menurut prioritas.
+func susunSaran(ctx context.Context, dbpool *pgxpool.Pool, idPerkembangan int) (models.SaranUsulan, error) {
+	usulan := models.SaranUsulan{IdAturan: make([]int, 0)}
+	var anak dataAnakGizi
+	var idAnak int
+	var tanggal time.Time
+	var k kondisiSaran
+	var bbu, tbu, bbtb, imtu, lku *string
+	err := dbpool.QueryRow(ctx,
+		`SELECT p.id_anak, p.tanggal_pemeriksaan, p.status_bbu, p.status_tbu, p.status_bbtb, p.status_imtu, p.status_lku, p.kms_status, p.kms_2t, p.status_asi,
+            a.nama_anak, a.tanggal_lahir, a.jenis_kelamin, a.usia_kehamilan_lahir_minggu
+        FROM perkembangan p JOIN anak a ON p.id_anak = a.id WHERE p.id = $1`, idPerkembangan).
+		Scan(&idAnak, &tanggal, &bbu, &tbu, &bbtb, &imtu, &lku, &k.kmsStatus, &k.kms2T, &k.statusAsi,
+			&k.namaAnak, &anak.TanggalLahir, &anak.JenisKelamin, &anak.UsiaKehamilanLahirMinggu)
+	if err != nil {
+		return usulan, err
+	}
+	k.status = map[string]*string{"bbu": bbu, "tbu": tbu, "bbtb": bbtb, "imtu": imtu, "lku": lku}
+	k.usiaBulan = int(math.Floor(float64(anak.usiaPenilaian(tanggal)) / growth.HariPerBulan))
+
+	k.imunisasiTertunggak, err = imunisasiTertunggak(ctx, dbpool, idAnak, anak.TanggalLahir, tanggal)
+	if err != nil {
+		return usulan, err
+	}
+	usulan.ImunisasiTertunggak = k.imunisasiTertunggak
+
+	rows, err := dbpool.Query(ctx, aturanSaranSelect+" WHERE aktif ORDER BY prioritas ASC, id ASC")
+	if err != nil {
+		return usulan, err
+	}
+	defer rows.Close()
+
+	var teks []string
+	for rows.Next() {
+		a, err := scanAturanSaran(rows)
+		if err != nil {
+			return usulan, err
+		}
+		if k.cocok(a) {
+			teks = append(teks, k.teks(a))
+			usulan.IdAturan = append(usulan.IdAturan, a.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return usulan, err
+	}
+	usulan.Saran = strings.Join(teks, "\n")
+	return usulan, nil
+}
+
+// scanAturanSaran memindai satu baris hasil aturanSaranSelect
+func scanAturanSaran(row interface{ Scan(...any) error }) (models.AturanSaran, error) {
+	var a models.AturanSaran
+	err := row.Scan(&a.ID, &a.Nama, &a.Prioritas, &a.Aktif, &a.UsiaMinBulan, &a.UsiaMaxBulan, &a.Indikator, &a.Status, &a.KmsStatus, &a.Kms2T,
+		&a.ImunisasiTertunggak, &a.StatusAsi, &a.TeksSaran, &a.CreatedAt, &a.UpdatedAt)
+	return a, err
+}
+
+// nilaiAturanSaran menormalkan payload: larik kosong berarti tidak membatasi dan disimpan sebagai NULL
+func nilaiAturanSaran(payload *models.AturanSaranPayload) (prioritas int, aktif bool) {
+	prioritas, aktif = 100, true
+	if payload.Prioritas != nil {
+		prioritas = *payload.Prioritas
+	}
+	if payload.Aktif != nil {
+		aktif = *payload.Aktif
+	}
+	for _, larik := range []*[]string{&payload.Status, &payload.KmsStatus, &payload.StatusAsi} {
+		if len(*larik) == 0 {
+			*larik = nil
+		}
+	}
+	return prioritas, aktif
+}
+
+// tanggapiGalatAturanSaran menerjemahkan pelanggaran constraint aturan_saran menjadi pesan yang jelas
+func tanggapiGalatAturanSaran(c *gin.Context, err error) bool {
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23514" {
+		switch pgErr.ConstraintName {
+		case "aturan_saran_status_indikator_check":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Kondisi status memerlukan indikator."})
+			return true
+		case "aturan_saran_usia_check":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "usia_min_bulan harus lebih kecil dari usia_max_bulan."})
+			return true
+		}
+	}
+	return false
+}
+
+// GetAturanSaranHandler menangani pengambilan daftar aturan saran
+func GetAturanSaranHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := aturanSaranSelect
+		if c.Query("aktif") == "true" {
+			query += " WHERE aktif"
+		}
+		rows, err := dbpool.Query(context.Background(), query+" ORDER BY prioritas ASC, id ASC")
+		if err != nil {
+			log.Printf("ERROR querying aturan saran: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil aturan saran."})
+			return
+		}
+		defer rows.Close()
+
+		daftarAturan := make([]models.AturanSaran, 0)
+		for rows.Next() {
+			a, err := scanAturanSaran(rows)
+			if err != nil {
+				log.Printf("ERROR scanning aturan saran: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai aturan saran."})
+				return
+			}
+			daftarAturan = append(daftarAturan, a)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating aturan saran: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses aturan saran."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarAturan)
+	}
+}
+
+// GetAturanSaranByIdHandler menangani pengambilan satu aturan saran
+func GetAturanSaranByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID aturan saran tidak valid"})
+			return
+		}
+		a, err := scanAturanSaran(dbpool.QueryRow(context.Background(), aturanSaranSelect+" WHERE id = $1", id))
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": pesanAturanSaranTidakDitemukan})
+			} else {
+				log.Printf("ERROR fetching aturan saran ID %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil aturan saran."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, a)
+	}
+}
+
+// TambahAturanSaranHandler menangani penambahan aturan saran (khusus admin)
+func TambahAturanSaranHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload models.AturanSaranPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			log.Printf("WARNING: invalid aturan saran payload: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+		prioritas, aktif := nilaiAturanSaran(&payload)
+
+		var id int
+		err := dbpool.QueryRow(context.Background(),
+			`INSERT INTO aturan_saran (nama, prioritas, aktif, usia_min_bulan, usia_max_bulan, indikator, status, kms_status, kms_2t, imunisasi_tertunggak, status_asi, teks_saran)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+			payload.Nama, prioritas, aktif, payload.UsiaMinBulan, payload.UsiaMaxBulan, payload.Indikator, payload.Status, payload.KmsStatus,
+			payload.Kms2T, payload.ImunisasiTertunggak, payload.StatusAsi, payload.TeksSaran).Scan(&id)
+		if err != nil {
+			if tanggapiGalatAturanSaran(c, err) {
+				return
+			}
+			log.Printf("ERROR inserting aturan saran: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan aturan saran."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Aturan saran berhasil ditambahkan!", "id": id})
+	}
+}
+
+// UpdateAturanSaranHandler menangani perubahan aturan saran (khusus admin)
+func UpdateAturanSaranHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID aturan saran tidak valid"})
+			return
+		}
+		var payload models.AturanSaranPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			log.Printf("WARNING: invalid aturan saran payload: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+		prioritas, aktif := nilaiAturanSaran(&payload)
+
+		commandTag, err := dbpool.Exec(context.Background(),
+			`UPDATE aturan_saran SET nama = $1, prioritas = $2, aktif = $3, usia_min_bulan = $4, usia_max_bulan = $5, indikator = $6, status = $7,
+                kms_status = $8, kms_2t = $9, imunisasi_tertunggak = $10, status_asi = $11, teks_saran = $12, updated_at = NOW() WHERE id = $13`,
+			payload.Nama, prioritas, aktif, payload.UsiaMinBulan, payload.UsiaMaxBulan, payload.Indikator, payload.Status, payload.KmsStatus,
+			payload.Kms2T, payload.ImunisasiTertunggak, payload.StatusAsi, payload.TeksSaran, id)
+		if err != nil {
+			if tanggapiGalatAturanSaran(c, err) {
+				return
+			}
+			log.Printf("ERROR updating aturan saran ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui aturan saran."})
+			return
+		}
+		if commandTag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": pesanAturanSaranTidakDitemukan})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Aturan saran berhasil diperbarui!"})
+	}
+}
+
+// DeleteAturanSaranHandler menangani penghapusan aturan saran (khusus admin)
+func DeleteAturanSaranHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID aturan saran tidak valid"})
+			return
+		}
+		commandTag, err := dbpool.Exec(context.Background(), "DELETE FROM aturan_saran WHERE id = $1", id)
+		if err != nil {
+			log.Printf("ERROR deleting aturan saran ID %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus aturan saran."})
+			return
+		}
+		if commandTag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": pesanAturanSaranTidakDitemukan})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Aturan saran berhasil dihapus!"})
+	}
+}
+
+// GetSaranPerkembanganHandler menangani pembentukan ulang usulan saran untuk data perkembangan yang sudah ada
+func GetSaranPerkembanganHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID perkembangan tidak valid"})
+			return
+		}
+		usulan, err := susunSaran(context.Background(), dbpool, id)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data perkembangan tidak ditemukan."})
+			} else {
+				log.Printf("ERROR building saran for perkembangan %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun saran."})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, usulan)
+	}
+}
@@ -0,0 +1,95 @@
+// handlers/sasaran_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// GetSasaranImunisasiHandler menampilkan target sasaran imunisasi per posyandu untuk satu tahun.
+// Query: tahun=YYYY (default tahun berjalan).
+func GetSasaranImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tahun, err := strconv.Atoi(c.DefaultQuery("tahun", strconv.Itoa(time.Now().Year())))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tahun tidak valid (YYYY)"})
+			return
+		}
+
+		rows, err := dbpool.Query(context.Background(),
+			`SELECT s.id, s.tahun, s.id_posyandu, ps.nama, s.sasaran_bayi, s.sasaran_baduta, s.id_kader, s.created_at, s.updated_at
+            FROM sasaran_imunisasi s
+            JOIN posyandu ps ON s.id_posyandu = ps.id
+            WHERE s.tahun = $1
+            ORDER BY ps.nama ASC`, tahun)
+		if err != nil {
+			log.Printf("ERROR querying sasaran imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.SasaranImunisasi, 0)
+		for rows.Next() {
+			var s models.SasaranImunisasi
+			if err := rows.Scan(&s.ID, &s.Tahun, &s.IdPosyandu, &s.NamaPosyandu, &s.SasaranBayi, &s.SasaranBaduta, &s.IdKader, &s.CreatedAt, &s.UpdatedAt); err != nil {
+				log.Printf("ERROR scanning sasaran imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, s)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating sasaran imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// SimpanSasaranImunisasiHandler mengatur target sasaran imunisasi posyandu untuk satu tahun.
+// Target yang sudah ada untuk tahun dan posyandu yang sama diperbarui.
+func SimpanSasaranImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.SimpanSasaranImunisasiPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tahun dan posyandu wajib diisi; sasaran tidak boleh negatif."})
+			return
+		}
+
+		var id int
+		err := dbpool.QueryRow(context.Background(),
+			`INSERT INTO sasaran_imunisasi (tahun, id_posyandu, sasaran_bayi, sasaran_baduta, id_kader)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (tahun, id_posyandu) DO UPDATE
+            SET sasaran_bayi = EXCLUDED.sasaran_bayi, sasaran_baduta = EXCLUDED.sasaran_baduta, id_kader = EXCLUDED.id_kader, updated_at = NOW()
+            RETURNING id`,
+			payload.Tahun, payload.IdPosyandu, payload.SasaranBayi, payload.SasaranBaduta, kaderId).Scan(&id)
+		if err != nil {
+			log.Printf("ERROR saving sasaran imunisasi: %v", err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == "sasaran_imunisasi_id_posyandu_fkey" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Posyandu tidak ditemukan."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Sasaran imunisasi berhasil disimpan!", "id": id})
+	}
+}
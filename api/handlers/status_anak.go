@@ -0,0 +1,168 @@
+// handlers/status_anak.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// kondisiAnakAktif adalah filter default daftar & laporan (alias tabel anak harus "a")
+const kondisiAnakAktif = "a.status = 'aktif'"
+
+// sertakanNonaktif membaca query include_inactive=true untuk menampilkan anak yang pindah, meninggal, atau lulus
+func sertakanNonaktif(c *gin.Context) bool {
+	include, err := strconv.ParseBool(c.Query("include_inactive"))
+	return err == nil && include
+}
+
+// UbahStatusAnakHandler menangani perubahan status anak dan mencatatnya ke riwayat
+func UbahStatusAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+
+		var payload models.UbahStatusAnakPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status atau tanggal berlaku tidak valid."})
+			return
+		}
+
+		tglBerlaku, err := time.Parse("2006-01-02", payload.TanggalBerlaku)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal berlaku salah (YYYY-MM-DD)."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for status anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status anak."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var statusLama string
+		var tglLahir time.Time
+		err = tx.QueryRow(ctx, "SELECT status, tanggal_lahir FROM anak WHERE id = $1 FOR UPDATE", id).Scan(&statusLama, &tglLahir)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Data anak tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying status anak %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data anak."})
+			}
+			return
+		}
+		if statusLama == payload.Status {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status anak sudah " + statusLama + "."})
+			return
+		}
+		if tglBerlaku.Before(tglLahir) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal berlaku tidak boleh sebelum tanggal lahir."})
+			return
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE anak SET status = $1, status_tanggal = $2, status_alasan = $3, updated_at = NOW() WHERE id = $4`,
+			payload.Status, tglBerlaku, payload.Alasan, id)
+		if err != nil {
+			log.Printf("ERROR updating status anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status anak."})
+			return
+		}
+
+		// Kasus gizi terbuka anak yang pindah atau meninggal ditutup agar tidak terus muncul sebagai tindak lanjut terlambat
+		var kasusDitutup int64
+		if payload.Status == "pindah" || payload.Status == "meninggal" {
+			tag, err := tx.Exec(ctx,
+				`UPDATE kasus_gizi SET status = 'ditutup', hasil_penutupan = $1, tanggal_penutupan = $2, catatan_penutupan = $3, updated_at = NOW()
+                WHERE id_anak = $4 AND status = 'terbuka'`,
+				payload.Status, tglBerlaku, "Ditutup otomatis karena status anak berubah menjadi "+payload.Status+".", id)
+			if err != nil {
+				log.Printf("ERROR closing kasus_gizi for anak %d: %v", id, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status anak."})
+				return
+			}
+			kasusDitutup = tag.RowsAffected()
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO riwayat_status_anak (id_anak, status_lama, status_baru, tanggal_berlaku, alasan, id_kader) VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, statusLama, payload.Status, tglBerlaku, payload.Alasan, kaderId)
+		if err != nil {
+			log.Printf("ERROR inserting riwayat status anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencatat riwayat status."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing status anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status anak."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Status anak berhasil diubah!", "kasus_gizi_ditutup": kasusDitutup})
+	}
+}
+
+// GetRiwayatStatusAnakHandler menangani pengambilan riwayat perubahan status seorang anak
+func GetRiwayatStatusAnakHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID anak tidak valid"})
+			return
+		}
+
+		daftarRiwayat := make([]models.RiwayatStatusAnak, 0)
+		query := `SELECT r.id, r.id_anak, r.status_lama, r.status_baru, r.tanggal_berlaku, r.alasan, r.id_kader, k.nama_lengkap AS nama_kader, r.created_at
+            FROM riwayat_status_anak r
+            LEFT JOIN kader k ON r.id_kader = k.id
+            WHERE r.id_anak = $1
+            ORDER BY r.tanggal_berlaku DESC, r.id DESC`
+
+		rows, err := dbpool.Query(context.Background(), query, id)
+		if err != nil {
+			log.Printf("ERROR querying riwayat status anak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat status."})
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var r models.RiwayatStatusAnak
+			if err := rows.Scan(&r.ID, &r.IdAnak, &r.StatusLama, &r.StatusBaru, &r.TanggalBerlaku, &r.Alasan, &r.IdKader, &r.NamaKader, &r.CreatedAt); err != nil {
+				log.Printf("ERROR scanning riwayat status anak: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftarRiwayat = append(daftarRiwayat, r)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating riwayat status anak: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftarRiwayat)
+	}
+}
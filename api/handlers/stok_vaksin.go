@@ -0,0 +1,486 @@
+// handlers/stok_vaksin.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// hariHampirKedaluwarsa adalah batas sisa hari sebelum kedaluwarsa yang memicu peringatan
+const hariHampirKedaluwarsa = 30
+
+const batchVaksinSelect = `
+    SELECT b.id, b.id_posyandu, ps.nama, b.nama_vaksin, b.nomor_batch, b.produsen, b.tanggal_kedaluwarsa,
+           b.dosis_per_vial, b.status_vvm, b.stok_dosis, b.created_at, b.updated_at
+    FROM batch_vaksin b
+    LEFT JOIN posyandu ps ON b.id_posyandu = ps.id`
+
+// vvmLayak menandakan tahap VVM yang masih boleh dipakai (A dan B)
+func vvmLayak(status string) bool {
+	return status == "A" || status == "B"
+}
+
+// statusKedaluwarsa mengembalikan sisa hari dan kategori kedaluwarsa batch pada tanggal acuan.
+// Batch masih boleh dipakai pada tanggal kedaluwarsanya.
+func statusKedaluwarsa(tanggalKedaluwarsa, acuan time.Time) (int, string) {
+	sisa := int(tanggalKedaluwarsa.Sub(acuan).Hours() / 24)
+	switch {
+	case sisa < 0:
+		return sisa, "kedaluwarsa"
+	case sisa <= hariHampirKedaluwarsa:
+		return sisa, "hampir_kedaluwarsa"
+	default:
+		return sisa, "layak"
+	}
+}
+
+func scanBatchVaksin(row pgx.Row, b *models.BatchVaksin) error {
+	err := row.Scan(&b.ID, &b.IdPosyandu, &b.NamaPosyandu, &b.NamaVaksin, &b.NomorBatch, &b.Produsen, &b.TanggalKedaluwarsa,
+		&b.DosisPerVial, &b.StatusVVM, &b.StokDosis, &b.CreatedAt, &b.UpdatedAt)
+	if err == nil {
+		b.HariMenujuKedaluwarsa, b.StatusKedaluwarsa = statusKedaluwarsa(b.TanggalKedaluwarsa, tanggalHariIni())
+	}
+	return err
+}
+
+// pakaiBatchVaksin memeriksa batch yang dipilih saat mencatat satu dosis lalu mengurangi stoknya satu dosis.
+// Batch kedaluwarsa, VVM C/D, vaksin yang tidak sesuai, stok habis dan batch milik posyandu lain (dibanding
+// posyandu yang tercatat pada riwayat imunisasi) ditolak; batch yang hampir kedaluwarsa atau lokasinya
+// tidak dapat dicocokkan (gudang puskesmas, atau pencatat tanpa posyandu) menghasilkan peringatan.
+// ok bernilai false bila respons galat sudah dikirim.
+func pakaiBatchVaksin(ctx context.Context, c *gin.Context, tx pgx.Tx, idBatch, idMaster, idRiwayat, kaderId int, tanggal time.Time) (peringatan *string, ok bool) {
+	var namaVaksin, nomorBatch, statusVVM, vaksinMaster string
+	var tanggalKedaluwarsa time.Time
+	var stok int
+	var posyanduBatch, posyanduRiwayat *int
+	var namaPosyanduBatch *string
+	err := tx.QueryRow(ctx,
+		`SELECT b.nama_vaksin, b.nomor_batch, b.tanggal_kedaluwarsa, b.status_vvm, b.stok_dosis, b.id_posyandu, ps.nama,
+                (SELECT COALESCE(m.seri, m.nama_imunisasi) FROM master_imunisasi m WHERE m.id = $2),
+                (SELECT r.id_posyandu FROM riwayat_imunisasi r WHERE r.id = $3)
+            FROM batch_vaksin b LEFT JOIN posyandu ps ON b.id_posyandu = ps.id WHERE b.id = $1 FOR UPDATE OF b`, idBatch, idMaster, idRiwayat).
+		Scan(&namaVaksin, &nomorBatch, &tanggalKedaluwarsa, &statusVVM, &stok, &posyanduBatch, &namaPosyanduBatch, &vaksinMaster, &posyanduRiwayat)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Batch vaksin tidak ditemukan."})
+		} else {
+			log.Printf("ERROR querying batch_vaksin %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa batch vaksin."})
+		}
+		return nil, false
+	}
+
+	if !strings.EqualFold(namaVaksin, vaksinMaster) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch %s berisi vaksin %s, bukan %s.", nomorBatch, namaVaksin, vaksinMaster)})
+		return nil, false
+	}
+	sisaHari, status := statusKedaluwarsa(tanggalKedaluwarsa, tanggal)
+	if status == "kedaluwarsa" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch %s sudah kedaluwarsa sejak %s dan tidak boleh dipakai.", nomorBatch, tanggalKedaluwarsa.Format("2006-01-02"))})
+		return nil, false
+	}
+	if !vvmLayak(statusVVM) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("VVM batch %s berada pada tahap %s dan tidak boleh dipakai.", nomorBatch, statusVVM)})
+		return nil, false
+	}
+	if stok < 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Stok batch %s sudah habis.", nomorBatch)})
+		return nil, false
+	}
+	var daftarPeringatan []string
+	switch {
+	case posyanduBatch != nil && posyanduRiwayat != nil && *posyanduBatch != *posyanduRiwayat:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch %s tersimpan di posyandu %s, bukan di posyandu pencatat.", nomorBatch, *namaPosyanduBatch)})
+		return nil, false
+	case posyanduBatch == nil && posyanduRiwayat != nil:
+		daftarPeringatan = append(daftarPeringatan, fmt.Sprintf("Batch %s tercatat di gudang puskesmas, bukan di posyandu pencatat; catat distribusinya ke posyandu.", nomorBatch))
+	case posyanduBatch != nil && posyanduRiwayat == nil:
+		daftarPeringatan = append(daftarPeringatan, fmt.Sprintf("Batch %s tersimpan di posyandu %s, sedangkan pencatat tidak terdaftar di posyandu mana pun.", nomorBatch, *namaPosyanduBatch))
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE batch_vaksin SET stok_dosis = stok_dosis - 1, updated_at = NOW() WHERE id = $1", idBatch); err != nil {
+		log.Printf("ERROR decrementing stok batch_vaksin %d: %v", idBatch, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui stok vaksin."})
+		return nil, false
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO transaksi_vaksin (id_batch, jenis, tanggal, jumlah_dosis, id_riwayat_imunisasi, id_kader) VALUES ($1, 'pemakaian', $2, -1, $3, $4)`,
+		idBatch, tanggal, idRiwayat, kaderId)
+	if err != nil {
+		log.Printf("ERROR inserting pemakaian batch_vaksin %d: %v", idBatch, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui stok vaksin."})
+		return nil, false
+	}
+
+	if status == "hampir_kedaluwarsa" {
+		daftarPeringatan = append(daftarPeringatan, fmt.Sprintf("Batch %s kedaluwarsa dalam %d hari (%s); gunakan lebih dulu.", nomorBatch, sisaHari, tanggalKedaluwarsa.Format("2006-01-02")))
+	}
+	if len(daftarPeringatan) > 0 {
+		p := strings.Join(daftarPeringatan, " ")
+		peringatan = &p
+	}
+	return peringatan, true
+}
+
+// kembalikanBatchVaksin membatalkan pemakaian stok oleh sebuah riwayat imunisasi
+func kembalikanBatchVaksin(ctx context.Context, tx pgx.Tx, idRiwayat int) error {
+	_, err := tx.Exec(ctx,
+		`WITH dihapus AS (
+                DELETE FROM transaksi_vaksin WHERE id_riwayat_imunisasi = $1 AND jenis = 'pemakaian' RETURNING id_batch, jumlah_dosis
+            )
+            UPDATE batch_vaksin b SET stok_dosis = b.stok_dosis - d.jumlah_dosis, updated_at = NOW()
+            FROM dihapus d WHERE b.id = d.id_batch`, idRiwayat)
+	return err
+}
+
+// TambahPenerimaanVaksinHandler mencatat penerimaan vaksin. Nomor batch yang sudah ada
+// di lokasi yang sama ditambah stoknya, selain itu batch baru dibuat.
+func TambahPenerimaanVaksinHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.PenerimaanVaksinPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+		tanggal, err := time.Parse("2006-01-02", payload.Tanggal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+			return
+		}
+		tanggalKedaluwarsa, err := time.Parse("2006-01-02", payload.TanggalKedaluwarsa)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal kedaluwarsa salah (YYYY-MM-DD)."})
+			return
+		}
+		if tanggalKedaluwarsa.Before(tanggal) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Vaksin yang sudah kedaluwarsa tidak dapat diterima."})
+			return
+		}
+		statusVVM := payload.StatusVVM
+		if statusVVM == "" {
+			statusVVM = "A"
+		}
+		nomorBatch := strings.TrimSpace(payload.NomorBatch)
+		ctx := context.Background()
+
+		// Nama vaksin mengikuti penulisan seri (atau nama imunisasi dosis tunggal) pada master
+		var namaVaksin string
+		err = dbpool.QueryRow(ctx,
+			`SELECT COALESCE(seri, nama_imunisasi) FROM master_imunisasi
+            WHERE LOWER(COALESCE(seri, nama_imunisasi)) = LOWER($1) LIMIT 1`, strings.TrimSpace(payload.NamaVaksin)).Scan(&namaVaksin)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Nama vaksin tidak dikenal. Gunakan seri atau nama imunisasi pada master imunisasi."})
+			} else {
+				log.Printf("ERROR querying master_imunisasi for vaksin %q: %v", payload.NamaVaksin, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			}
+			return
+		}
+
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for penerimaan vaksin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		jumlahDosis := payload.JumlahVial * payload.DosisPerVial
+		var idBatch, dosisPerVial int
+		var kedaluwarsaTercatat time.Time
+		err = tx.QueryRow(ctx,
+			`INSERT INTO batch_vaksin (id_posyandu, nama_vaksin, nomor_batch, produsen, tanggal_kedaluwarsa, dosis_per_vial, status_vvm, stok_dosis)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            ON CONFLICT ((COALESCE(id_posyandu, 0)), nama_vaksin, nomor_batch)
+            DO UPDATE SET stok_dosis = batch_vaksin.stok_dosis + EXCLUDED.stok_dosis, updated_at = NOW()
+            RETURNING id, tanggal_kedaluwarsa, dosis_per_vial`,
+			payload.IdPosyandu, namaVaksin, nomorBatch, payload.Produsen, tanggalKedaluwarsa, payload.DosisPerVial, statusVVM, jumlahDosis).
+			Scan(&idBatch, &kedaluwarsaTercatat, &dosisPerVial)
+		if err != nil {
+			log.Printf("ERROR upserting batch_vaksin %s by kader %d: %v", nomorBatch, kaderId, err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == "batch_vaksin_id_posyandu_fkey" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Posyandu tidak ditemukan."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			return
+		}
+		if !kedaluwarsaTercatat.Equal(tanggalKedaluwarsa) || dosisPerVial != payload.DosisPerVial {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Batch %s sudah tercatat dengan tanggal kedaluwarsa %s dan %d dosis per vial.",
+				nomorBatch, kedaluwarsaTercatat.Format("2006-01-02"), dosisPerVial)})
+			return
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO transaksi_vaksin (id_batch, jenis, tanggal, jumlah_dosis, jumlah_vial, id_kader, catatan) VALUES ($1, 'penerimaan', $2, $3, $4, $5, $6)`,
+			idBatch, tanggal, jumlahDosis, payload.JumlahVial, kaderId, payload.Catatan)
+		if err != nil {
+			log.Printf("ERROR inserting penerimaan batch_vaksin %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			return
+		}
+
+		var b models.BatchVaksin
+		if err := scanBatchVaksin(tx.QueryRow(ctx, batchVaksinSelect+" WHERE b.id = $1", idBatch), &b); err != nil {
+			log.Printf("ERROR fetching batch_vaksin %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			return
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing penerimaan batch_vaksin %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan penerimaan vaksin."})
+			return
+		}
+		c.JSON(http.StatusCreated, b)
+	}
+}
+
+// GetBatchVaksinHandler menampilkan daftar batch vaksin, urut kedaluwarsa terdekat lebih dulu.
+// Query: id_posyandu, nama_vaksin, tersedia=true (hanya batch yang masih boleh dipakai dan ada stok).
+func GetBatchVaksinHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var conditions []string
+		var args []interface{}
+		if idPosyanduQuery := c.Query("id_posyandu"); idPosyanduQuery != "" {
+			idPosyandu, err := strconv.Atoi(idPosyanduQuery)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID posyandu tidak valid"})
+				return
+			}
+			args = append(args, idPosyandu)
+			conditions = append(conditions, fmt.Sprintf("b.id_posyandu = $%d", len(args)))
+		}
+		if namaVaksin := c.Query("nama_vaksin"); namaVaksin != "" {
+			args = append(args, namaVaksin)
+			conditions = append(conditions, fmt.Sprintf("LOWER(b.nama_vaksin) = LOWER($%d)", len(args)))
+		}
+		if c.Query("tersedia") == "true" {
+			conditions = append(conditions, "b.stok_dosis > 0 AND b.tanggal_kedaluwarsa >= CURRENT_DATE AND b.status_vvm IN ('A', 'B')")
+		}
+
+		query := batchVaksinSelect
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY b.nama_vaksin ASC, b.tanggal_kedaluwarsa ASC, b.id ASC"
+
+		rows, err := dbpool.Query(context.Background(), query, args...)
+		if err != nil {
+			log.Printf("ERROR querying batch_vaksin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.BatchVaksin, 0)
+		for rows.Next() {
+			var b models.BatchVaksin
+			if err := scanBatchVaksin(rows, &b); err != nil {
+				log.Printf("ERROR scanning batch_vaksin: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, b)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating batch_vaksin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// UbahVVMBatchVaksinHandler memperbarui tahap Vaccine Vial Monitor sebuah batch
+func UbahVVMBatchVaksinHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID batch tidak valid"})
+			return
+		}
+		var payload models.UbahVVMPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status VVM harus A, B, C atau D."})
+			return
+		}
+
+		tag, err := dbpool.Exec(context.Background(), "UPDATE batch_vaksin SET status_vvm = $1, updated_at = NOW() WHERE id = $2", payload.StatusVVM, id)
+		if err != nil {
+			log.Printf("ERROR updating status_vvm batch_vaksin %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update."})
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Batch vaksin tidak ditemukan."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Status VVM berhasil diperbarui!"})
+	}
+}
+
+// TambahTransaksiVaksinHandler mencatat vial yang dibuka, vaksin terbuang, atau penyesuaian stok opname
+func TambahTransaksiVaksinHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		idStr := c.Param("id")
+		idBatch, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID batch tidak valid"})
+			return
+		}
+		var payload models.TambahTransaksiVaksinPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap atau format salah."})
+			return
+		}
+		tanggal, err := time.Parse("2006-01-02", payload.Tanggal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal salah (YYYY-MM-DD)."})
+			return
+		}
+
+		// Perubahan stok dalam dosis (bertanda)
+		var perubahan int
+		var alasan *string
+		switch payload.Jenis {
+		case "buka_vial":
+			if payload.JumlahVial == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Jumlah vial yang dibuka wajib diisi."})
+				return
+			}
+		case "terbuang":
+			if payload.JumlahDosis == nil || *payload.JumlahDosis <= 0 || payload.Alasan == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Jumlah dosis terbuang (lebih dari nol) dan alasan wajib diisi."})
+				return
+			}
+			perubahan, alasan = -*payload.JumlahDosis, payload.Alasan
+		case "penyesuaian":
+			if payload.JumlahDosis == nil || *payload.JumlahDosis == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Jumlah dosis penyesuaian wajib diisi dan tidak boleh nol."})
+				return
+			}
+			perubahan = *payload.JumlahDosis
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for transaksi vaksin batch %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi vaksin."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var stok int
+		if err := tx.QueryRow(ctx, "SELECT stok_dosis FROM batch_vaksin WHERE id = $1 FOR UPDATE", idBatch).Scan(&stok); err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Batch vaksin tidak ditemukan."})
+			} else {
+				log.Printf("ERROR querying batch_vaksin %d: %v", idBatch, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi vaksin."})
+			}
+			return
+		}
+		if stok+perubahan < 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Stok batch hanya %d dosis.", stok)})
+			return
+		}
+
+		var t models.TransaksiVaksin
+		err = tx.QueryRow(ctx,
+			`INSERT INTO transaksi_vaksin (id_batch, jenis, tanggal, jumlah_dosis, jumlah_vial, alasan, id_kader, catatan)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            RETURNING id, id_batch, jenis, tanggal, jumlah_dosis, jumlah_vial, alasan, id_riwayat_imunisasi, id_kader, catatan, created_at`,
+			idBatch, payload.Jenis, tanggal, perubahan, payload.JumlahVial, alasan, kaderId, payload.Catatan).
+			Scan(&t.ID, &t.IdBatch, &t.Jenis, &t.Tanggal, &t.JumlahDosis, &t.JumlahVial, &t.Alasan, &t.IdRiwayatImunisasi, &t.IdKader, &t.Catatan, &t.CreatedAt)
+		if err != nil {
+			log.Printf("ERROR inserting transaksi vaksin batch %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi vaksin."})
+			return
+		}
+		if perubahan != 0 {
+			if _, err := tx.Exec(ctx, "UPDATE batch_vaksin SET stok_dosis = stok_dosis + $1, updated_at = NOW() WHERE id = $2", perubahan, idBatch); err != nil {
+				log.Printf("ERROR updating stok batch_vaksin %d: %v", idBatch, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi vaksin."})
+				return
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing transaksi vaksin batch %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi vaksin."})
+			return
+		}
+		c.JSON(http.StatusCreated, t)
+	}
+}
+
+// GetTransaksiVaksinHandler menampilkan kartu stok sebuah batch (seluruh transaksi, terbaru lebih dulu)
+func GetTransaksiVaksinHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		idBatch, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID batch tidak valid"})
+			return
+		}
+
+		rows, err := dbpool.Query(context.Background(),
+			`SELECT t.id, t.id_batch, t.jenis, t.tanggal, t.jumlah_dosis, t.jumlah_vial, t.alasan, t.id_riwayat_imunisasi,
+                    t.id_kader, k.nama_lengkap, t.catatan, t.created_at
+            FROM transaksi_vaksin t
+            LEFT JOIN kader k ON t.id_kader = k.id
+            WHERE t.id_batch = $1
+            ORDER BY t.tanggal DESC, t.id DESC`, idBatch)
+		if err != nil {
+			log.Printf("ERROR querying transaksi_vaksin batch %d: %v", idBatch, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.TransaksiVaksin, 0)
+		for rows.Next() {
+			var t models.TransaksiVaksin
+			if err := rows.Scan(&t.ID, &t.IdBatch, &t.Jenis, &t.Tanggal, &t.JumlahDosis, &t.JumlahVial, &t.Alasan, &t.IdRiwayatImunisasi,
+				&t.IdKader, &t.NamaKader, &t.Catatan, &t.CreatedAt); err != nil {
+				log.Printf("ERROR scanning transaksi_vaksin: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			daftar = append(daftar, t)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating transaksi_vaksin: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
@@ -0,0 +1,229 @@
+// handlers/versi_jadwal_imunisasi.go
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nadhifhafizp/api/db"
+	"github.com/nadhifhafizp/api/models"
+)
+
+// GetVersiJadwalImunisasiHandler menampilkan seluruh versi jadwal imunisasi beserta penandanya:
+// berlaku = versi yang dipakai untuk bayi yang lahir hari ini
+func GetVersiJadwalImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows, err := dbpool.Query(context.Background(),
+			`SELECT v.id, v.nama, v.berlaku_mulai, v.keterangan, v.id_kader_penerbit, k.nama_lengkap, v.created_at,
+                (SELECT COUNT(*) FROM dosis_versi_jadwal_imunisasi d WHERE d.id_versi = v.id),
+                v.berlaku_mulai = (SELECT MAX(berlaku_mulai) FROM versi_jadwal_imunisasi WHERE berlaku_mulai <= CURRENT_DATE)
+            FROM versi_jadwal_imunisasi v
+            LEFT JOIN kader k ON v.id_kader_penerbit = k.id
+            ORDER BY v.berlaku_mulai DESC`)
+		if err != nil {
+			log.Printf("ERROR querying versi jadwal imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		daftar := make([]models.VersiJadwalImunisasi, 0)
+		for rows.Next() {
+			var v models.VersiJadwalImunisasi
+			var berlaku *bool
+			if err := rows.Scan(&v.ID, &v.Nama, &v.BerlakuMulai, &v.Keterangan, &v.IdKaderPenerbit, &v.NamaKaderPenerbit, &v.CreatedAt, &v.JumlahDosis, &berlaku); err != nil {
+				log.Printf("ERROR scanning versi jadwal imunisasi: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			v.Berlaku = berlaku != nil && *berlaku
+			daftar = append(daftar, v)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating versi jadwal imunisasi: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		c.JSON(http.StatusOK, daftar)
+	}
+}
+
+// GetVersiJadwalImunisasiByIdHandler menampilkan satu versi jadwal beserta parameter setiap dosisnya
+func GetVersiJadwalImunisasiByIdHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID versi jadwal tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		var v models.VersiJadwalImunisasi
+		var berlaku *bool
+		err = dbpool.QueryRow(ctx,
+			`SELECT v.id, v.nama, v.berlaku_mulai, v.keterangan, v.id_kader_penerbit, k.nama_lengkap, v.created_at,
+                v.berlaku_mulai = (SELECT MAX(berlaku_mulai) FROM versi_jadwal_imunisasi WHERE berlaku_mulai <= CURRENT_DATE)
+            FROM versi_jadwal_imunisasi v
+            LEFT JOIN kader k ON v.id_kader_penerbit = k.id
+            WHERE v.id = $1`, id).Scan(&v.ID, &v.Nama, &v.BerlakuMulai, &v.Keterangan, &v.IdKaderPenerbit, &v.NamaKaderPenerbit, &v.CreatedAt, &berlaku)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Versi jadwal tidak ditemukan."})
+				return
+			}
+			log.Printf("ERROR fetching versi jadwal %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		v.Berlaku = berlaku != nil && *berlaku
+
+		rows, err := dbpool.Query(ctx,
+			`SELECT m.id, m.nama_imunisasi, m.seri, m.dosis_ke, d.usia_ideal_bulan, d.usia_min_hari, d.usia_max_hari, d.interval_min_hari, d.termasuk_idl
+            FROM dosis_versi_jadwal_imunisasi d
+            JOIN master_imunisasi m ON d.id_master_imunisasi = m.id
+            WHERE d.id_versi = $1
+            ORDER BY d.usia_ideal_bulan ASC, m.seri ASC NULLS FIRST, m.dosis_ke ASC, m.nama_imunisasi ASC`, id)
+		if err != nil {
+			log.Printf("ERROR querying dosis versi jadwal %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		defer rows.Close()
+
+		v.Dosis = make([]models.DosisJadwal, 0)
+		for rows.Next() {
+			var d models.DosisJadwal
+			if err := rows.Scan(&d.IdMasterImunisasi, &d.NamaImunisasi, &d.Seri, &d.DosisKe, &d.UsiaIdealBulan, &d.UsiaMinHari, &d.UsiaMaxHari, &d.IntervalMinHari, &d.TermasukIdl); err != nil {
+				log.Printf("ERROR scanning dosis versi jadwal: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindai data."})
+				return
+			}
+			v.Dosis = append(v.Dosis, d)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("ERROR iterating dosis versi jadwal: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses daftar."})
+			return
+		}
+		v.JumlahDosis = len(v.Dosis)
+		c.JSON(http.StatusOK, v)
+	}
+}
+
+// TerbitkanVersiJadwalImunisasiHandler menerbitkan versi jadwal baru. Tanpa daftar dosis, parameter
+// master imunisasi saat ini dibekukan apa adanya; dengan daftar dosis (impor jadwal nasional), master
+// diperbarui atau ditambah terlebih dahulu lalu hanya dosis tersebut yang masuk ke versi.
+func TerbitkanVersiJadwalImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kaderIdInterface, exists := c.Get("kaderId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi tidak valid."})
+			return
+		}
+		kaderId := kaderIdInterface.(int)
+
+		var payload models.TerbitkanVersiJadwalPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nama versi dan tanggal berlaku wajib diisi; setiap dosis wajib memiliki nama imunisasi."})
+			return
+		}
+		berlakuMulai, err := time.Parse("2006-01-02", payload.BerlakuMulai)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format tanggal berlaku salah (YYYY-MM-DD)."})
+			return
+		}
+
+		ctx := context.Background()
+		tx, err := dbpool.Begin(ctx)
+		if err != nil {
+			log.Printf("ERROR starting transaction for versi jadwal: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulai transaksi."})
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		var idMaster []int
+		dibuat := 0
+		for _, d := range payload.Dosis {
+			id, baru, err := db.SimpanDosisMaster(ctx, tx, d)
+			if err != nil {
+				log.Printf("ERROR saving dosis %s for versi jadwal: %v", d.NamaImunisasi, err)
+				if tanggapiGalatSeriImunisasi(c, err) {
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan dosis " + d.NamaImunisasi + "."})
+				return
+			}
+			if baru {
+				dibuat++
+			}
+			idMaster = append(idMaster, id)
+		}
+
+		id, err := db.TerbitkanVersiJadwal(ctx, tx, payload.Nama, berlakuMulai, payload.Keterangan, &kaderId, idMaster)
+		if err != nil {
+			log.Printf("ERROR inserting versi jadwal: %v", err)
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == "versi_jadwal_imunisasi_berlaku_mulai_key" {
+				c.JSON(http.StatusConflict, gin.H{"error": "Sudah ada versi jadwal yang berlaku mulai tanggal tersebut."})
+				return
+			}
+			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Daftar dosis memuat imunisasi yang sama lebih dari sekali."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menerbitkan versi jadwal."})
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			log.Printf("ERROR committing versi jadwal: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan transaksi."})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Versi jadwal imunisasi berhasil diterbitkan!", "id": id, "master_baru": dibuat})
+	}
+}
+
+// DeleteVersiJadwalImunisasiHandler menghapus versi jadwal yang belum mulai berlaku. Versi yang sudah
+// berlaku tidak boleh dihapus agar arti "jatuh tempo" bagi kohort yang sudah lahir tidak berubah.
+func DeleteVersiJadwalImunisasiHandler(dbpool *pgxpool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID versi jadwal tidak valid"})
+			return
+		}
+		ctx := context.Background()
+
+		var berlakuMulai time.Time
+		err = dbpool.QueryRow(ctx, "SELECT berlaku_mulai FROM versi_jadwal_imunisasi WHERE id = $1", id).Scan(&berlakuMulai)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Versi jadwal tidak ditemukan."})
+				return
+			}
+			log.Printf("ERROR fetching versi jadwal %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data."})
+			return
+		}
+		if !berlakuMulai.After(tanggalHariIni()) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Versi jadwal yang sudah mulai berlaku tidak dapat dihapus."})
+			return
+		}
+
+		if _, err := dbpool.Exec(ctx, "DELETE FROM versi_jadwal_imunisasi WHERE id = $1", id); err != nil {
+			log.Printf("ERROR deleting versi jadwal %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus."})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Versi jadwal imunisasi berhasil dihapus."})
+	}
+}
@@ -0,0 +1,27 @@
+package imunisasi
+
+import "time"
+
+// TenggangDefaulterHari adalah tenggang bawaan (hari sejak tanggal jadwal) sebelum dosis
+// yang belum diberikan membuat anak masuk daftar pelacakan (defaulter)
+const TenggangDefaulterHari = JendelaJatuhTempoHari
+
+// Defaulter mengembalikan dosis pada jadwal yang belum diberikan dan sudah melewati tenggangHari
+// sejak tanggal jadwalnya pada tanggal acuan. HariTerlambat diisi untuk setiap dosis. Dosis yang
+// batas umur maksimalnya sudah lewat tidak dapat dikejar lagi sehingga tidak dimasukkan.
+func Defaulter(tanggalLahir time.Time, jadwal []Jadwal, acuan time.Time, tenggangHari int) []Jadwal {
+	var daftar []Jadwal
+	for _, j := range jadwal {
+		if j.Status == Selesai {
+			continue
+		}
+		if j.UsiaMaxHari != nil && selisihHari(tanggalLahir, acuan) > *j.UsiaMaxHari {
+			continue
+		}
+		if hari := selisihHari(j.TanggalJadwal, acuan); hari >= tenggangHari {
+			j.HariTerlambat = hari
+			daftar = append(daftar, j)
+		}
+	}
+	return daftar
+}
@@ -0,0 +1,82 @@
+package imunisasi
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pemberian adalah satu dosis yang tercatat di riwayat imunisasi anak
+type Pemberian struct {
+	IdAntigen int
+	Tanggal   time.Time
+}
+
+// HasilPeriksaDosis adalah hasil pemeriksaan satu dosis sebelum dicatat. Galat menolak pencatatan;
+// Ganda menandai galat karena dosis yang sama sudah tercatat. Peringatan masih dapat dicatat
+// setelah dikonfirmasi petugas.
+type HasilPeriksaDosis struct {
+	Galat      string
+	Ganda      bool
+	Peringatan []string
+}
+
+// PeriksaDosis memeriksa sebuah dosis yang akan dicatat terhadap tanggal lahir, batas umur antigen,
+// dan riwayat imunisasi anak lainnya (tanpa catatan yang sedang diubah): urutan dan interval minimal
+// dibandingkan dengan dosis sebelum maupun sesudahnya pada seri yang sama.
+func PeriksaDosis(tanggalLahir time.Time, a Antigen, tanggal, hariIni time.Time, antigen []Antigen, riwayat []Pemberian) HasilPeriksaDosis {
+	var hasil HasilPeriksaDosis
+	if tanggal.Before(tanggalLahir) {
+		hasil.Galat = "Tanggal imunisasi sebelum tanggal lahir anak."
+		return hasil
+	}
+	if tanggal.After(hariIni) {
+		hasil.Galat = "Tanggal imunisasi tidak boleh di masa depan."
+		return hasil
+	}
+	diberikan := make(map[int]time.Time, len(riwayat))
+	for _, r := range riwayat {
+		if tgl, ok := diberikan[r.IdAntigen]; !ok || r.Tanggal.Before(tgl) {
+			diberikan[r.IdAntigen] = r.Tanggal
+		}
+	}
+	if tgl, ok := diberikan[a.ID]; ok {
+		hasil.Galat = fmt.Sprintf("%s sudah tercatat diberikan pada %s.", a.Nama, tgl.Format("02-01-2006"))
+		hasil.Ganda = true
+		return hasil
+	}
+
+	usia := selisihHari(tanggalLahir, tanggal)
+	if a.UsiaMinHari != nil && usia < *a.UsiaMinHari {
+		hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Terlalu dini: umur anak %d hari, umur minimal %s %d hari.", usia, a.Nama, *a.UsiaMinHari))
+	}
+	if a.UsiaMaxHari != nil && usia > *a.UsiaMaxHari {
+		hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Melewati batas umur: umur anak %d hari, umur maksimal %s %d hari.", usia, a.Nama, *a.UsiaMaxHari))
+	}
+
+	seri := indeksSeri(antigen)
+	if idSebelumnya, ok := dosisSebelumnya(seri, a); ok {
+		tglSebelumnya, ada := diberikan[idSebelumnya]
+		switch {
+		case !ada:
+			hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Dosis %d seri %s belum tercatat.", a.DosisKe-1, *a.Seri))
+		case tglSebelumnya.After(tanggal):
+			hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Dosis %d seri %s tercatat sesudah tanggal ini (%s).", a.DosisKe-1, *a.Seri, tglSebelumnya.Format("02-01-2006")))
+		case a.IntervalMinHari != nil && selisihHari(tglSebelumnya, tanggal) < *a.IntervalMinHari:
+			hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Terlalu dekat dengan dosis sebelumnya: baru %d hari sejak %s, minimal %d hari.",
+				selisihHari(tglSebelumnya, tanggal), tglSebelumnya.Format("02-01-2006"), *a.IntervalMinHari))
+		}
+	}
+	// Dosis yang dicatat susulan (atau tanggalnya diubah) juga diperiksa terhadap dosis berikutnya yang sudah tercatat
+	if b, ok := dosisBerikutnya(seri, antigen, a); ok {
+		if tglBerikutnya, ada := diberikan[b.ID]; ada {
+			switch {
+			case tglBerikutnya.Before(tanggal):
+				hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Dosis %d seri %s tercatat sebelum tanggal ini (%s).", b.DosisKe, *a.Seri, tglBerikutnya.Format("02-01-2006")))
+			case b.IntervalMinHari != nil && selisihHari(tanggal, tglBerikutnya) < *b.IntervalMinHari:
+				hasil.Peringatan = append(hasil.Peringatan, fmt.Sprintf("Terlalu dekat dengan dosis berikutnya: hanya %d hari sebelum %s, minimal %d hari.",
+					selisihHari(tanggal, tglBerikutnya), tglBerikutnya.Format("02-01-2006"), *b.IntervalMinHari))
+			}
+		}
+	}
+	return hasil
+}
@@ -0,0 +1,53 @@
+package imunisasi
+
+import "time"
+
+// Status Imunisasi Dasar Lengkap (IDL). Target nasional adalah seluruh antigen dasar
+// diberikan sebelum anak berumur 12 bulan.
+const (
+	IDLTepatWaktu   = "lengkap_tepat_waktu"
+	IDLLengkap      = "lengkap" // Lengkap, tetapi dosis terakhir diberikan pada umur 12 bulan atau lebih
+	IDLBelumLengkap = "belum_lengkap"
+)
+
+// UsiaIDLBulan adalah batas umur imunisasi dasar lengkap tepat waktu
+const UsiaIDLBulan = 12
+
+// StatusIDL adalah status imunisasi dasar lengkap seorang anak pada tanggal acuan
+type StatusIDL struct {
+	Status         string
+	TanggalLengkap *time.Time // Tanggal antigen dasar terakhir diberikan, jika sudah lengkap
+	Diberikan      []Jadwal   // Antigen dasar yang sudah diberikan, urut jadwal
+	BelumDiberikan []Antigen
+}
+
+// HitungIDL menghitung status IDL dari antigen yang termasuk IDL. Tanpa antigen IDL di master
+// data, status selalu belum lengkap.
+func HitungIDL(tanggalLahir time.Time, antigen []Antigen, diberikan map[int]time.Time, acuan time.Time) StatusIDL {
+	hasil := StatusIDL{Status: IDLBelumLengkap, BelumDiberikan: make([]Antigen, 0)}
+	var terakhir time.Time
+	ada := false
+	for _, j := range Susun(tanggalLahir, antigen, diberikan, acuan) {
+		if !j.TermasukIDL {
+			continue
+		}
+		ada = true
+		if j.Status != Selesai {
+			hasil.BelumDiberikan = append(hasil.BelumDiberikan, j.Antigen)
+			continue
+		}
+		hasil.Diberikan = append(hasil.Diberikan, j)
+		if j.TanggalDiberikan.After(terakhir) {
+			terakhir = *j.TanggalDiberikan
+		}
+	}
+	if !ada || len(hasil.BelumDiberikan) > 0 {
+		return hasil
+	}
+	hasil.TanggalLengkap = &terakhir
+	hasil.Status = IDLLengkap
+	if terakhir.Before(TambahBulan(tanggalLahir, UsiaIDLBulan)) {
+		hasil.Status = IDLTepatWaktu
+	}
+	return hasil
+}
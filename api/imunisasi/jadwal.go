@@ -0,0 +1,154 @@
+// Package imunisasi menyusun jadwal imunisasi anak dari master imunisasi dan riwayat pemberiannya.
+// Jadwal dihitung dari tanggal lahir (umur kronologis), juga untuk bayi prematur.
+package imunisasi
+
+import "time"
+
+// Status antigen pada jadwal anak
+const (
+	Selesai    = "selesai"
+	JatuhTempo = "jatuh_tempo"
+	AkanDatang = "akan_datang"
+	Terlambat  = "terlambat"
+)
+
+// JendelaJatuhTempoHari adalah lama (hari) sejak tanggal jadwal sebuah antigen masih dianggap
+// jatuh tempo; sesudahnya antigen yang belum diberikan dianggap terlambat
+const JendelaJatuhTempoHari = 28
+
+// Antigen adalah satu baris master imunisasi, yaitu satu dosis. Dosis sebuah seri
+// (mis. DPT-HB-Hib 1-3) memiliki Seri yang sama dan DosisKe berurutan.
+type Antigen struct {
+	ID              int
+	Nama            string
+	UsiaIdealBulan  int
+	Seri            *string
+	DosisKe         int
+	UsiaMinHari     *int
+	UsiaMaxHari     *int
+	IntervalMinHari *int // Jarak minimal dari dosis sebelumnya pada seri yang sama
+	TermasukIDL     bool // Bagian dari Imunisasi Dasar Lengkap
+}
+
+// kunciDosis mengidentifikasi satu dosis dalam seri
+type kunciDosis struct {
+	seri    string
+	dosisKe int
+}
+
+// indeksSeri memetakan setiap dosis seri ke ID antigennya
+func indeksSeri(antigen []Antigen) map[kunciDosis]int {
+	indeks := make(map[kunciDosis]int)
+	for _, a := range antigen {
+		if a.Seri != nil {
+			indeks[kunciDosis{*a.Seri, a.DosisKe}] = a.ID
+		}
+	}
+	return indeks
+}
+
+// dosisSebelumnya mengembalikan ID antigen dosis sebelumnya pada seri yang sama
+func dosisSebelumnya(indeks map[kunciDosis]int, a Antigen) (int, bool) {
+	if a.Seri == nil || a.DosisKe <= 1 {
+		return 0, false
+	}
+	id, ok := indeks[kunciDosis{*a.Seri, a.DosisKe - 1}]
+	return id, ok
+}
+
+// dosisBerikutnya mengembalikan antigen dosis berikutnya pada seri yang sama
+func dosisBerikutnya(indeks map[kunciDosis]int, antigen []Antigen, a Antigen) (Antigen, bool) {
+	if a.Seri == nil {
+		return Antigen{}, false
+	}
+	id, ok := indeks[kunciDosis{*a.Seri, a.DosisKe + 1}]
+	if !ok {
+		return Antigen{}, false
+	}
+	for _, b := range antigen {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Antigen{}, false
+}
+
+// Jadwal adalah status satu antigen bagi seorang anak pada tanggal acuan
+type Jadwal struct {
+	Antigen
+	TanggalJadwal    time.Time
+	Status           string
+	TanggalDiberikan *time.Time
+	HariTerlambat    int // Hari sejak tanggal jadwal, hanya untuk status terlambat
+}
+
+// TambahBulan menambahkan n bulan kalender pada tanggal. Tanggal yang tidak ada pada bulan tujuan
+// (mis. 31 Januari + 1 bulan) dibulatkan ke hari terakhir bulan tersebut, bukan melimpah ke bulan berikutnya.
+func TambahBulan(t time.Time, n int) time.Time {
+	awal := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	hariTerakhir := awal.AddDate(0, 1, -1).Day()
+	return time.Date(awal.Year(), awal.Month(), min(t.Day(), hariTerakhir), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// TanggalJadwal mengembalikan tanggal sebuah dosis dijadwalkan: umur ideal, tetapi tidak sebelum
+// umur minimal dan tidak sebelum interval minimal dari dosis sebelumnya yang sudah diberikan
+func TanggalJadwal(tanggalLahir time.Time, a Antigen, tanggalDosisSebelumnya *time.Time) time.Time {
+	jadwal := TambahBulan(tanggalLahir, a.UsiaIdealBulan)
+	if a.UsiaMinHari != nil {
+		if minimal := tanggalLahir.AddDate(0, 0, *a.UsiaMinHari); minimal.After(jadwal) {
+			jadwal = minimal
+		}
+	}
+	if tanggalDosisSebelumnya != nil && a.IntervalMinHari != nil {
+		if minimal := tanggalDosisSebelumnya.AddDate(0, 0, *a.IntervalMinHari); minimal.After(jadwal) {
+			jadwal = minimal
+		}
+	}
+	return jadwal
+}
+
+// Susun menyusun jadwal semua antigen untuk anak yang lahir pada tanggalLahir. diberikan memetakan
+// ID antigen ke tanggal pemberiannya; pemberian sesudah tanggal acuan diabaikan.
+func Susun(tanggalLahir time.Time, antigen []Antigen, diberikan map[int]time.Time, acuan time.Time) []Jadwal {
+	seri := indeksSeri(antigen)
+	jadwal := make([]Jadwal, 0, len(antigen))
+	for _, a := range antigen {
+		var sebelumnya *time.Time
+		if id, ok := dosisSebelumnya(seri, a); ok {
+			if tgl, ok := diberikan[id]; ok && !tgl.After(acuan) {
+				sebelumnya = &tgl
+			}
+		}
+		j := Jadwal{Antigen: a, TanggalJadwal: TanggalJadwal(tanggalLahir, a, sebelumnya)}
+		if tgl, ok := diberikan[a.ID]; ok && !tgl.After(acuan) {
+			j.Status = Selesai
+			j.TanggalDiberikan = &tgl
+		} else {
+			j.Status = statusBelumDiberikan(j.TanggalJadwal, acuan)
+			if j.Status == Terlambat {
+				j.HariTerlambat = selisihHari(j.TanggalJadwal, acuan)
+			}
+		}
+		jadwal = append(jadwal, j)
+	}
+	return jadwal
+}
+
+// statusBelumDiberikan menilai antigen yang belum diberikan terhadap tanggal acuan
+func statusBelumDiberikan(tanggalJadwal, acuan time.Time) string {
+	switch hari := selisihHari(tanggalJadwal, acuan); {
+	case hari < 0:
+		return AkanDatang
+	case hari < JendelaJatuhTempoHari:
+		return JatuhTempo
+	default:
+		return Terlambat
+	}
+}
+
+// selisihHari mengembalikan jumlah hari kalender dari a ke b
+func selisihHari(a, b time.Time) int {
+	ya, ma, da := a.Date()
+	yb, mb, db := b.Date()
+	return int(time.Date(yb, mb, db, 0, 0, 0, 0, time.UTC).Sub(time.Date(ya, ma, da, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
@@ -0,0 +1,107 @@
+package imunisasi
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status dosis pada rencana imunisasi kejar
+const (
+	KejarDiberikan          = "diberikan"
+	KejarBisaDiberikan      = "bisa_diberikan"
+	KejarMenunggu           = "menunggu"
+	KejarTidakDiindikasikan = "tidak_diindikasikan"
+)
+
+// DosisKejar adalah satu dosis pada rencana imunisasi kejar
+type DosisKejar struct {
+	Antigen
+	Status           string
+	TanggalDiberikan *time.Time
+	TanggalTercepat  *time.Time // Tanggal paling awal dosis boleh diberikan, tidak sebelum tanggal acuan
+	BatasTanggal     *time.Time // Tanggal terakhir sesuai umur maksimal
+	Alasan           string     // Diisi untuk dosis yang tidak diindikasikan lagi
+}
+
+// Kejar menyusun rencana imunisasi kejar pada tanggal acuan. Berbeda dengan Susun yang memakai umur
+// ideal, dosis yang tertinggal dijadwalkan secepatnya: tidak sebelum umur minimal (atau umur ideal bila
+// tidak diatur) dan interval minimal dari dosis sebelumnya, baik yang sudah diberikan maupun yang
+// direncanakan. Dosis yang tanggal tercepatnya melewati umur maksimal tidak diindikasikan lagi,
+// begitu pula dosis berikutnya pada seri tersebut.
+func Kejar(tanggalLahir time.Time, antigen []Antigen, diberikan map[int]time.Time, acuan time.Time) []DosisKejar {
+	seri := indeksSeri(antigen)
+	posisi := make(map[int]int, len(antigen))
+	for i, a := range antigen {
+		posisi[a.ID] = i
+	}
+	rencana := make([]DosisKejar, len(antigen))
+	dihitung := make([]bool, len(antigen))
+
+	var hitung func(i int) *DosisKejar
+	hitung = func(i int) *DosisKejar {
+		d := &rencana[i]
+		if dihitung[i] {
+			return d
+		}
+		dihitung[i] = true
+		a := antigen[i]
+		d.Antigen = a
+		if a.UsiaMaxHari != nil {
+			batas := tanggalLahir.AddDate(0, 0, *a.UsiaMaxHari)
+			d.BatasTanggal = &batas
+		}
+		if tgl, ok := diberikan[a.ID]; ok && !tgl.After(acuan) {
+			d.Status = KejarDiberikan
+			d.TanggalDiberikan = &tgl
+			return d
+		}
+
+		tercepat := TambahBulan(tanggalLahir, a.UsiaIdealBulan)
+		if a.UsiaMinHari != nil {
+			tercepat = tanggalLahir.AddDate(0, 0, *a.UsiaMinHari)
+		}
+		if tercepat.Before(acuan) {
+			tercepat = acuan
+		}
+		if id, ok := dosisSebelumnya(seri, a); ok {
+			if j, ok := posisi[id]; ok {
+				sebelumnya := hitung(j)
+				tglSebelumnya := sebelumnya.TanggalTercepat
+				switch sebelumnya.Status {
+				case KejarTidakDiindikasikan:
+					d.Status = KejarTidakDiindikasikan
+					d.Alasan = fmt.Sprintf("Dosis %d seri %s tidak dapat diberikan lagi.", a.DosisKe-1, *a.Seri)
+					return d
+				case KejarDiberikan:
+					tglSebelumnya = sebelumnya.TanggalDiberikan
+				}
+				if a.IntervalMinHari != nil {
+					if minimal := tglSebelumnya.AddDate(0, 0, *a.IntervalMinHari); minimal.After(tercepat) {
+						tercepat = minimal
+					}
+				}
+			}
+		}
+
+		if d.BatasTanggal != nil && tercepat.After(*d.BatasTanggal) {
+			d.Status = KejarTidakDiindikasikan
+			if acuan.After(*d.BatasTanggal) {
+				d.Alasan = fmt.Sprintf("Umur anak melewati umur maksimal %s (%d hari).", a.Nama, *a.UsiaMaxHari)
+			} else {
+				d.Alasan = fmt.Sprintf("Interval minimal dari dosis sebelumnya melewati umur maksimal %s (%d hari).", a.Nama, *a.UsiaMaxHari)
+			}
+			return d
+		}
+		d.TanggalTercepat = &tercepat
+		d.Status = KejarMenunggu
+		if !tercepat.After(acuan) {
+			d.Status = KejarBisaDiberikan
+		}
+		return d
+	}
+
+	for i := range antigen {
+		hitung(i)
+	}
+	return rencana
+}
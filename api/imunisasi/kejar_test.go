@@ -0,0 +1,164 @@
+package imunisasi
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// jadwalNasional menyalin jadwal imunisasi rutin program nasional dari cmd/seed-jadwal, dengan ID
+// antigen sesuai urutannya
+func jadwalNasional() []Antigen {
+	polio, dpt, pcv, rota, ipv, mr := ptr("Polio"), ptr("DPT-HB-Hib"), ptr("PCV"), ptr("Rotavirus"), ptr("IPV"), ptr("MR")
+	rotaDosis1, rotaTerakhir, bawah5Tahun := ptr(104), ptr(243), ptr(1825)
+	antigen := []Antigen{
+		{Nama: "HB-0", DosisKe: 1, UsiaIdealBulan: 0, UsiaMaxHari: ptr(7)},
+		{Nama: "BCG", DosisKe: 1, UsiaIdealBulan: 1, UsiaMaxHari: ptr(365)},
+		{Nama: "Polio 1", Seri: polio, DosisKe: 1, UsiaIdealBulan: 1},
+		{Nama: "Polio 2", Seri: polio, DosisKe: 2, UsiaIdealBulan: 2, IntervalMinHari: ptr(28)},
+		{Nama: "Polio 3", Seri: polio, DosisKe: 3, UsiaIdealBulan: 3, IntervalMinHari: ptr(28)},
+		{Nama: "Polio 4", Seri: polio, DosisKe: 4, UsiaIdealBulan: 4, IntervalMinHari: ptr(28)},
+		{Nama: "DPT-HB-Hib 1", Seri: dpt, DosisKe: 1, UsiaIdealBulan: 2, UsiaMinHari: ptr(42), UsiaMaxHari: bawah5Tahun},
+		{Nama: "DPT-HB-Hib 2", Seri: dpt, DosisKe: 2, UsiaIdealBulan: 3, UsiaMaxHari: bawah5Tahun, IntervalMinHari: ptr(28)},
+		{Nama: "DPT-HB-Hib 3", Seri: dpt, DosisKe: 3, UsiaIdealBulan: 4, UsiaMaxHari: bawah5Tahun, IntervalMinHari: ptr(28)},
+		{Nama: "PCV 1", Seri: pcv, DosisKe: 1, UsiaIdealBulan: 2, UsiaMinHari: ptr(42), UsiaMaxHari: bawah5Tahun},
+		{Nama: "PCV 2", Seri: pcv, DosisKe: 2, UsiaIdealBulan: 3, UsiaMaxHari: bawah5Tahun, IntervalMinHari: ptr(28)},
+		{Nama: "Rotavirus 1", Seri: rota, DosisKe: 1, UsiaIdealBulan: 2, UsiaMinHari: ptr(42), UsiaMaxHari: rotaDosis1},
+		{Nama: "Rotavirus 2", Seri: rota, DosisKe: 2, UsiaIdealBulan: 3, UsiaMaxHari: rotaTerakhir, IntervalMinHari: ptr(28)},
+		{Nama: "Rotavirus 3", Seri: rota, DosisKe: 3, UsiaIdealBulan: 4, UsiaMaxHari: rotaTerakhir, IntervalMinHari: ptr(28)},
+		{Nama: "IPV 1", Seri: ipv, DosisKe: 1, UsiaIdealBulan: 4, UsiaMinHari: ptr(98)},
+		{Nama: "IPV 2", Seri: ipv, DosisKe: 2, UsiaIdealBulan: 9, IntervalMinHari: ptr(28)},
+		{Nama: "MR 1", Seri: mr, DosisKe: 1, UsiaIdealBulan: 9, UsiaMinHari: ptr(270)},
+		{Nama: "PCV 3", Seri: pcv, DosisKe: 3, UsiaIdealBulan: 12, UsiaMaxHari: bawah5Tahun, IntervalMinHari: ptr(56)},
+		{Nama: "DPT-HB-Hib 4", Seri: dpt, DosisKe: 4, UsiaIdealBulan: 18, UsiaMaxHari: bawah5Tahun, IntervalMinHari: ptr(180)},
+		{Nama: "MR 2", Seri: mr, DosisKe: 2, UsiaIdealBulan: 18, IntervalMinHari: ptr(180)},
+	}
+	for i := range antigen {
+		antigen[i].ID = i + 1
+		antigen[i].TermasukIDL = antigen[i].UsiaIdealBulan < 12
+	}
+	return antigen
+}
+
+// kejarIngin adalah hasil yang diharapkan untuk satu dosis; tercepat dalam hari umur, -1 bila kosong
+type kejarIngin struct {
+	status   string
+	tercepat int
+	alasan   string
+}
+
+func TestKejarJadwalNasional(t *testing.T) {
+	lahir := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	hari := func(n int) time.Time { return lahir.AddDate(0, 0, n) }
+
+	kasus := []struct {
+		nama      string
+		diberikan map[string]int // Nama antigen ke umur (hari) saat diberikan
+		acuanHari int
+		ingin     map[string]kejarIngin
+	}{
+		{
+			nama:      "umur 10 minggu belum diimunisasi",
+			acuanHari: 70,
+			ingin: map[string]kejarIngin{
+				"HB-0":        {KejarTidakDiindikasikan, -1, "melewati umur maksimal HB-0"},
+				"BCG":         {KejarBisaDiberikan, 70, ""},
+				"Rotavirus 1": {KejarBisaDiberikan, 70, ""},
+				"Rotavirus 2": {KejarMenunggu, 98, ""},
+				"Rotavirus 3": {KejarMenunggu, 126, ""},
+				"IPV 1":       {KejarMenunggu, 98, ""},
+				"MR 1":        {KejarMenunggu, 270, ""},
+			},
+		},
+		{
+			nama:      "umur 16 minggu melewati batas rotavirus dosis 1",
+			acuanHari: 112,
+			ingin: map[string]kejarIngin{
+				"Rotavirus 1":  {KejarTidakDiindikasikan, -1, "melewati umur maksimal Rotavirus 1"},
+				"Rotavirus 2":  {KejarTidakDiindikasikan, -1, "Dosis 1 seri Rotavirus"},
+				"Rotavirus 3":  {KejarTidakDiindikasikan, -1, "Dosis 2 seri Rotavirus"},
+				"DPT-HB-Hib 1": {KejarBisaDiberikan, 112, ""},
+				"DPT-HB-Hib 2": {KejarMenunggu, 140, ""},
+			},
+		},
+		{
+			nama:      "rotavirus dosis 1 terlambat sehingga dosis 3 tidak terkejar",
+			diberikan: map[string]int{"Rotavirus 1": 200},
+			acuanHari: 210,
+			ingin: map[string]kejarIngin{
+				"Rotavirus 1": {KejarDiberikan, -1, ""},
+				"Rotavirus 2": {KejarMenunggu, 228, ""},
+				"Rotavirus 3": {KejarTidakDiindikasikan, -1, "Interval minimal dari dosis sebelumnya"},
+			},
+		},
+		{
+			nama:      "interval dihitung dari dosis yang sudah diberikan",
+			diberikan: map[string]int{"DPT-HB-Hib 1": 60, "PCV 1": 60, "PCV 2": 100},
+			acuanHari: 70,
+			ingin: map[string]kejarIngin{
+				"DPT-HB-Hib 1": {KejarDiberikan, -1, ""},
+				"DPT-HB-Hib 2": {KejarMenunggu, 91, ""},
+				"PCV 2":        {KejarMenunggu, 91, ""},
+			},
+		},
+		{
+			nama:      "umur 3 tahun belum diimunisasi",
+			acuanHari: 1096,
+			ingin: map[string]kejarIngin{
+				"BCG":          {KejarTidakDiindikasikan, -1, "melewati umur maksimal BCG"},
+				"DPT-HB-Hib 1": {KejarBisaDiberikan, 1096, ""},
+				"DPT-HB-Hib 2": {KejarMenunggu, 1124, ""},
+				"DPT-HB-Hib 3": {KejarMenunggu, 1152, ""},
+				"DPT-HB-Hib 4": {KejarMenunggu, 1332, ""},
+				"PCV 3":        {KejarMenunggu, 1180, ""},
+				"MR 1":         {KejarBisaDiberikan, 1096, ""},
+				"MR 2":         {KejarMenunggu, 1276, ""},
+			},
+		},
+		{
+			nama:      "menjelang umur 5 tahun",
+			acuanHari: 1800,
+			ingin: map[string]kejarIngin{
+				"DPT-HB-Hib 1": {KejarBisaDiberikan, 1800, ""},
+				"DPT-HB-Hib 2": {KejarTidakDiindikasikan, -1, "Interval minimal dari dosis sebelumnya"},
+				"DPT-HB-Hib 3": {KejarTidakDiindikasikan, -1, "Dosis 2 seri DPT-HB-Hib"},
+			},
+		},
+	}
+
+	antigen := jadwalNasional()
+	idAntigen := make(map[string]int, len(antigen))
+	for _, a := range antigen {
+		idAntigen[a.Nama] = a.ID
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			diberikan := make(map[int]time.Time, len(k.diberikan))
+			for nama, umur := range k.diberikan {
+				diberikan[idAntigen[nama]] = hari(umur)
+			}
+			rencana := make(map[string]DosisKejar)
+			for _, d := range Kejar(lahir, antigen, diberikan, hari(k.acuanHari)) {
+				rencana[d.Nama] = d
+			}
+			for nama, ingin := range k.ingin {
+				d := rencana[nama]
+				if d.Status != ingin.status {
+					t.Errorf("%s: status = %q, ingin %q (%s)", nama, d.Status, ingin.status, d.Alasan)
+					continue
+				}
+				switch {
+				case ingin.tercepat < 0 && d.TanggalTercepat != nil:
+					t.Errorf("%s: tanggal tercepat = %s, ingin kosong", nama, d.TanggalTercepat.Format("2006-01-02"))
+				case ingin.tercepat >= 0 && (d.TanggalTercepat == nil || !d.TanggalTercepat.Equal(hari(ingin.tercepat))):
+					t.Errorf("%s: tanggal tercepat = %v, ingin %s", nama, d.TanggalTercepat, hari(ingin.tercepat).Format("2006-01-02"))
+				}
+				if !strings.Contains(d.Alasan, ingin.alasan) {
+					t.Errorf("%s: alasan = %q, ingin memuat %q", nama, d.Alasan, ingin.alasan)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package imunisasi
+
+import "time"
+
+// Versi adalah satu set jadwal imunisasi yang berlaku bagi anak yang lahir sejak BerlakuMulai.
+// Parameter setiap dosis dibekukan saat versi diterbitkan, sehingga perubahan jadwal nasional
+// sesudahnya tidak mengubah arti "jatuh tempo" bagi kohort kelahiran sebelumnya.
+type Versi struct {
+	ID           int
+	Nama         string
+	BerlakuMulai time.Time
+	Antigen      []Antigen
+}
+
+// Kalender adalah seluruh versi jadwal, urut BerlakuMulai dari yang terlama
+type Kalender []Versi
+
+// UntukKelahiran mengembalikan versi yang berlaku bagi kohort kelahiran tanggalLahir, yaitu versi
+// terakhir yang mulai berlaku pada atau sebelum tanggal lahir. Anak yang lahir sebelum versi pertama
+// memakai versi pertama.
+func (k Kalender) UntukKelahiran(tanggalLahir time.Time) Versi {
+	if len(k) == 0 {
+		return Versi{}
+	}
+	berlaku := k[0]
+	for _, v := range k[1:] {
+		if v.BerlakuMulai.After(tanggalLahir) {
+			break
+		}
+		berlaku = v
+	}
+	return berlaku
+}
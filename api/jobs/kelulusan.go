@@ -0,0 +1,52 @@
+// jobs/kelulusan.go
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UsiaLulusBulan adalah usia (bulan) saat anak otomatis dianggap lulus dari posyandu balita
+const UsiaLulusBulan = 60
+
+// JalankanKelulusanOtomatis langsung memproses kelulusan sekali, lalu mengulanginya setiap interval.
+// Dipanggil sebagai goroutine dari main.
+func JalankanKelulusanOtomatis(dbpool *pgxpool.Pool, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		jumlah, err := LuluskanAnak(context.Background(), dbpool)
+		if err != nil {
+			log.Printf("ERROR running kelulusan otomatis: %v", err)
+		} else if jumlah > 0 {
+			log.Printf("INFO: %d anak otomatis berstatus lulus (usia %d bulan)", jumlah, UsiaLulusBulan)
+		}
+		<-ticker.C
+	}
+}
+
+// LuluskanAnak mengubah status anak aktif yang sudah berusia 60 bulan menjadi "lulus"
+// dan mencatatnya di riwayat_status_anak. Mengembalikan jumlah anak yang diluluskan.
+func LuluskanAnak(ctx context.Context, dbpool *pgxpool.Pool) (int64, error) {
+	cmdTag, err := dbpool.Exec(ctx,
+		`WITH lulus AS (
+            UPDATE anak
+            SET status = 'lulus',
+                status_tanggal = (tanggal_lahir + make_interval(months => $1))::date,
+                status_alasan = $2,
+                updated_at = NOW()
+            WHERE status = 'aktif' AND tanggal_lahir + make_interval(months => $1) <= CURRENT_DATE
+            RETURNING id, status_tanggal
+        )
+        INSERT INTO riwayat_status_anak (id_anak, status_lama, status_baru, tanggal_berlaku, alasan)
+        SELECT id, 'aktif', 'lulus', status_tanggal, $2 FROM lulus`,
+		UsiaLulusBulan, fmt.Sprintf("Otomatis: usia %d bulan", UsiaLulusBulan))
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/nadhifhafizp/api/db"
 	"github.com/nadhifhafizp/api/handlers"
+	"github.com/nadhifhafizp/api/jobs"
 )
 
 func main() {
@@ -17,6 +18,9 @@ func main() {
 	dbpool := db.ConnectDB()
 	defer dbpool.Close()
 
+	// --- Jadwal Otomatis ---
+	go jobs.JalankanKelulusanOtomatis(dbpool, 24*time.Hour)
+
 	// --- Setup Gin Router ---
 	router := gin.Default()
 
@@ -33,6 +37,12 @@ func main() {
 	router.POST("/api/login", handlers.LoginHandler(dbpool))
 	router.GET("/api/anak", handlers.GetAnakHandler(dbpool))
 	router.GET("/api/anak/:id", handlers.GetAnakByIdHandler(dbpool))
+	router.GET("/api/anak/:id/growth", handlers.GetPertumbuhanAnakHandler(dbpool))
+	router.GET("/api/anak/:id/chart.svg", handlers.GetGrafikAnakSVGHandler(dbpool))
+	router.GET("/api/anak/:id/chart.png", handlers.GetGrafikAnakPNGHandler(dbpool))
+	router.GET("/api/anak/:id/jadwal-imunisasi", handlers.GetJadwalImunisasiAnakHandler(dbpool))
+	router.GET("/api/anak/:id/status-idl", handlers.GetStatusIDLAnakHandler(dbpool))
+	router.GET("/api/anak/:id/sertifikat-idl.pdf", handlers.GetSertifikatIDLHandler(dbpool))
 	router.GET("/api/perkembangan", handlers.GetPerkembanganHandler(dbpool))
 	router.GET("/api/riwayat-imunisasi", handlers.GetRiwayatImunisasiHandler(dbpool))
 
@@ -46,6 +56,7 @@ func main() {
 		authenticated.PUT("/kader/:id", handlers.UpdateKaderHandler(dbpool))
 		authenticated.PUT("/kader/:id/password", handlers.ChangePasswordHandler(dbpool))
 		authenticated.DELETE("/kader/:id", handlers.DeleteKaderHandler(dbpool))
+		authenticated.PUT("/kader/:id/peran", handlers.RequirePeran(dbpool, "admin"), handlers.UbahPeranKaderHandler(dbpool))
 
 		// Ibu Routes
 		authenticated.POST("/ibu", handlers.TambahIbuHandler(dbpool))
@@ -60,12 +71,37 @@ func main() {
 		authenticated.GET("/anak/simple", handlers.GetAnakSimpleHandler(dbpool))
 		authenticated.PUT("/anak/:id", handlers.UpdateAnakHandler(dbpool))
 		authenticated.DELETE("/anak/:id", handlers.DeleteAnakHandler(dbpool))
+		authenticated.PUT("/anak/:id/status", handlers.UbahStatusAnakHandler(dbpool))
+		authenticated.GET("/anak/:id/riwayat-status", handlers.GetRiwayatStatusAnakHandler(dbpool))
 
 		// Perkembangan Routes
 		authenticated.POST("/perkembangan", handlers.TambahPerkembanganHandler(dbpool))
 		authenticated.GET("/perkembangan/:id", handlers.GetPerkembanganByIdHandler(dbpool))
 		authenticated.PUT("/perkembangan/:id", handlers.UpdatePerkembanganHandler(dbpool))
 		authenticated.DELETE("/perkembangan/:id", handlers.DeletePerkembanganHandler(dbpool))
+		authenticated.GET("/perkembangan/:id/saran", handlers.GetSaranPerkembanganHandler(dbpool))
+
+		// Aturan Saran Routes
+		authenticated.GET("/aturan-saran", handlers.GetAturanSaranHandler(dbpool))
+		authenticated.GET("/aturan-saran/:id", handlers.GetAturanSaranByIdHandler(dbpool))
+		authenticated.POST("/aturan-saran", handlers.RequirePeran(dbpool, "admin"), handlers.TambahAturanSaranHandler(dbpool))
+		authenticated.PUT("/aturan-saran/:id", handlers.RequirePeran(dbpool, "admin"), handlers.UpdateAturanSaranHandler(dbpool))
+		authenticated.DELETE("/aturan-saran/:id", handlers.RequirePeran(dbpool, "admin"), handlers.DeleteAturanSaranHandler(dbpool))
+
+		// Kehamilan Routes
+		authenticated.POST("/kehamilan", handlers.TambahKehamilanHandler(dbpool))
+		authenticated.GET("/kehamilan", handlers.GetKehamilanHandler(dbpool))
+		authenticated.GET("/kehamilan/:id", handlers.GetKehamilanByIdHandler(dbpool))
+		authenticated.PUT("/kehamilan/:id", handlers.UpdateKehamilanHandler(dbpool))
+		authenticated.DELETE("/kehamilan/:id", handlers.DeleteKehamilanHandler(dbpool))
+		authenticated.POST("/kehamilan/:id/persalinan", handlers.TambahPersalinanHandler(dbpool))
+
+		// Kunjungan ANC Routes
+		authenticated.POST("/kunjungan-anc", handlers.TambahKunjunganANCHandler(dbpool))
+		authenticated.GET("/kunjungan-anc", handlers.GetKunjunganANCHandler(dbpool))
+		authenticated.GET("/kunjungan-anc/:id", handlers.GetKunjunganANCByIdHandler(dbpool))
+		authenticated.PUT("/kunjungan-anc/:id", handlers.UpdateKunjunganANCHandler(dbpool))
+		authenticated.DELETE("/kunjungan-anc/:id", handlers.DeleteKunjunganANCHandler(dbpool))
 
 		// Master Imunisasi Routes
 		authenticated.POST("/master-imunisasi", handlers.TambahMasterImunisasiHandler(dbpool))
@@ -81,8 +117,78 @@ func main() {
 		authenticated.PUT("/riwayat-imunisasi/:id", handlers.UpdateRiwayatImunisasiHandler(dbpool))
 		authenticated.DELETE("/riwayat-imunisasi/:id", handlers.DeleteRiwayatImunisasiHandler(dbpool))
 
+		// Jadwal Imunisasi Routes
+		authenticated.GET("/imunisasi/jatuh-tempo", handlers.GetJatuhTempoImunisasiHandler(dbpool))
+		authenticated.GET("/imunisasi/defaulter", handlers.GetDefaulterImunisasiHandler(dbpool))
+		authenticated.GET("/anak/:id/kejar-imunisasi", handlers.GetKejarImunisasiAnakHandler(dbpool))
+		authenticated.POST("/anak/:id/pelacakan-imunisasi", handlers.TambahPelacakanImunisasiHandler(dbpool))
+		authenticated.GET("/anak/:id/pelacakan-imunisasi", handlers.GetPelacakanImunisasiHandler(dbpool))
+
+		// Versi Jadwal Imunisasi Routes
+		authenticated.GET("/jadwal-imunisasi/versi", handlers.GetVersiJadwalImunisasiHandler(dbpool))
+		authenticated.GET("/jadwal-imunisasi/versi/:id", handlers.GetVersiJadwalImunisasiByIdHandler(dbpool))
+		authenticated.POST("/jadwal-imunisasi/versi", handlers.RequirePeran(dbpool, "admin"), handlers.TerbitkanVersiJadwalImunisasiHandler(dbpool))
+		authenticated.DELETE("/jadwal-imunisasi/versi/:id", handlers.RequirePeran(dbpool, "admin"), handlers.DeleteVersiJadwalImunisasiHandler(dbpool))
+
+		// Sasaran Imunisasi Routes
+		authenticated.GET("/sasaran-imunisasi", handlers.GetSasaranImunisasiHandler(dbpool))
+		authenticated.PUT("/sasaran-imunisasi", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.SimpanSasaranImunisasiHandler(dbpool))
+
+		// Stok Vaksin Routes
+		authenticated.GET("/vaksin/batch", handlers.GetBatchVaksinHandler(dbpool))
+		authenticated.GET("/vaksin/batch/:id/transaksi", handlers.GetTransaksiVaksinHandler(dbpool))
+		authenticated.POST("/vaksin/penerimaan", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.TambahPenerimaanVaksinHandler(dbpool))
+		authenticated.POST("/vaksin/batch/:id/transaksi", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.TambahTransaksiVaksinHandler(dbpool))
+		authenticated.PUT("/vaksin/batch/:id/vvm", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.UbahVVMBatchVaksinHandler(dbpool))
+
+		// Rantai Dingin Routes
+		authenticated.GET("/rantai-dingin/alat", handlers.GetAlatRantaiDinginHandler(dbpool))
+		authenticated.POST("/rantai-dingin/alat", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.TambahAlatRantaiDinginHandler(dbpool))
+		authenticated.PUT("/rantai-dingin/alat/:id", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.UpdateAlatRantaiDinginHandler(dbpool))
+		authenticated.GET("/rantai-dingin/alat/:id/suhu", handlers.GetSuhuAlatHandler(dbpool))
+		authenticated.POST("/rantai-dingin/alat/:id/suhu", handlers.TambahSuhuAlatHandler(dbpool))
+		authenticated.POST("/rantai-dingin/alat/:id/suhu/unggah", handlers.UnggahSuhuAlatHandler(dbpool))
+		authenticated.GET("/rantai-dingin/ekskursi", handlers.GetEkskursiSuhuHandler(dbpool))
+		authenticated.GET("/rantai-dingin/ekskursi/:id", handlers.GetEkskursiSuhuByIdHandler(dbpool))
+		authenticated.PUT("/rantai-dingin/ekskursi/:id/tinjau", handlers.RequirePeran(dbpool, "bidan", "admin"), handlers.TinjauEkskursiSuhuHandler(dbpool))
+
+		// KIPI Routes
+		authenticated.POST("/kipi", handlers.TambahKipiHandler(dbpool))
+		authenticated.GET("/kipi", handlers.GetKipiHandler(dbpool))
+		authenticated.GET("/kipi/:id", handlers.GetKipiByIdHandler(dbpool))
+		authenticated.PUT("/kipi/:id", handlers.UpdateKipiHandler(dbpool))
+		authenticated.DELETE("/kipi/:id", handlers.DeleteKipiHandler(dbpool))
+
+		// Notifikasi Routes
+		authenticated.GET("/notifikasi", handlers.GetNotifikasiHandler(dbpool))
+		authenticated.PUT("/notifikasi/baca-semua", handlers.BacaSemuaNotifikasiHandler(dbpool))
+		authenticated.PUT("/notifikasi/:id/baca", handlers.BacaNotifikasiHandler(dbpool))
+
 		// Laporan Route
 		authenticated.GET("/laporan/:tipe", handlers.GetLaporanHandler(dbpool))
+
+		// Peta Routes
+		authenticated.GET("/geo/anak.geojson", handlers.GetGeoAnakHandler(dbpool))
+
+		// Posyandu Routes
+		authenticated.POST("/posyandu", handlers.TambahPosyanduHandler(dbpool))
+		authenticated.GET("/posyandu", handlers.GetPosyanduHandler(dbpool))
+		authenticated.PUT("/posyandu/:id", handlers.UpdatePosyanduHandler(dbpool))
+
+		// Mutasi Routes
+		authenticated.POST("/mutasi", handlers.AjukanMutasiHandler(dbpool))
+		authenticated.GET("/mutasi", handlers.GetMutasiHandler(dbpool))
+		authenticated.PUT("/mutasi/:id/terima", handlers.TerimaMutasiHandler(dbpool))
+		authenticated.PUT("/mutasi/:id/tolak", handlers.TolakMutasiHandler(dbpool))
+		authenticated.PUT("/mutasi/:id/batal", handlers.BatalkanMutasiHandler(dbpool))
+
+		// Kasus Gizi Routes
+		authenticated.POST("/kasus-gizi", handlers.BukaKasusGiziHandler(dbpool))
+		authenticated.GET("/kasus-gizi", handlers.GetKasusGiziHandler(dbpool))
+		authenticated.GET("/kasus-gizi/terlambat", handlers.GetKasusGiziTerlambatHandler(dbpool))
+		authenticated.GET("/kasus-gizi/:id", handlers.GetKasusGiziByIdHandler(dbpool))
+		authenticated.POST("/kasus-gizi/:id/tindak-lanjut", handlers.TambahTindakLanjutKasusGiziHandler(dbpool))
+		authenticated.PUT("/kasus-gizi/:id/tutup", handlers.TutupKasusGiziHandler(dbpool))
 	}
 
 	// --- Jalankan Server ---
@@ -9,14 +9,17 @@ import (
 
 // --- Structs untuk Kader ---
 type Kader struct {
-	ID          int        `json:"id"`
-	NamaLengkap string     `json:"nama_lengkap"`
-	NIK         *string    `json:"nik"`
-	NoTelepon   *string    `json:"no_telepon"`
-	Password    string     `json:"-"` // Jangan kirim password ke frontend
-	Username    string     `json:"username"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   *time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	NamaLengkap  string     `json:"nama_lengkap"`
+	NIK          *string    `json:"nik"`
+	NoTelepon    *string    `json:"no_telepon"`
+	Password     string     `json:"-"` // Jangan kirim password ke frontend
+	Username     string     `json:"username"`
+	IdPosyandu   *int       `json:"id_posyandu"`
+	NamaPosyandu *string    `json:"nama_posyandu,omitempty"`
+	Peran        string     `json:"peran"` // kader, bidan, admin
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
 }
 type LoginPayload struct {
 	Username string `json:"username" binding:"required"`
@@ -28,12 +31,17 @@ type RegisterKaderPayload struct {
 	NoTelepon   string `json:"no_telepon"`
 	Username    string `json:"username" binding:"required"`
 	Password    string `json:"password" binding:"required"`
+	IdPosyandu  *int   `json:"id_posyandu"`
 }
 type UpdateKaderPayload struct {
 	NamaLengkap string `json:"nama_lengkap" binding:"required"`
 	NIK         string `json:"nik"`
 	NoTelepon   string `json:"no_telepon"`
 	Username    string `json:"username" binding:"required"`
+	IdPosyandu  *int   `json:"id_posyandu"`
+}
+type UbahPeranKaderPayload struct {
+	Peran string `json:"peran" binding:"required,oneof=kader bidan admin"`
 }
 type ChangePasswordPayload struct {
 	NewPassword string `json:"new_password" binding:"required"`
@@ -41,16 +49,20 @@ type ChangePasswordPayload struct {
 
 // --- Structs untuk Ibu ---
 type TambahIbuPayload struct {
-	NamaLengkap string `json:"nama_lengkap" binding:"required"`
-	NIK         string `json:"nik" binding:"required"`
-	NoTelepon   string `json:"no_telepon" binding:"required"`
-	Alamat      string `json:"alamat" binding:"required"`
+	NamaLengkap string   `json:"nama_lengkap" binding:"required"`
+	NIK         string   `json:"nik" binding:"required"`
+	NoTelepon   string   `json:"no_telepon" binding:"required"`
+	Alamat      string   `json:"alamat" binding:"required"`
+	Latitude    *float64 `json:"latitude"`  // Opsional, koordinat rumah
+	Longitude   *float64 `json:"longitude"` // Opsional, koordinat rumah
 }
 type UpdateIbuPayload struct {
-	NamaLengkap string `json:"nama_lengkap" binding:"required"`
-	NIK         string `json:"nik" binding:"required"`
-	NoTelepon   string `json:"no_telepon" binding:"required"`
-	Alamat      string `json:"alamat" binding:"required"`
+	NamaLengkap string   `json:"nama_lengkap" binding:"required"`
+	NIK         string   `json:"nik" binding:"required"`
+	NoTelepon   string   `json:"no_telepon" binding:"required"`
+	Alamat      string   `json:"alamat" binding:"required"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
 }
 type Ibu struct {
 	ID               int        `json:"id"`
@@ -58,6 +70,8 @@ type Ibu struct {
 	NIK              *string    `json:"nik"`
 	NoTelepon        *string    `json:"no_telepon"`
 	Alamat           *string    `json:"alamat"`
+	Latitude         *float64   `json:"latitude"`
+	Longitude        *float64   `json:"longitude"`
 	IdKaderPendaftar *int       `json:"id_kader_pendaftar,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        *time.Time `json:"updated_at"`
@@ -78,100 +92,484 @@ type Anak struct {
 	AnakKe        *int       `json:"anak_ke"`
 	BeratLahirKg  *float64   `json:"berat_lahir_kg"`
 	TinggiLahirCm *float64   `json:"tinggi_lahir_cm"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     *time.Time `json:"updated_at"`
-	NamaIbu       *string    `json:"nama_ibu,omitempty"`
-	NikIbu        *string    `json:"nik_ibu,omitempty"`
+	Status        string     `json:"status"` // aktif, pindah, meninggal, lulus
+	StatusTanggal *time.Time `json:"status_tanggal"`
+	StatusAlasan  *string    `json:"status_alasan"`
+	IdPosyandu    *int       `json:"id_posyandu"` // Posyandu pemilik data saat ini
+	NamaPosyandu  *string    `json:"nama_posyandu,omitempty"`
+	PunyaKms      bool       `json:"punya_kms"`
+	// Usia kehamilan saat lahir; bayi < 37 minggu dinilai dengan umur koreksi sampai 24 bulan
+	UsiaKehamilanLahirMinggu *int       `json:"usia_kehamilan_lahir_minggu"`
+	UsiaHari                 int        `json:"usia_hari"`         // Umur kronologis hari ini
+	UsiaKoreksiHari          *int       `json:"usia_koreksi_hari"` // Kosong jika koreksi tidak berlaku
+	CreatedAt                time.Time  `json:"created_at"`
+	UpdatedAt                *time.Time `json:"updated_at"`
+	NamaIbu                  *string    `json:"nama_ibu,omitempty"`
+	NikIbu                   *string    `json:"nik_ibu,omitempty"`
 }
 type TambahAnakPayload struct {
-	IdIbu         int      `json:"id_ibu" binding:"required"`
-	NamaAnak      string   `json:"nama_anak" binding:"required"`
-	NikAnak       *string  `json:"nik_anak"`
-	TanggalLahir  string   `json:"tanggal_lahir" binding:"required"` // Terima YYYY-MM-DD
-	JenisKelamin  string   `json:"jenis_kelamin" binding:"required,oneof=L P"`
-	AnakKe        *int     `json:"anak_ke"`
-	BeratLahirKg  *float64 `json:"berat_lahir_kg"`
-	TinggiLahirCm *float64 `json:"tinggi_lahir_cm"`
+	IdIbu                    int      `json:"id_ibu" binding:"required"`
+	NamaAnak                 string   `json:"nama_anak" binding:"required"`
+	NikAnak                  *string  `json:"nik_anak"`
+	TanggalLahir             string   `json:"tanggal_lahir" binding:"required"` // Terima YYYY-MM-DD
+	JenisKelamin             string   `json:"jenis_kelamin" binding:"required,oneof=L P"`
+	AnakKe                   *int     `json:"anak_ke"`
+	BeratLahirKg             *float64 `json:"berat_lahir_kg"`
+	TinggiLahirCm            *float64 `json:"tinggi_lahir_cm"`
+	PunyaKms                 *bool    `json:"punya_kms"` // Default: true
+	UsiaKehamilanLahirMinggu *int     `json:"usia_kehamilan_lahir_minggu" binding:"omitempty,min=22,max=44"`
 }
 type UpdateAnakPayload struct {
-	IdIbu         int      `json:"id_ibu" binding:"required"`
-	NamaAnak      string   `json:"nama_anak" binding:"required"`
-	NikAnak       *string  `json:"nik_anak"`
-	TanggalLahir  string   `json:"tanggal_lahir" binding:"required"` // Terima YYYY-MM-DD
-	JenisKelamin  string   `json:"jenis_kelamin" binding:"required,oneof=L P"`
-	AnakKe        *int     `json:"anak_ke"`
-	BeratLahirKg  *float64 `json:"berat_lahir_kg"`
-	TinggiLahirCm *float64 `json:"tinggi_lahir_cm"`
+	IdIbu                    int      `json:"id_ibu" binding:"required"`
+	NamaAnak                 string   `json:"nama_anak" binding:"required"`
+	NikAnak                  *string  `json:"nik_anak"`
+	TanggalLahir             string   `json:"tanggal_lahir" binding:"required"` // Terima YYYY-MM-DD
+	JenisKelamin             string   `json:"jenis_kelamin" binding:"required,oneof=L P"`
+	AnakKe                   *int     `json:"anak_ke"`
+	BeratLahirKg             *float64 `json:"berat_lahir_kg"`
+	TinggiLahirCm            *float64 `json:"tinggi_lahir_cm"`
+	PunyaKms                 *bool    `json:"punya_kms"` // Default: true
+	UsiaKehamilanLahirMinggu *int     `json:"usia_kehamilan_lahir_minggu" binding:"omitempty,min=22,max=44"`
 }
 type AnakSimple struct {
 	ID       int     `json:"id"`
 	NamaAnak string  `json:"nama_anak"`
 	NikAnak  *string `json:"nik_anak"`
 }
+type UbahStatusAnakPayload struct {
+	Status         string  `json:"status" binding:"required,oneof=aktif pindah meninggal lulus"`
+	TanggalBerlaku string  `json:"tanggal_berlaku" binding:"required"` // Terima YYYY-MM-DD
+	Alasan         *string `json:"alasan"`
+}
+type RiwayatStatusAnak struct {
+	ID             int       `json:"id"`
+	IdAnak         int       `json:"id_anak"`
+	StatusLama     string    `json:"status_lama"`
+	StatusBaru     string    `json:"status_baru"`
+	TanggalBerlaku time.Time `json:"tanggal_berlaku"`
+	Alasan         *string   `json:"alasan"`
+	IdKader        *int      `json:"id_kader"` // Kosong jika diubah otomatis oleh sistem
+	NamaKader      *string   `json:"nama_kader,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
 
 // --- Structs untuk Perkembangan ---
 type Perkembangan struct {
 	ID                 int        `json:"id"`
 	IdAnak             int        `json:"id_anak"`
 	TanggalPemeriksaan time.Time  `json:"tanggal_pemeriksaan"`
+	UsiaHari           int        `json:"usia_hari"`         // Umur kronologis saat pemeriksaan
+	UsiaKoreksiHari    *int       `json:"usia_koreksi_hari"` // Umur koreksi prematur yang dipakai untuk z-score, jika berlaku
 	BbKg               *float64   `json:"bb_kg"`
 	TbCm               *float64   `json:"tb_cm"`
+	CaraUkur           *string    `json:"cara_ukur"`     // telentang atau berdiri
+	TbCmStandar        *float64   `json:"tb_cm_standar"` // Panjang/tinggi setelah koreksi cara ukur, dipakai untuk z-score
 	LkCm               *float64   `json:"lk_cm"`
 	LlCm               *float64   `json:"ll_cm"`
 	StatusGizi         *string    `json:"status_gizi"`
+	ZsBbu              *float64   `json:"zs_bbu"`   // Z-score WHO: BB/U
+	ZsTbu              *float64   `json:"zs_tbu"`   // Z-score WHO: PB/U atau TB/U
+	ZsBbtb             *float64   `json:"zs_bbtb"`  // Z-score WHO: BB/PB atau BB/TB; null sampai tabel resmi WHO disertakan
+	ZsImtu             *float64   `json:"zs_imtu"`  // Z-score WHO: IMT/U
+	ZsLku              *float64   `json:"zs_lku"`   // Z-score WHO: LK/U
+	ZsLilau            *float64   `json:"zs_lilau"` // Z-score WHO: LILA/U (3-60 bulan); null sampai tabel resmi WHO disertakan
+	StatusBbu          *string    `json:"status_bbu"`
+	StatusTbu          *string    `json:"status_tbu"`
+	StatusBbtb         *string    `json:"status_bbtb"`
+	StatusImtu         *string    `json:"status_imtu"`
+	StatusLku          *string    `json:"status_lku"`
+	KmsStatus          *string    `json:"kms_status"` // N, T, O, B (NULL jika tidak ditimbang)
+	Kms2T              bool       `json:"kms_2t"`
+	KmsBgm             bool       `json:"kms_bgm"`
+	KenaikanBbGram     *int       `json:"kenaikan_bb_gram"`
+	KbmGram            *int       `json:"kbm_gram"`
+	StatusAsi          *string    `json:"status_asi"` // eksklusif, parsial, tidak
+	Peringatan         []string   `json:"peringatan"` // Peringatan kewajaran yang telah dikonfirmasi kader
+	AlasanKonfirmasi   *string    `json:"alasan_konfirmasi"`
 	Saran              *string    `json:"saran"`
 	IdKaderPencatat    int        `json:"id_kader_pencatat"`
+	IdPosyandu         *int       `json:"id_posyandu"` // Posyandu tempat data dicatat
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          *time.Time `json:"updated_at"`
 	NamaAnak           string     `json:"nama_anak,omitempty"`
 	NamaKader          *string    `json:"nama_kader,omitempty"`
 	NikAnak            *string    `json:"nik_anak,omitempty"`
 	NamaIbu            *string    `json:"nama_ibu,omitempty"`
+	NamaPosyandu       *string    `json:"nama_posyandu,omitempty"`
 }
 type TambahPerkembanganPayload struct {
 	IdAnak             int      `json:"id_anak" binding:"required"`
 	TanggalPemeriksaan string   `json:"tanggal_pemeriksaan" binding:"required"` // Terima YYYY-MM-DD
 	BbKg               *float64 `json:"bb_kg"`
 	TbCm               *float64 `json:"tb_cm"`
+	CaraUkur           *string  `json:"cara_ukur" binding:"omitempty,oneof=telentang berdiri"` // Kosong: dianggap sesuai umur
 	LkCm               *float64 `json:"lk_cm"`
 	LlCm               *float64 `json:"ll_cm"`
-	StatusGizi         *string  `json:"status_gizi"`
+	StatusGizi         *string  `json:"status_gizi"` // Dipakai hanya jika status tidak dapat dihitung dari pengukuran
 	Saran              *string  `json:"saran"`
+	StatusAsi          *string  `json:"status_asi" binding:"omitempty,oneof=eksklusif parsial tidak"`
+	// Wajib diisi jika pengukuran menghasilkan peringatan kewajaran
+	KonfirmasiPeringatan bool    `json:"konfirmasi_peringatan"`
+	AlasanKonfirmasi     *string `json:"alasan_konfirmasi"`
 }
 type UpdatePerkembanganPayload struct {
 	IdAnak             int      `json:"id_anak" binding:"required"`
 	TanggalPemeriksaan string   `json:"tanggal_pemeriksaan" binding:"required"` // Terima YYYY-MM-DD
 	BbKg               *float64 `json:"bb_kg"`
 	TbCm               *float64 `json:"tb_cm"`
+	CaraUkur           *string  `json:"cara_ukur" binding:"omitempty,oneof=telentang berdiri"` // Kosong: dianggap sesuai umur
 	LkCm               *float64 `json:"lk_cm"`
 	LlCm               *float64 `json:"ll_cm"`
-	StatusGizi         *string  `json:"status_gizi"`
+	StatusGizi         *string  `json:"status_gizi"` // Dipakai hanya jika status tidak dapat dihitung dari pengukuran
 	Saran              *string  `json:"saran"`
+	StatusAsi          *string  `json:"status_asi" binding:"omitempty,oneof=eksklusif parsial tidak"`
+	// Wajib diisi jika pengukuran menghasilkan peringatan kewajaran
+	KonfirmasiPeringatan bool    `json:"konfirmasi_peringatan"`
+	AlasanKonfirmasi     *string `json:"alasan_konfirmasi"`
+}
+
+// --- Structs untuk Analisis Pertumbuhan ---
+type TitikPertumbuhan struct {
+	IdPerkembangan     int       `json:"id_perkembangan"`
+	TanggalPemeriksaan time.Time `json:"tanggal_pemeriksaan"`
+	UsiaHari           int       `json:"usia_hari"` // Umur kronologis
+	UsiaBulan          float64   `json:"usia_bulan"`
+	UsiaKoreksiHari    *int      `json:"usia_koreksi_hari"` // Umur koreksi prematur yang dipakai untuk penilaian, jika berlaku
+	UsiaKoreksiBulan   *float64  `json:"usia_koreksi_bulan"`
+	BbKg               *float64  `json:"bb_kg"`
+	TbCm               *float64  `json:"tb_cm"` // Sudah dikoreksi sesuai cara ukur standar WHO
+	LkCm               *float64  `json:"lk_cm"`
+	ZsBBU              *float64  `json:"zs_bbu"`
+	ZsTBU              *float64  `json:"zs_tbu"`
+	ZsBBTB             *float64  `json:"zs_bbtb"`
+	ZsIMTU             *float64  `json:"zs_imtu"`
+	ZsLKU              *float64  `json:"zs_lku"`
+	StatusGizi         *string   `json:"status_gizi"`
+	// Kecepatan sejak pengukuran sebelumnya, dinormalkan ke per bulan
+	KecepatanBbGramBulan *float64 `json:"kecepatan_bb_gram_bulan"`
+	KecepatanTbCmBulan   *float64 `json:"kecepatan_tb_cm_bulan"`
+}
+type FalteringPertumbuhan struct {
+	Indikator      string    `json:"indikator"` // bbu, tbu
+	IdPerkembangan int       `json:"id_perkembangan"`
+	TanggalMulai   time.Time `json:"tanggal_mulai"`
+	TanggalPuncak  time.Time `json:"tanggal_puncak"`
+	ZPuncak        float64   `json:"z_puncak"`
+	Z              float64   `json:"z"`
+	Penurunan      float64   `json:"penurunan"`
+}
+type PertumbuhanAnak struct {
+	IdAnak                   int                    `json:"id_anak"`
+	NamaAnak                 string                 `json:"nama_anak"`
+	JenisKelamin             string                 `json:"jenis_kelamin"`
+	TanggalLahir             time.Time              `json:"tanggal_lahir"`
+	UsiaKehamilanLahirMinggu *int                   `json:"usia_kehamilan_lahir_minggu"`
+	Titik                    []TitikPertumbuhan     `json:"titik"`
+	Faltering                []FalteringPertumbuhan `json:"faltering"`
+}
+
+// --- Structs untuk Kasus Gizi (Stunting & Wasting) ---
+type KasusGizi struct {
+	ID                     int                     `json:"id"`
+	IdAnak                 int                     `json:"id_anak"`
+	Jenis                  string                  `json:"jenis"`   // stunting, wasting
+	Tingkat                string                  `json:"tingkat"` // sedang, berat
+	Sumber                 string                  `json:"sumber"`  // otomatis, manual
+	IdPerkembanganDeteksi  *int                    `json:"id_perkembangan_deteksi"`
+	TanggalDeteksi         time.Time               `json:"tanggal_deteksi"`
+	ZScoreDeteksi          *float64                `json:"z_score_deteksi"`
+	Status                 string                  `json:"status"` // terbuka, ditutup
+	Dirujuk                bool                    `json:"dirujuk"`
+	TanggalRujukan         *time.Time              `json:"tanggal_rujukan"`
+	TempatRujukan          *string                 `json:"tempat_rujukan"`
+	TanggalTindakLanjut    time.Time               `json:"tanggal_tindak_lanjut"` // Jatuh tempo tindak lanjut berikutnya
+	IdKaderPenanggungJawab *int                    `json:"id_kader_penanggung_jawab"`
+	Catatan                *string                 `json:"catatan"`
+	HasilPenutupan         *string                 `json:"hasil_penutupan"`
+	TanggalPenutupan       *time.Time              `json:"tanggal_penutupan"`
+	CatatanPenutupan       *string                 `json:"catatan_penutupan"`
+	CreatedAt              time.Time               `json:"created_at"`
+	UpdatedAt              *time.Time              `json:"updated_at"`
+	NamaAnak               string                  `json:"nama_anak,omitempty"`
+	NamaKader              *string                 `json:"nama_kader,omitempty"`
+	HariTerlambat          *int                    `json:"hari_terlambat,omitempty"`
+	TindakLanjut           []TindakLanjutKasusGizi `json:"tindak_lanjut,omitempty"` // Hanya diisi pada detail
+}
+type BukaKasusGiziPayload struct {
+	IdAnak                 int     `json:"id_anak" binding:"required"`
+	Jenis                  string  `json:"jenis" binding:"required,oneof=stunting wasting"`
+	Tingkat                *string `json:"tingkat" binding:"omitempty,oneof=sedang berat"`
+	TanggalDeteksi         *string `json:"tanggal_deteksi"`       // YYYY-MM-DD, default hari ini
+	TanggalTindakLanjut    *string `json:"tanggal_tindak_lanjut"` // YYYY-MM-DD, default sesuai jenis kasus
+	IdKaderPenanggungJawab *int    `json:"id_kader_penanggung_jawab"`
+	Catatan                *string `json:"catatan"`
+}
+type TindakLanjutKasusGizi struct {
+	ID        int       `json:"id"`
+	IdKasus   int       `json:"id_kasus"`
+	Tanggal   time.Time `json:"tanggal"`
+	Jenis     string    `json:"jenis"` // rujukan, pmt, konseling, kunjungan_rumah, lainnya
+	Catatan   *string   `json:"catatan"`
+	IdKader   *int      `json:"id_kader"`
+	NamaKader *string   `json:"nama_kader,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type TambahTindakLanjutPayload struct {
+	Tanggal             string  `json:"tanggal" binding:"required"` // YYYY-MM-DD
+	Jenis               string  `json:"jenis" binding:"required,oneof=rujukan pmt konseling kunjungan_rumah lainnya"`
+	Catatan             *string `json:"catatan"`
+	TempatRujukan       *string `json:"tempat_rujukan"`        // Untuk jenis rujukan
+	TanggalTindakLanjut *string `json:"tanggal_tindak_lanjut"` // Jatuh tempo berikutnya, default sesuai jenis kasus
+}
+type TutupKasusGiziPayload struct {
+	Hasil   string  `json:"hasil" binding:"required,oneof=pulih membaik tidak_membaik pindah meninggal lainnya"`
+	Tanggal *string `json:"tanggal"` // YYYY-MM-DD, default hari ini
+	Catatan *string `json:"catatan"`
+}
+type KasusTerlambatKader struct {
+	IdKader   *int        `json:"id_kader"`
+	NamaKader *string     `json:"nama_kader"`
+	Jumlah    int         `json:"jumlah"`
+	Kasus     []KasusGizi `json:"kasus"`
+}
+
+// --- Structs untuk Aturan Saran ---
+// Kondisi yang kosong (null) tidak membatasi; semua kondisi yang terisi harus cocok
+type AturanSaran struct {
+	ID                  int        `json:"id"`
+	Nama                string     `json:"nama"`
+	Prioritas           int        `json:"prioritas"` // Kecil lebih dulu ditampilkan
+	Aktif               bool       `json:"aktif"`
+	UsiaMinBulan        *int       `json:"usia_min_bulan"` // Inklusif
+	UsiaMaxBulan        *int       `json:"usia_max_bulan"` // Eksklusif
+	Indikator           *string    `json:"indikator"`      // bbu, tbu, imtu, lku (bbtb menunggu tabel resmi WHO)
+	Status              []string   `json:"status"`         // Kategori indikator, mis. ["Pendek", "Sangat pendek"]
+	KmsStatus           []string   `json:"kms_status"`     // N, T, O, B
+	Kms2T               *bool      `json:"kms_2t"`
+	ImunisasiTertunggak *bool      `json:"imunisasi_tertunggak"`
+	StatusAsi           []string   `json:"status_asi"` // eksklusif, parsial, tidak
+	TeksSaran           string     `json:"teks_saran"` // Boleh memuat {nama_anak}, {usia_bulan}, {imunisasi_tertunggak}
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           *time.Time `json:"updated_at"`
+}
+type AturanSaranPayload struct {
+	Nama                string   `json:"nama" binding:"required"`
+	Prioritas           *int     `json:"prioritas"` // Default 100
+	Aktif               *bool    `json:"aktif"`     // Default true
+	UsiaMinBulan        *int     `json:"usia_min_bulan" binding:"omitempty,min=0"`
+	UsiaMaxBulan        *int     `json:"usia_max_bulan" binding:"omitempty,min=1"`
+	Indikator           *string  `json:"indikator" binding:"omitempty,oneof=bbu tbu imtu lku"`
+	Status              []string `json:"status"`
+	KmsStatus           []string `json:"kms_status" binding:"omitempty,dive,oneof=N T O B"`
+	Kms2T               *bool    `json:"kms_2t"`
+	ImunisasiTertunggak *bool    `json:"imunisasi_tertunggak"`
+	StatusAsi           []string `json:"status_asi" binding:"omitempty,dive,oneof=eksklusif parsial tidak"`
+	TeksSaran           string   `json:"teks_saran" binding:"required"`
+}
+type SaranUsulan struct {
+	Saran               string   `json:"saran"`
+	IdAturan            []int    `json:"id_aturan"`
+	ImunisasiTertunggak []string `json:"imunisasi_tertunggak"`
+}
+
+// --- Structs untuk Posyandu & Mutasi ---
+type Posyandu struct {
+	ID        int        `json:"id"`
+	Nama      string     `json:"nama"`
+	Desa      *string    `json:"desa"`
+	Kecamatan *string    `json:"kecamatan"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+type PosyanduPayload struct {
+	Nama      string  `json:"nama" binding:"required"`
+	Desa      *string `json:"desa"`
+	Kecamatan *string `json:"kecamatan"`
+}
+type MutasiAnak struct {
+	ID                 int        `json:"id"`
+	IdAnak             int        `json:"id_anak"`
+	IdPosyanduAsal     int        `json:"id_posyandu_asal"`
+	IdPosyanduTujuan   int        `json:"id_posyandu_tujuan"`
+	Status             string     `json:"status"` // diajukan, diterima, ditolak, dibatalkan
+	Alasan             *string    `json:"alasan"`
+	CatatanKeputusan   *string    `json:"catatan_keputusan"`
+	IdKaderPengaju     int        `json:"id_kader_pengaju"`
+	IdKaderPemutus     *int       `json:"id_kader_pemutus"`
+	TanggalPengajuan   time.Time  `json:"tanggal_pengajuan"`
+	TanggalKeputusan   *time.Time `json:"tanggal_keputusan"`
+	NamaAnak           string     `json:"nama_anak,omitempty"`
+	NamaPosyanduAsal   string     `json:"nama_posyandu_asal,omitempty"`
+	NamaPosyanduTujuan string     `json:"nama_posyandu_tujuan,omitempty"`
+	NamaKaderPengaju   *string    `json:"nama_kader_pengaju,omitempty"`
+	NamaKaderPemutus   *string    `json:"nama_kader_pemutus,omitempty"`
+}
+type AjukanMutasiPayload struct {
+	IdAnak           int     `json:"id_anak" binding:"required"`
+	IdPosyanduTujuan int     `json:"id_posyandu_tujuan" binding:"required"`
+	Alasan           *string `json:"alasan"`
+}
+type KeputusanMutasiPayload struct {
+	Catatan *string `json:"catatan"`
+}
+
+// --- Structs untuk Kehamilan & Kunjungan ANC ---
+type Kehamilan struct {
+	ID                  int            `json:"id"`
+	IdIbu               int            `json:"id_ibu"`
+	HPHT                time.Time      `json:"hpht"`
+	HPL                 time.Time      `json:"hpl"`
+	Gravida             int            `json:"gravida"`
+	Para                int            `json:"para"`
+	Abortus             int            `json:"abortus"`
+	TinggiBadanCm       *float64       `json:"tinggi_badan_cm"`
+	Status              string         `json:"status"`
+	Catatan             *string        `json:"catatan"`
+	IdKaderPencatat     *int           `json:"id_kader_pencatat"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           *time.Time     `json:"updated_at"`
+	NamaIbu             *string        `json:"nama_ibu,omitempty"`
+	NamaKader           *string        `json:"nama_kader,omitempty"`
+	UsiaKehamilanMinggu *int           `json:"usia_kehamilan_minggu"` // Dihitung dari HPHT, hanya untuk kehamilan aktif
+	FaktorRisiko        []string       `json:"faktor_risiko"`
+	Kunjungan           []KunjunganANC `json:"kunjungan,omitempty"`  // Hanya diisi pada detail
+	Persalinan          *Persalinan    `json:"persalinan,omitempty"` // Hanya diisi pada detail
+}
+type TambahKehamilanPayload struct {
+	IdIbu         int      `json:"id_ibu" binding:"required"`
+	HPHT          string   `json:"hpht" binding:"required"` // Terima YYYY-MM-DD
+	Gravida       int      `json:"gravida" binding:"required,min=1"`
+	Para          int      `json:"para" binding:"min=0"`
+	Abortus       int      `json:"abortus" binding:"min=0"`
+	TinggiBadanCm *float64 `json:"tinggi_badan_cm"`
+	Catatan       *string  `json:"catatan"`
+}
+type UpdateKehamilanPayload struct {
+	IdIbu         int      `json:"id_ibu" binding:"required"`
+	HPHT          string   `json:"hpht" binding:"required"` // Terima YYYY-MM-DD
+	Gravida       int      `json:"gravida" binding:"required,min=1"`
+	Para          int      `json:"para" binding:"min=0"`
+	Abortus       int      `json:"abortus" binding:"min=0"`
+	TinggiBadanCm *float64 `json:"tinggi_badan_cm"`
+	Status        string   `json:"status" binding:"required,oneof=aktif selesai"`
+	Catatan       *string  `json:"catatan"`
+}
+type KunjunganANC struct {
+	ID                    int        `json:"id"`
+	IdKehamilan           int        `json:"id_kehamilan"`
+	TanggalKunjungan      time.Time  `json:"tanggal_kunjungan"`
+	BbKg                  *float64   `json:"bb_kg"`
+	LilaCm                *float64   `json:"lila_cm"`
+	TekananDarahSistolik  *int       `json:"tekanan_darah_sistolik"`
+	TekananDarahDiastolik *int       `json:"tekanan_darah_diastolik"`
+	TabletFe              *int       `json:"tablet_fe"`
+	Catatan               *string    `json:"catatan"`
+	IdKaderPencatat       *int       `json:"id_kader_pencatat"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             *time.Time `json:"updated_at"`
+	NamaIbu               *string    `json:"nama_ibu,omitempty"`
+	NamaKader             *string    `json:"nama_kader,omitempty"`
+	UsiaKehamilanMinggu   int        `json:"usia_kehamilan_minggu"` // Usia kehamilan saat kunjungan
+	FaktorRisiko          []string   `json:"faktor_risiko"`
+}
+type TambahKunjunganANCPayload struct {
+	IdKehamilan           int      `json:"id_kehamilan" binding:"required"`
+	TanggalKunjungan      string   `json:"tanggal_kunjungan" binding:"required"` // Terima YYYY-MM-DD
+	BbKg                  *float64 `json:"bb_kg"`
+	LilaCm                *float64 `json:"lila_cm"`
+	TekananDarahSistolik  *int     `json:"tekanan_darah_sistolik"`
+	TekananDarahDiastolik *int     `json:"tekanan_darah_diastolik"`
+	TabletFe              *int     `json:"tablet_fe"`
+	Catatan               *string  `json:"catatan"`
+}
+type UpdateKunjunganANCPayload struct {
+	IdKehamilan           int      `json:"id_kehamilan" binding:"required"`
+	TanggalKunjungan      string   `json:"tanggal_kunjungan" binding:"required"` // Terima YYYY-MM-DD
+	BbKg                  *float64 `json:"bb_kg"`
+	LilaCm                *float64 `json:"lila_cm"`
+	TekananDarahSistolik  *int     `json:"tekanan_darah_sistolik"`
+	TekananDarahDiastolik *int     `json:"tekanan_darah_diastolik"`
+	TabletFe              *int     `json:"tablet_fe"`
+	Catatan               *string  `json:"catatan"`
+}
+
+// --- Structs untuk Persalinan ---
+type Persalinan struct {
+	ID                  int       `json:"id"`
+	IdKehamilan         int       `json:"id_kehamilan"`
+	TanggalPersalinan   time.Time `json:"tanggal_persalinan"`
+	Tempat              string    `json:"tempat"`
+	Penolong            string    `json:"penolong"`
+	UsiaKehamilanMinggu int       `json:"usia_kehamilan_minggu"`
+	Hasil               string    `json:"hasil"`
+	JumlahBayi          int       `json:"jumlah_bayi"`
+	Catatan             *string   `json:"catatan"`
+	IdKaderPencatat     *int      `json:"id_kader_pencatat"`
+	CreatedAt           time.Time `json:"created_at"`
+	IdAnak              []int     `json:"id_anak"` // Anak yang didaftarkan dari persalinan ini
+}
+type TambahPersalinanPayload struct {
+	TanggalPersalinan   string                `json:"tanggal_persalinan" binding:"required"` // Terima YYYY-MM-DD
+	Tempat              string                `json:"tempat" binding:"required,oneof=rumah_sakit puskesmas klinik polindes rumah lainnya"`
+	Penolong            string                `json:"penolong" binding:"required,oneof=dokter bidan perawat dukun lainnya"`
+	UsiaKehamilanMinggu *int                  `json:"usia_kehamilan_minggu" binding:"omitempty,min=1,max=45"` // Opsional, dihitung dari HPHT jika kosong
+	Hasil               string                `json:"hasil" binding:"required,oneof=lahir_hidup lahir_mati keguguran"`
+	Bayi                []PersalinanBayiInput `json:"bayi" binding:"dive"`
+	Catatan             *string               `json:"catatan"`
+}
+type PersalinanBayiInput struct {
+	NamaAnak      string   `json:"nama_anak" binding:"required"`
+	NikAnak       *string  `json:"nik_anak"`
+	JenisKelamin  string   `json:"jenis_kelamin" binding:"required,oneof=L P"`
+	BeratLahirKg  *float64 `json:"berat_lahir_kg"`
+	TinggiLahirCm *float64 `json:"tinggi_lahir_cm"`
+	LahirHidup    *bool    `json:"lahir_hidup"` // Default true; bayi lahir mati tidak didaftarkan sebagai anak
 }
 
 // --- Structs Master Imunisasi ---
+// Setiap baris adalah satu dosis; dosis sebuah seri berbagi nama seri dengan dosis_ke berurutan
 type MasterImunisasi struct {
-	ID             int        `json:"id"`
-	NamaImunisasi  string     `json:"nama_imunisasi"`
-	UsiaIdealBulan int        `json:"usia_ideal_bulan"`
-	Deskripsi      *string    `json:"deskripsi"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      *time.Time `json:"updated_at"`
+	ID              int        `json:"id"`
+	NamaImunisasi   string     `json:"nama_imunisasi"`
+	UsiaIdealBulan  int        `json:"usia_ideal_bulan"`
+	Seri            *string    `json:"seri"` // Mis. "DPT-HB-Hib"; null untuk antigen dosis tunggal
+	DosisKe         int        `json:"dosis_ke"`
+	UsiaMinHari     *int       `json:"usia_min_hari"`
+	UsiaMaxHari     *int       `json:"usia_max_hari"`
+	IntervalMinHari *int       `json:"interval_min_hari"` // Jarak minimal dari dosis sebelumnya pada seri yang sama
+	TermasukIdl     bool       `json:"termasuk_idl"`      // Bagian dari Imunisasi Dasar Lengkap
+	Deskripsi       *string    `json:"deskripsi"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
 }
 type TambahMasterImunisasiPayload struct {
-	NamaImunisasi  string  `json:"nama_imunisasi" binding:"required"`
-	UsiaIdealBulan int     `json:"usia_ideal_bulan"`
-	Deskripsi      *string `json:"deskripsi"`
+	NamaImunisasi   string  `json:"nama_imunisasi" binding:"required"`
+	UsiaIdealBulan  int     `json:"usia_ideal_bulan"`
+	Seri            *string `json:"seri"`
+	DosisKe         *int    `json:"dosis_ke" binding:"omitempty,min=1"` // Default 1
+	UsiaMinHari     *int    `json:"usia_min_hari" binding:"omitempty,min=0"`
+	UsiaMaxHari     *int    `json:"usia_max_hari" binding:"omitempty,min=0"`
+	IntervalMinHari *int    `json:"interval_min_hari" binding:"omitempty,min=1"`
+	TermasukIdl     *bool   `json:"termasuk_idl"` // Kosong: false untuk data baru, tidak berubah saat update
+	Deskripsi       *string `json:"deskripsi"`
 }
 type UpdateMasterImunisasiPayload struct {
-	NamaImunisasi  string  `json:"nama_imunisasi" binding:"required"`
-	UsiaIdealBulan int     `json:"usia_ideal_bulan"`
-	Deskripsi      *string `json:"deskripsi"`
+	NamaImunisasi   string  `json:"nama_imunisasi" binding:"required"`
+	UsiaIdealBulan  int     `json:"usia_ideal_bulan"`
+	Seri            *string `json:"seri"`
+	DosisKe         *int    `json:"dosis_ke" binding:"omitempty,min=1"` // Default 1
+	UsiaMinHari     *int    `json:"usia_min_hari" binding:"omitempty,min=0"`
+	UsiaMaxHari     *int    `json:"usia_max_hari" binding:"omitempty,min=0"`
+	IntervalMinHari *int    `json:"interval_min_hari" binding:"omitempty,min=1"`
+	TermasukIdl     *bool   `json:"termasuk_idl"` // Kosong: false untuk data baru, tidak berubah saat update
+	Deskripsi       *string `json:"deskripsi"`
 }
 type MasterImunisasiSimple struct {
-	ID             int    `json:"id"`
-	NamaImunisasi  string `json:"nama_imunisasi"`
-	UsiaIdealBulan int    `json:"usia_ideal_bulan"`
+	ID             int     `json:"id"`
+	NamaImunisasi  string  `json:"nama_imunisasi"`
+	UsiaIdealBulan int     `json:"usia_ideal_bulan"`
+	Seri           *string `json:"seri"`
+	DosisKe        int     `json:"dosis_ke"`
 }
 
 // --- Structs Riwayat Imunisasi ---
@@ -183,6 +581,10 @@ type RiwayatImunisasi struct {
 	IdKaderUpdater    *int       `json:"id_kader_updater"`
 	TanggalDiberikan  time.Time  `json:"tanggal_imunisasi"`
 	Catatan           *string    `json:"catatan"`
+	Peringatan        []string   `json:"peringatan"` // Peringatan dosis yang telah dikonfirmasi kader
+	AlasanKonfirmasi  *string    `json:"alasan_konfirmasi"`
+	IdPosyandu        *int       `json:"id_posyandu"` // Posyandu tempat imunisasi dicatat
+	IdBatchVaksin     *int       `json:"id_batch_vaksin"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         *time.Time `json:"updated_at"`
 
@@ -191,18 +593,492 @@ type RiwayatImunisasi struct {
 	NamaImunisasi    string  `json:"nama_imunisasi,omitempty"`
 	NamaKader        *string `json:"nama_kader,omitempty"`
 	NamaKaderUpdater *string `json:"nama_kader_updater,omitempty"`
+	NamaPosyandu     *string `json:"nama_posyandu,omitempty"`
+	NomorBatch       *string `json:"nomor_batch,omitempty"`
 }
 type TambahRiwayatPayload struct {
 	IdAnak            int     `json:"id_anak" binding:"required"`
 	IdMasterImunisasi int     `json:"id_master_imunisasi" binding:"required"`
 	TanggalDiberikan  string  `json:"tanggal_imunisasi" binding:"required"` // Terima YYYY-MM-DD
 	Catatan           *string `json:"catatan"`
+	IdBatchVaksin     *int    `json:"id_batch_vaksin"` // Opsional; stok batch berkurang satu dosis
+	// Wajib diisi jika dosis menghasilkan peringatan (terlalu dini, terlalu dekat, dll.)
+	KonfirmasiPeringatan bool    `json:"konfirmasi_peringatan"`
+	AlasanKonfirmasi     *string `json:"alasan_konfirmasi"`
 }
 type UpdateRiwayatPayload struct {
 	IdAnak            int     `json:"id_anak" binding:"required"`
 	IdMasterImunisasi int     `json:"id_master_imunisasi" binding:"required"`
 	TanggalDiberikan  string  `json:"tanggal_imunisasi" binding:"required"` // Terima YYYY-MM-DD
 	Catatan           *string `json:"catatan"`
+	IdBatchVaksin     *int    `json:"id_batch_vaksin"` // Opsional; stok batch berkurang satu dosis
+	// Wajib diisi jika dosis menghasilkan peringatan (terlalu dini, terlalu dekat, dll.)
+	KonfirmasiPeringatan bool    `json:"konfirmasi_peringatan"`
+	AlasanKonfirmasi     *string `json:"alasan_konfirmasi"`
+}
+
+// --- Structs untuk Versi Jadwal Imunisasi ---
+type DosisJadwal struct {
+	IdMasterImunisasi int     `json:"id_master_imunisasi"`
+	NamaImunisasi     string  `json:"nama_imunisasi"`
+	Seri              *string `json:"seri"`
+	DosisKe           int     `json:"dosis_ke"`
+	UsiaIdealBulan    int     `json:"usia_ideal_bulan"`
+	UsiaMinHari       *int    `json:"usia_min_hari"`
+	UsiaMaxHari       *int    `json:"usia_max_hari"`
+	IntervalMinHari   *int    `json:"interval_min_hari"`
+	TermasukIdl       bool    `json:"termasuk_idl"`
+}
+type VersiJadwalImunisasi struct {
+	ID                int           `json:"id"`
+	Nama              string        `json:"nama"`
+	BerlakuMulai      time.Time     `json:"berlaku_mulai"` // Untuk anak yang lahir pada atau sesudah tanggal ini
+	Keterangan        *string       `json:"keterangan"`
+	IdKaderPenerbit   *int          `json:"id_kader_penerbit"`
+	NamaKaderPenerbit *string       `json:"nama_kader_penerbit,omitempty"`
+	CreatedAt         time.Time     `json:"created_at"`
+	JumlahDosis       int           `json:"jumlah_dosis"`
+	Berlaku           bool          `json:"berlaku"`         // Versi bagi bayi yang lahir hari ini
+	Dosis             []DosisJadwal `json:"dosis,omitempty"` // Hanya diisi pada detail
+}
+
+// DosisJadwalPayload adalah satu dosis pada impor jadwal; dicocokkan dengan master imunisasi
+// berdasarkan seri dan dosis ke-, atau nama imunisasi
+type DosisJadwalPayload struct {
+	NamaImunisasi   string  `json:"nama_imunisasi" binding:"required"`
+	Seri            *string `json:"seri"`
+	DosisKe         *int    `json:"dosis_ke" binding:"omitempty,min=1"` // Default 1
+	UsiaIdealBulan  int     `json:"usia_ideal_bulan" binding:"min=0"`
+	UsiaMinHari     *int    `json:"usia_min_hari" binding:"omitempty,min=0"`
+	UsiaMaxHari     *int    `json:"usia_max_hari" binding:"omitempty,min=0"`
+	IntervalMinHari *int    `json:"interval_min_hari" binding:"omitempty,min=1"`
+	TermasukIdl     bool    `json:"termasuk_idl"`
+	Deskripsi       *string `json:"deskripsi"`
+}
+
+// TerbitkanVersiJadwalPayload menerbitkan versi jadwal. Tanpa dosis, seluruh master imunisasi
+// saat ini dibekukan; dengan dosis (impor), master diperbarui lalu hanya dosis tersebut yang masuk versi.
+type TerbitkanVersiJadwalPayload struct {
+	Nama         string               `json:"nama" binding:"required"`
+	BerlakuMulai string               `json:"berlaku_mulai" binding:"required"` // YYYY-MM-DD
+	Keterangan   *string              `json:"keterangan"`
+	Dosis        []DosisJadwalPayload `json:"dosis" binding:"omitempty,dive"`
+}
+
+// --- Structs untuk Jadwal Imunisasi ---
+type JadwalImunisasi struct {
+	IdMasterImunisasi int        `json:"id_master_imunisasi"`
+	NamaImunisasi     string     `json:"nama_imunisasi"`
+	UsiaIdealBulan    int        `json:"usia_ideal_bulan"`
+	Seri              *string    `json:"seri"`
+	DosisKe           int        `json:"dosis_ke"`
+	TanggalJadwal     time.Time  `json:"tanggal_jadwal"`
+	Status            string     `json:"status"` // selesai, jatuh_tempo, akan_datang, terlambat
+	TanggalImunisasi  *time.Time `json:"tanggal_imunisasi"`
+	HariTerlambat     *int       `json:"hari_terlambat"`
+}
+type JadwalImunisasiAnak struct {
+	IdAnak          int               `json:"id_anak"`
+	NamaAnak        string            `json:"nama_anak"`
+	TanggalLahir    time.Time         `json:"tanggal_lahir"`
+	TanggalAcuan    time.Time         `json:"tanggal_acuan"`
+	IdVersiJadwal   int               `json:"id_versi_jadwal"` // Versi jadwal yang berlaku bagi kohort kelahiran anak
+	NamaVersiJadwal string            `json:"nama_versi_jadwal"`
+	Jumlah          map[string]int    `json:"jumlah"` // Jumlah antigen per status
+	Jadwal          []JadwalImunisasi `json:"jadwal"`
+}
+type DosisKejarImunisasi struct {
+	IdMasterImunisasi int        `json:"id_master_imunisasi"`
+	NamaImunisasi     string     `json:"nama_imunisasi"`
+	Seri              *string    `json:"seri"`
+	DosisKe           int        `json:"dosis_ke"`
+	Status            string     `json:"status"` // diberikan, bisa_diberikan, menunggu, tidak_diindikasikan
+	TanggalImunisasi  *time.Time `json:"tanggal_imunisasi"`
+	TanggalTercepat   *time.Time `json:"tanggal_tercepat"` // Tanggal paling awal dosis boleh diberikan
+	BatasTanggal      *time.Time `json:"batas_tanggal"`    // Tanggal terakhir sesuai umur maksimal
+	Alasan            *string    `json:"alasan"`           // Alasan dosis tidak diindikasikan lagi
+}
+type KejarImunisasiAnak struct {
+	IdAnak          int                   `json:"id_anak"`
+	NamaAnak        string                `json:"nama_anak"`
+	TanggalLahir    time.Time             `json:"tanggal_lahir"`
+	TanggalAcuan    time.Time             `json:"tanggal_acuan"`
+	UsiaHari        int                   `json:"usia_hari"`
+	IdVersiJadwal   int                   `json:"id_versi_jadwal"`
+	NamaVersiJadwal string                `json:"nama_versi_jadwal"`
+	Jumlah          map[string]int        `json:"jumlah"` // Jumlah dosis per status
+	Dosis           []DosisKejarImunisasi `json:"dosis"`  // Urut tanggal tercepat; dosis yang sudah atau tidak lagi diberikan di akhir
+}
+type AnakJatuhTempoImunisasi struct {
+	IdAnak       int               `json:"id_anak"`
+	NamaAnak     string            `json:"nama_anak"`
+	NikAnak      *string           `json:"nik_anak"`
+	TanggalLahir time.Time         `json:"tanggal_lahir"`
+	NamaIbu      string            `json:"nama_ibu"`
+	IdPosyandu   *int              `json:"id_posyandu"`
+	NamaPosyandu *string           `json:"nama_posyandu"`
+	Imunisasi    []JadwalImunisasi `json:"imunisasi"` // Hanya antigen jatuh tempo atau terlambat
+}
+type JatuhTempoImunisasi struct {
+	TanggalMulai   time.Time                 `json:"tanggal_mulai"`
+	TanggalSelesai time.Time                 `json:"tanggal_selesai"`
+	JumlahAnak     int                       `json:"jumlah_anak"`
+	Anak           []AnakJatuhTempoImunisasi `json:"anak"`
+}
+
+// --- Structs untuk Pelacakan Defaulter Imunisasi ---
+type PelacakanImunisasi struct {
+	ID                  int       `json:"id"`
+	IdAnak              int       `json:"id_anak"`
+	Tanggal             time.Time `json:"tanggal"`
+	Hasil               string    `json:"hasil"` // ditelepon, dikunjungi, menolak, pindah
+	ImunisasiTertunggak []string  `json:"imunisasi_tertunggak"`
+	Catatan             *string   `json:"catatan"`
+	IdKader             *int      `json:"id_kader"`
+	NamaKader           *string   `json:"nama_kader"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+type TambahPelacakanPayload struct {
+	Tanggal *string `json:"tanggal"` // YYYY-MM-DD, default hari ini
+	Hasil   string  `json:"hasil" binding:"required,oneof=ditelepon dikunjungi menolak pindah"`
+	Catatan *string `json:"catatan"`
+}
+type AnakDefaulterImunisasi struct {
+	IdAnak            int                 `json:"id_anak"`
+	NamaAnak          string              `json:"nama_anak"`
+	NikAnak           *string             `json:"nik_anak"`
+	TanggalLahir      time.Time           `json:"tanggal_lahir"`
+	NamaIbu           string              `json:"nama_ibu"`
+	NoTeleponIbu      *string             `json:"no_telepon_ibu"`
+	IdPosyandu        *int                `json:"id_posyandu"`
+	NamaPosyandu      *string             `json:"nama_posyandu"`
+	HariTerlambat     int                 `json:"hari_terlambat"`   // Dosis tertunggak paling lama
+	Imunisasi         []JadwalImunisasi   `json:"imunisasi"`        // Dosis yang tertunggak
+	JumlahPelacakan   int                 `json:"jumlah_pelacakan"` // Sejak dosis tertua jatuh jadwal
+	PelacakanTerakhir *PelacakanImunisasi `json:"pelacakan_terakhir"`
+}
+type DefaulterImunisasi struct {
+	TanggalAcuan time.Time                `json:"tanggal_acuan"`
+	TenggangHari int                      `json:"tenggang_hari"`
+	JumlahAnak   int                      `json:"jumlah_anak"`
+	Anak         []AnakDefaulterImunisasi `json:"anak"`
+}
+
+// --- Structs untuk Imunisasi Dasar Lengkap ---
+type StatusIDLAnak struct {
+	IdAnak           int                     `json:"id_anak"`
+	NamaAnak         string                  `json:"nama_anak"`
+	TanggalLahir     time.Time               `json:"tanggal_lahir"`
+	Status           string                  `json:"status"` // lengkap_tepat_waktu, lengkap, belum_lengkap
+	TanggalLengkap   *time.Time              `json:"tanggal_lengkap"`
+	UsiaLengkapBulan *int                    `json:"usia_lengkap_bulan"` // Umur (bulan penuh) saat IDL tercapai
+	Diberikan        []JadwalImunisasi       `json:"diberikan"`
+	BelumDiberikan   []MasterImunisasiSimple `json:"belum_diberikan"`
+}
+type IndikatorIDL struct {
+	Sasaran         int      `json:"sasaran"`           // Anak yang genap berumur 12 bulan pada bulan laporan
+	SasaranIDL      int      `json:"sasaran_idl"`       // Sasaran yang IDL sebelum umur 12 bulan
+	Cakupan         *float64 `json:"cakupan"`           // Persen SasaranIDL terhadap Sasaran
+	Lengkap         int      `json:"lengkap"`           // Anak yang mencapai IDL pada bulan laporan
+	TepatWaktu      int      `json:"tepat_waktu"`       // Bagian dari Lengkap sebelum umur 12 bulan
+	TidakTepatWaktu int      `json:"tidak_tepat_waktu"` // Bagian dari Lengkap pada umur 12 bulan atau lebih
+}
+type AnakIDL struct {
+	IdAnak         int       `json:"id_anak"`
+	NamaAnak       string    `json:"nama_anak"`
+	TanggalLahir   time.Time `json:"tanggal_lahir"`
+	Status         string    `json:"status"`
+	TanggalLengkap time.Time `json:"tanggal_lengkap"`
+}
+type IDLPosyandu struct {
+	IdPosyandu   *int    `json:"id_posyandu"`
+	NamaPosyandu *string `json:"nama_posyandu"`
+	IndikatorIDL
+	Anak []AnakIDL `json:"anak"` // Anak yang mencapai IDL pada bulan laporan
+}
+type LaporanIDL struct {
+	Bulan    string        `json:"bulan"` // YYYY-MM
+	Total    IndikatorIDL  `json:"total"`
+	Posyandu []IDLPosyandu `json:"posyandu"`
+}
+
+// --- Structs untuk Cakupan Imunisasi ---
+type SasaranImunisasi struct {
+	ID            int        `json:"id"`
+	Tahun         int        `json:"tahun"`
+	IdPosyandu    int        `json:"id_posyandu"`
+	NamaPosyandu  string     `json:"nama_posyandu"`
+	SasaranBayi   int        `json:"sasaran_bayi"`
+	SasaranBaduta int        `json:"sasaran_baduta"`
+	IdKader       *int       `json:"id_kader"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
+type SimpanSasaranImunisasiPayload struct {
+	Tahun         int `json:"tahun" binding:"required,min=2000"`
+	IdPosyandu    int `json:"id_posyandu" binding:"required"`
+	SasaranBayi   int `json:"sasaran_bayi" binding:"min=0"`
+	SasaranBaduta int `json:"sasaran_baduta" binding:"min=0"`
+}
+type CakupanAntigen struct {
+	IdMasterImunisasi int          `json:"id_master_imunisasi"`
+	NamaImunisasi     string       `json:"nama_imunisasi"`
+	Sasaran           int          `json:"sasaran"`   // Sasaran bayi, atau baduta untuk imunisasi lanjutan
+	Kumulatif         [12]int      `json:"kumulatif"` // Dosis diberikan sejak Januari sampai akhir tiap bulan
+	Cakupan           [12]*float64 `json:"cakupan"`   // Persen kumulatif terhadap sasaran per bulan
+}
+type CakupanWilayah struct {
+	SasaranBayi     int              `json:"sasaran_bayi"`
+	SasaranBaduta   int              `json:"sasaran_baduta"`
+	SumberSasaran   string           `json:"sumber_sasaran"` // target, terdaftar, campuran
+	Antigen         []CakupanAntigen `json:"antigen"`
+	IDL             int              `json:"idl"`                // Anak yang mencapai IDL pada tahun laporan
+	CakupanIDL      *float64         `json:"cakupan_idl"`        // Persen IDL terhadap sasaran bayi
+	DropOutDPT1DPT3 *float64         `json:"drop_out_dpt1_dpt3"` // Persen (DPT1 - DPT3) / DPT1
+	DropOutDPT1MR   *float64         `json:"drop_out_dpt1_mr"`   // Persen (DPT1 - MR1) / DPT1
+}
+type CakupanPosyandu struct {
+	IdPosyandu   *int    `json:"id_posyandu"`
+	NamaPosyandu *string `json:"nama_posyandu"`
+	CakupanWilayah
+}
+type CakupanDesa struct {
+	Desa *string `json:"desa"`
+	UCI  bool    `json:"uci"` // Cakupan IDL desa mencapai target UCI
+	CakupanWilayah
+	Posyandu []CakupanPosyandu `json:"posyandu"`
+}
+type LaporanCakupanImunisasi struct {
+	Tahun     int            `json:"tahun"`
+	TargetUCI float64        `json:"target_uci"` // Persen minimal cakupan IDL desa
+	Total     CakupanWilayah `json:"total"`
+	DesaUCI   int            `json:"desa_uci"`
+	Desa      []CakupanDesa  `json:"desa"`
+}
+
+// --- Structs untuk Stok Vaksin ---
+type BatchVaksin struct {
+	ID                    int        `json:"id"`
+	IdPosyandu            *int       `json:"id_posyandu"` // NULL = gudang puskesmas
+	NamaPosyandu          *string    `json:"nama_posyandu"`
+	NamaVaksin            string     `json:"nama_vaksin"` // Seri atau nama imunisasi pada master
+	NomorBatch            string     `json:"nomor_batch"`
+	Produsen              *string    `json:"produsen"`
+	TanggalKedaluwarsa    time.Time  `json:"tanggal_kedaluwarsa"`
+	DosisPerVial          int        `json:"dosis_per_vial"`
+	StatusVVM             string     `json:"status_vvm"` // A, B (boleh dipakai), C, D (jangan dipakai)
+	StokDosis             int        `json:"stok_dosis"`
+	HariMenujuKedaluwarsa int        `json:"hari_menuju_kedaluwarsa"`
+	StatusKedaluwarsa     string     `json:"status_kedaluwarsa"` // layak, hampir_kedaluwarsa, kedaluwarsa
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             *time.Time `json:"updated_at"`
+}
+type PenerimaanVaksinPayload struct {
+	IdPosyandu         *int    `json:"id_posyandu"`
+	NamaVaksin         string  `json:"nama_vaksin" binding:"required"`
+	NomorBatch         string  `json:"nomor_batch" binding:"required"`
+	Produsen           *string `json:"produsen"`
+	TanggalKedaluwarsa string  `json:"tanggal_kedaluwarsa" binding:"required"` // YYYY-MM-DD
+	DosisPerVial       int     `json:"dosis_per_vial" binding:"required,min=1"`
+	JumlahVial         int     `json:"jumlah_vial" binding:"required,min=1"`
+	Tanggal            string  `json:"tanggal" binding:"required"` // Tanggal diterima, YYYY-MM-DD
+	StatusVVM          string  `json:"status_vvm" binding:"omitempty,oneof=A B C D"`
+	Catatan            *string `json:"catatan"`
+}
+type UbahVVMPayload struct {
+	StatusVVM string `json:"status_vvm" binding:"required,oneof=A B C D"`
+}
+type TransaksiVaksin struct {
+	ID                 int       `json:"id"`
+	IdBatch            int       `json:"id_batch"`
+	Jenis              string    `json:"jenis"` // penerimaan, pemakaian, buka_vial, terbuang, penyesuaian
+	Tanggal            time.Time `json:"tanggal"`
+	JumlahDosis        int       `json:"jumlah_dosis"` // Bertanda: positif menambah stok
+	JumlahVial         *int      `json:"jumlah_vial"`
+	Alasan             *string   `json:"alasan"`
+	IdRiwayatImunisasi *int      `json:"id_riwayat_imunisasi"`
+	IdKader            *int      `json:"id_kader"`
+	NamaKader          *string   `json:"nama_kader"`
+	Catatan            *string   `json:"catatan"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+type TambahTransaksiVaksinPayload struct {
+	Jenis   string `json:"jenis" binding:"required,oneof=buka_vial terbuang penyesuaian"`
+	Tanggal string `json:"tanggal" binding:"required"` // YYYY-MM-DD
+	// buka_vial: jumlah_vial wajib. terbuang: jumlah_dosis (positif) dan alasan wajib.
+	// penyesuaian: jumlah_dosis bertanda hasil stok opname.
+	JumlahVial  *int    `json:"jumlah_vial" binding:"omitempty,min=1"`
+	JumlahDosis *int    `json:"jumlah_dosis"`
+	Alasan      *string `json:"alasan" binding:"omitempty,oneof=sisa_vial kedaluwarsa vvm rusak hilang lainnya"`
+	Catatan     *string `json:"catatan"`
+}
+type MutasiStokVaksin struct {
+	StokAwal       int      `json:"stok_awal"`
+	Diterima       int      `json:"diterima"`
+	Dipakai        int      `json:"dipakai"`
+	Terbuang       int      `json:"terbuang"`
+	Penyesuaian    int      `json:"penyesuaian"` // Bertanda
+	StokAkhir      int      `json:"stok_akhir"`
+	VialDibuka     int      `json:"vial_dibuka"`
+	PersenTerbuang *float64 `json:"persen_terbuang"` // Terbuang terhadap (dipakai + terbuang)
+}
+type StokBatchBulanan struct {
+	IdBatch            int       `json:"id_batch"`
+	NamaVaksin         string    `json:"nama_vaksin"`
+	NomorBatch         string    `json:"nomor_batch"`
+	TanggalKedaluwarsa time.Time `json:"tanggal_kedaluwarsa"`
+	StatusVVM          string    `json:"status_vvm"`
+	MutasiStokVaksin
+}
+type RekapStokVaksin struct {
+	NamaVaksin string `json:"nama_vaksin"`
+	MutasiStokVaksin
+}
+type StokVaksinPosyandu struct {
+	IdPosyandu   *int               `json:"id_posyandu"` // NULL = gudang puskesmas
+	NamaPosyandu *string            `json:"nama_posyandu"`
+	Batch        []StokBatchBulanan `json:"batch"`
+}
+type LaporanStokVaksin struct {
+	Bulan    string               `json:"bulan"` // YYYY-MM
+	Vaksin   []RekapStokVaksin    `json:"vaksin"`
+	Posyandu []StokVaksinPosyandu `json:"posyandu"`
+}
+
+// --- Structs untuk KIPI (Kejadian Ikutan Pasca Imunisasi) ---
+type Kipi struct {
+	ID                 int        `json:"id"`
+	IdRiwayatImunisasi int        `json:"id_riwayat_imunisasi"`
+	Gejala             []string   `json:"gejala"`
+	WaktuMulai         time.Time  `json:"waktu_mulai"`
+	Keparahan          string     `json:"keparahan"` // ringan, sedang, berat
+	Hasil              string     `json:"hasil"`     // sembuh, dalam_perawatan, rawat_inap, gejala_sisa, meninggal, belum_diketahui
+	Serius             bool       `json:"serius"`
+	Tindakan           *string    `json:"tindakan"`
+	Dirujuk            bool       `json:"dirujuk"`
+	TanggalRujukan     *time.Time `json:"tanggal_rujukan"`
+	TempatRujukan      *string    `json:"tempat_rujukan"`
+	IdKaderPelapor     *int       `json:"id_kader_pelapor"`
+	IdKaderUpdater     *int       `json:"id_kader_updater"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          *time.Time `json:"updated_at"`
+
+	IdAnak            int       `json:"id_anak"`
+	NamaAnak          string    `json:"nama_anak"`
+	IdMasterImunisasi int       `json:"id_master_imunisasi"`
+	NamaImunisasi     string    `json:"nama_imunisasi"`
+	TanggalImunisasi  time.Time `json:"tanggal_imunisasi"`
+	IdBatchVaksin     *int      `json:"id_batch_vaksin"`
+	NomorBatch        *string   `json:"nomor_batch"`
+	NamaKaderPelapor  *string   `json:"nama_kader_pelapor,omitempty"`
+}
+type KipiPayload struct {
+	IdRiwayatImunisasi int      `json:"id_riwayat_imunisasi" binding:"required"`
+	Gejala             []string `json:"gejala" binding:"required,min=1,dive,required"`
+	WaktuMulai         string   `json:"waktu_mulai" binding:"required"` // YYYY-MM-DDTHH:MM
+	Keparahan          string   `json:"keparahan" binding:"required,oneof=ringan sedang berat"`
+	Hasil              string   `json:"hasil" binding:"omitempty,oneof=sembuh dalam_perawatan rawat_inap gejala_sisa meninggal belum_diketahui"`
+	Tindakan           *string  `json:"tindakan"`
+	Dirujuk            bool     `json:"dirujuk"`
+	TanggalRujukan     *string  `json:"tanggal_rujukan"` // YYYY-MM-DD
+	TempatRujukan      *string  `json:"tempat_rujukan"`
+}
+
+// --- Structs untuk Rantai Dingin ---
+type AlatRantaiDingin struct {
+	ID            int        `json:"id"`
+	Nama          string     `json:"nama"`
+	Jenis         string     `json:"jenis"`       // lemari_es, freezer, cold_box, vaccine_carrier
+	IdPosyandu    *int       `json:"id_posyandu"` // NULL = gudang puskesmas
+	NamaPosyandu  *string    `json:"nama_posyandu"`
+	NomorSeri     *string    `json:"nomor_seri"`
+	SuhuMin       float64    `json:"suhu_min"`
+	SuhuMax       float64    `json:"suhu_max"`
+	Aktif         bool       `json:"aktif"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	BacaanHariIni int        `json:"bacaan_hari_ini"` // Pencatatan suhu hari ini; minimal dua (pagi dan sore)
+	SuhuTerakhir  *float64   `json:"suhu_terakhir"`
+	WaktuTerakhir *time.Time `json:"waktu_terakhir"`
+	EkskursiBaru  int        `json:"ekskursi_baru"` // Ekskursi yang belum ditinjau
+}
+type AlatRantaiDinginPayload struct {
+	Nama       string   `json:"nama" binding:"required"`
+	Jenis      string   `json:"jenis" binding:"required,oneof=lemari_es freezer cold_box vaccine_carrier"`
+	IdPosyandu *int     `json:"id_posyandu"`
+	NomorSeri  *string  `json:"nomor_seri"`
+	SuhuMin    *float64 `json:"suhu_min"` // Default 2
+	SuhuMax    *float64 `json:"suhu_max"` // Default 8
+	Aktif      *bool    `json:"aktif"`
+}
+type SuhuAlat struct {
+	ID        int       `json:"id"`
+	IdAlat    int       `json:"id_alat"`
+	Waktu     time.Time `json:"waktu"`
+	Suhu      float64   `json:"suhu"`
+	Sumber    string    `json:"sumber"` // manual, logger
+	IdKader   *int      `json:"id_kader"`
+	NamaKader *string   `json:"nama_kader,omitempty"`
+	Catatan   *string   `json:"catatan"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type TambahSuhuAlatPayload struct {
+	Waktu   string   `json:"waktu" binding:"required"` // YYYY-MM-DDTHH:MM
+	Suhu    *float64 `json:"suhu" binding:"required"`
+	Catatan *string  `json:"catatan"`
+}
+type BatchEkskursi struct {
+	IdBatchVaksin      int       `json:"id_batch_vaksin"`
+	NamaVaksin         string    `json:"nama_vaksin"`
+	NomorBatch         string    `json:"nomor_batch"`
+	TanggalKedaluwarsa time.Time `json:"tanggal_kedaluwarsa"`
+	StatusVVM          string    `json:"status_vvm"`
+	StokDosis          int       `json:"stok_dosis"`          // Stok awal ditambah penerimaan selama ekskursi
+	StokDosisSekarang  int       `json:"stok_dosis_sekarang"` // Stok yang masih dapat ditahan atau dibuang
+	DosisDiberikan     int       `json:"dosis_diberikan"`     // Dosis dari batch ini yang diberikan sejak ekskursi dimulai
+}
+type EkskursiSuhu struct {
+	ID               int             `json:"id"`
+	IdAlat           int             `json:"id_alat"`
+	NamaAlat         string          `json:"nama_alat"`
+	IdPosyandu       *int            `json:"id_posyandu"`
+	NamaPosyandu     *string         `json:"nama_posyandu"`
+	Jenis            string          `json:"jenis"` // panas, beku
+	Mulai            time.Time       `json:"mulai"`
+	Selesai          *time.Time      `json:"selesai"` // NULL selama suhu belum kembali normal
+	SuhuEkstrem      float64         `json:"suhu_ekstrem"`
+	JumlahBacaan     int             `json:"jumlah_bacaan"`
+	Status           string          `json:"status"` // baru, ditinjau
+	TindakLanjut     *string         `json:"tindak_lanjut"`
+	IdKaderPeninjau  *int            `json:"id_kader_peninjau"`
+	DitinjauAt       *time.Time      `json:"ditinjau_at"`
+	DigantikanOleh   *int            `json:"digantikan_oleh"` // Ekskursi yang menggabungkannya
+	DigantikanAt     *time.Time      `json:"digantikan_at"`   // Diisi bila tidak lagi terdeteksi; tetap disimpan sebagai jejak tinjauan
+	CreatedAt        time.Time       `json:"created_at"`
+	JumlahBatch      int             `json:"jumlah_batch"`
+	JumlahAlatLokasi int             `json:"jumlah_alat_lokasi"` // Alat rantai dingin di lokasi yang sama
+	KeteranganBatch  string          `json:"keterangan_batch"`   // Batch dihubungkan per lokasi, bukan per alat
+	Batch            []BatchEkskursi `json:"batch,omitempty"`    // Hanya diisi pada detail
+}
+type TinjauEkskursiPayload struct {
+	TindakLanjut string `json:"tindak_lanjut" binding:"required"`
+}
+type HasilUnggahSuhu struct {
+	Tersimpan    int      `json:"tersimpan"`
+	Duplikat     int      `json:"duplikat"` // Waktu bacaan sudah tercatat
+	Galat        []string `json:"galat"`    // Baris yang tidak dapat dibaca
+	EkskursiBaru int      `json:"ekskursi_baru"`
+}
+
+// --- Structs untuk Notifikasi ---
+type Notifikasi struct {
+	ID          int        `json:"id"`
+	Jenis       string     `json:"jenis"` // kipi_serius, ekskursi_suhu
+	Judul       string     `json:"judul"`
+	Pesan       string     `json:"pesan"`
+	IdReferensi *int       `json:"id_referensi"`
+	DibacaAt    *time.Time `json:"dibaca_at"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
 // --- Structs untuk Laporan ---
@@ -215,6 +1091,65 @@ type LaporanImunisasi struct {
 	RiwayatImunisasi // Embed
 }
 
+// IndikatorSKDN memuat jumlah S, K, D, N beserta rasionya (persen, nil jika penyebut nol)
+type IndikatorSKDN struct {
+	S    int      `json:"s"`   // Seluruh balita di wilayah
+	K    int      `json:"k"`   // Balita yang memiliki KMS
+	D    int      `json:"d"`   // Balita yang ditimbang bulan ini
+	N    int      `json:"n"`   // Balita yang naik berat badannya
+	T    int      `json:"t"`   // Ditimbang tetapi tidak naik
+	O    int      `json:"o"`   // Ditimbang, bulan lalu tidak ditimbang
+	B    int      `json:"b"`   // Baru pertama kali ditimbang
+	DuaT int      `json:"2t"`  // Tidak naik dua kali berturut-turut
+	BGM  int      `json:"bgm"` // Bawah garis merah
+	KS   *float64 `json:"k_s"`
+	DS   *float64 `json:"d_s"`
+	ND   *float64 `json:"n_d"`
+}
+type RincianSKDN struct {
+	JenisKelamin string `json:"jenis_kelamin"`
+	KelompokUmur string `json:"kelompok_umur"` // 0-5, 6-11, 12-23, 24-59 bulan
+	IndikatorSKDN
+}
+type SKDNPosyandu struct {
+	IdPosyandu   *int          `json:"id_posyandu"`
+	NamaPosyandu *string       `json:"nama_posyandu"`
+	Total        IndikatorSKDN `json:"total"`
+	Rincian      []RincianSKDN `json:"rincian"`
+}
+type LaporanSKDN struct {
+	Bulan    string         `json:"bulan"` // YYYY-MM
+	Total    IndikatorSKDN  `json:"total"`
+	Posyandu []SKDNPosyandu `json:"posyandu"`
+}
+
+// --- Structs untuk Peta (GeoJSON) ---
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"` // Selalu "FeatureCollection"
+	Features []GeoJSONFeature `json:"features"`
+}
+type GeoJSONFeature struct {
+	Type       string          `json:"type"` // Selalu "Feature"
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties GeoAnakProperti `json:"properties"`
+}
+type GeoJSONGeometry struct {
+	Type        string     `json:"type"`        // Selalu "Point"
+	Coordinates [2]float64 `json:"coordinates"` // Urutan GeoJSON: [longitude, latitude]
+}
+type GeoAnakProperti struct {
+	// Identitas dan tanggal lahir dikosongkan saat koordinat dibulatkan untuk dibagikan ke publik;
+	// sebagai gantinya hanya kelompok umur yang disertakan
+	IdAnak            *int       `json:"id_anak,omitempty"`
+	NamaAnak          *string    `json:"nama_anak,omitempty"`
+	JenisKelamin      string     `json:"jenis_kelamin"`
+	TanggalLahir      *time.Time `json:"tanggal_lahir,omitempty"`
+	KelompokUmur      string     `json:"kelompok_umur"` // 0-5, 6-11, 12-23, 24-59, 60+ bulan
+	IdIbu             *int       `json:"id_ibu,omitempty"`
+	StatusGizi        *string    `json:"status_gizi"`
+	KunjunganTerakhir *time.Time `json:"kunjungan_terakhir"`
+}
+
 // --- Struct untuk JWT Claims ---
 type AuthClaims struct {
 	KaderID int `json:"kader_id"`
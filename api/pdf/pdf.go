@@ -0,0 +1,149 @@
+// Package pdf menulis dokumen PDF satu halaman sederhana (teks Helvetica, garis dan kotak)
+// untuk dokumen cetak seperti sertifikat. Koordinat dalam point (1/72 inci) dengan titik
+// asal di kiri atas halaman.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Ukuran kertas A4 dalam point
+const (
+	A4Pendek  = 595.28
+	A4Panjang = 841.89
+)
+
+// Dokumen adalah satu halaman PDF yang sedang disusun
+type Dokumen struct {
+	Lebar, Tinggi float64
+	isi           bytes.Buffer
+}
+
+// Baru membuat dokumen kosong dengan ukuran halaman tertentu
+func Baru(lebar, tinggi float64) *Dokumen {
+	return &Dokumen{Lebar: lebar, Tinggi: tinggi}
+}
+
+// angka memformat bilangan tanpa nol berlebih
+func angka(v float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", v), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Warna mengatur warna garis dan isi (komponen 0-1)
+func (d *Dokumen) Warna(r, g, b float64) {
+	fmt.Fprintf(&d.isi, "%s %s %s RG %s %s %s rg\n", angka(r), angka(g), angka(b), angka(r), angka(g), angka(b))
+}
+
+// Garis menggambar garis lurus
+func (d *Dokumen) Garis(x1, y1, x2, y2, tebal float64) {
+	fmt.Fprintf(&d.isi, "%s w %s %s m %s %s l S\n", angka(tebal), angka(x1), angka(d.Tinggi-y1), angka(x2), angka(d.Tinggi-y2))
+}
+
+// Kotak menggambar tepi persegi panjang dengan sudut kiri atas (x, y)
+func (d *Dokumen) Kotak(x, y, lebar, tinggi, tebal float64) {
+	fmt.Fprintf(&d.isi, "%s w %s %s %s %s re S\n", angka(tebal), angka(x), angka(d.Tinggi-y-tinggi), angka(lebar), angka(tinggi))
+}
+
+// Teks menulis teks dengan garis dasar pada (x, y)
+func (d *Dokumen) Teks(x, y, ukuran float64, tebal bool, s string) {
+	font := "F1"
+	if tebal {
+		font = "F2"
+	}
+	fmt.Fprintf(&d.isi, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, angka(ukuran), angka(x), angka(d.Tinggi-y), enkode(s))
+}
+
+// TeksTengah menulis teks rata tengah halaman
+func (d *Dokumen) TeksTengah(y, ukuran float64, tebal bool, s string) {
+	d.Teks((d.Lebar-LebarTeks(s, ukuran, tebal))/2, y, ukuran, tebal, s)
+}
+
+// TeksKanan menulis teks yang berakhir pada x
+func (d *Dokumen) TeksKanan(x, y, ukuran float64, tebal bool, s string) {
+	d.Teks(x-LebarTeks(s, ukuran, tebal), y, ukuran, tebal, s)
+}
+
+// LebarTeks mengembalikan lebar teks dalam point
+func LebarTeks(s string, ukuran float64, tebal bool) float64 {
+	tabel := &lebarHelvetica
+	if tebal {
+		tabel = &lebarHelveticaTebal
+	}
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += tabel[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * ukuran / 1000
+}
+
+// enkode mengubah teks ke WinAnsiEncoding dan meloloskan karakter khusus string PDF
+func enkode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r <= 126:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Bytes menyusun berkas PDF lengkap
+func (d *Dokumen) Bytes() []byte {
+	var out bytes.Buffer
+	var offset []int
+	objek := func(isi string) {
+		offset = append(offset, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offset), isi)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	objek("<< /Type /Catalog /Pages 2 0 R >>")
+	objek("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	objek(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		angka(d.Lebar), angka(d.Tinggi)))
+	objek("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objek("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	objek(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", d.isi.Len(), d.isi.String()))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offset)+1)
+	for _, o := range offset {
+		fmt.Fprintf(&out, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offset)+1, xref)
+	return out.Bytes()
+}
+
+// Lebar huruf (per 1000 satuan) karakter ASCII 32-126 dari metrik standar Adobe
+var lebarHelvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var lebarHelveticaTebal = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
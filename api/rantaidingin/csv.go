@@ -0,0 +1,113 @@
+package rantaidingin
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatWaktu adalah format waktu yang umum dipakai data logger suhu, dicoba berurutan
+var formatWaktu = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+}
+
+// parseWaktu membaca waktu bacaan; waktu tanpa zona dianggap dalam zona loc
+func parseWaktu(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, f := range formatWaktu {
+		if t, err := time.ParseInLocation(f, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("format waktu tidak dikenal: %q", s)
+}
+
+// parseSuhu membaca suhu dengan desimal titik atau koma, dengan atau tanpa satuan
+func parseSuhu(s string) (float64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(s), "C"), "°"))
+	return strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+}
+
+// kolomHeader mencari indeks kolom waktu dan suhu dari baris judul. ok bernilai false bila baris
+// tersebut bukan judul.
+func kolomHeader(baris []string) (kolomWaktu, kolomSuhu int, ok bool) {
+	kolomWaktu, kolomSuhu = -1, -1
+	for i, judul := range baris {
+		judul = strings.ToLower(judul)
+		switch {
+		case kolomWaktu < 0 && (strings.Contains(judul, "waktu") || strings.Contains(judul, "time") || strings.Contains(judul, "tanggal") || strings.Contains(judul, "date")):
+			kolomWaktu = i
+		case kolomSuhu < 0 && (strings.Contains(judul, "suhu") || strings.Contains(judul, "temp")):
+			kolomSuhu = i
+		}
+	}
+	return kolomWaktu, kolomSuhu, kolomWaktu >= 0 && kolomSuhu >= 0
+}
+
+// BacaCSV membaca ekspor data logger berisi kolom waktu dan suhu. Pemisah koma atau titik koma
+// dikenali dari baris pertama. Bila ada baris judul, kolom dicari dari judulnya (waktu/time/tanggal/date
+// dan suhu/temp); tanpa judul, kolom pertama adalah waktu dan kolom kedua suhu. Baris yang tidak dapat
+// dibaca dikembalikan sebagai galat per baris tanpa menggagalkan baris lainnya.
+func BacaCSV(r io.Reader, loc *time.Location) ([]Bacaan, []string, error) {
+	br := bufio.NewReader(r)
+	awal, _ := br.Peek(4096)
+	barisPertama, _, _ := strings.Cut(string(awal), "\n")
+
+	cr := csv.NewReader(br)
+	if strings.Count(barisPertama, ";") > strings.Count(barisPertama, ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var daftar []Bacaan
+	var galat []string
+	kolomWaktu, kolomSuhu := 0, 1
+	for nomor := 1; ; nomor++ {
+		baris, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if nomor == 1 {
+			if w, s, ok := kolomHeader(baris); ok {
+				kolomWaktu, kolomSuhu = w, s
+				continue
+			}
+		}
+		if len(baris) == 1 && strings.TrimSpace(baris[0]) == "" {
+			continue
+		}
+		if len(baris) <= max(kolomWaktu, kolomSuhu) {
+			galat = append(galat, fmt.Sprintf("Baris %d: kolom waktu atau suhu tidak ada.", nomor))
+			continue
+		}
+		waktu, err := parseWaktu(strings.TrimSpace(baris[kolomWaktu]), loc)
+		if err != nil {
+			galat = append(galat, fmt.Sprintf("Baris %d: %v.", nomor, err))
+			continue
+		}
+		suhu, err := parseSuhu(baris[kolomSuhu])
+		if err != nil {
+			galat = append(galat, fmt.Sprintf("Baris %d: suhu tidak valid: %q.", nomor, baris[kolomSuhu]))
+			continue
+		}
+		daftar = append(daftar, Bacaan{Waktu: waktu, Suhu: suhu})
+	}
+	return daftar, galat, nil
+}
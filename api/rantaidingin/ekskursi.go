@@ -0,0 +1,83 @@
+// Package rantaidingin mendeteksi ekskursi suhu pada alat penyimpanan vaksin (lemari es, cold box,
+// vaccine carrier) dari bacaan suhu manual maupun data logger.
+package rantaidingin
+
+import (
+	"sort"
+	"time"
+)
+
+// Jenis ekskursi
+const (
+	Panas = "panas" // Di atas suhu maksimal alat
+	Beku  = "beku"  // Di bawah suhu minimal alat
+)
+
+// Rentang suhu bawaan penyimpanan vaksin (derajat Celsius)
+const (
+	SuhuMinBawaan = 2.0
+	SuhuMaxBawaan = 8.0
+)
+
+// Bacaan adalah satu pencatatan suhu alat
+type Bacaan struct {
+	Waktu time.Time
+	Suhu  float64
+}
+
+// Ekskursi adalah rangkaian bacaan berturut-turut di luar rentang suhu alat dengan jenis yang sama
+type Ekskursi struct {
+	Jenis        string
+	Mulai        time.Time  // Bacaan pertama di luar rentang
+	Selesai      *time.Time // Bacaan pertama sesudahnya yang kembali normal atau berganti jenis; nil bila masih berlangsung
+	SuhuEkstrem  float64    // Suhu tertinggi (panas) atau terendah (beku)
+	JumlahBacaan int
+}
+
+// jenisBacaan mengembalikan jenis ekskursi sebuah bacaan, atau "" bila dalam rentang
+func jenisBacaan(suhu, min, max float64) string {
+	switch {
+	case suhu > max:
+		return Panas
+	case suhu < min:
+		return Beku
+	default:
+		return ""
+	}
+}
+
+// Deteksi mengelompokkan bacaan di luar rentang [min, max] menjadi ekskursi, urut waktu mulai.
+// Urutan bacaan masukan tidak harus kronologis.
+func Deteksi(bacaan []Bacaan, min, max float64) []Ekskursi {
+	urut := make([]Bacaan, len(bacaan))
+	copy(urut, bacaan)
+	sort.SliceStable(urut, func(i, j int) bool { return urut[i].Waktu.Before(urut[j].Waktu) })
+
+	var daftar []Ekskursi
+	var aktif *Ekskursi
+	for _, b := range urut {
+		jenis := jenisBacaan(b.Suhu, min, max)
+		if aktif != nil && jenis != aktif.Jenis {
+			waktu := b.Waktu
+			aktif.Selesai = &waktu
+			aktif = nil
+		}
+		if jenis == "" {
+			continue
+		}
+		if aktif == nil {
+			daftar = append(daftar, Ekskursi{Jenis: jenis, Mulai: b.Waktu, SuhuEkstrem: b.Suhu})
+			aktif = &daftar[len(daftar)-1]
+		}
+		aktif.JumlahBacaan++
+		if (jenis == Panas && b.Suhu > aktif.SuhuEkstrem) || (jenis == Beku && b.Suhu < aktif.SuhuEkstrem) {
+			aktif.SuhuEkstrem = b.Suhu
+		}
+	}
+	return daftar
+}
+
+// Tumpang menilai apakah dua rentang waktu ekskursi beririsan; selesai nil berarti masih berlangsung
+func Tumpang(mulaiA time.Time, selesaiA *time.Time, mulaiB time.Time, selesaiB *time.Time) bool {
+	return (selesaiA == nil || !selesaiA.Before(mulaiB)) && (selesaiB == nil || !selesaiB.Before(mulaiA))
+}
@@ -0,0 +1,108 @@
+package rantaidingin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeteksi(t *testing.T) {
+	awal := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	jam := func(n int) time.Time { return awal.Add(time.Duration(n) * time.Hour) }
+
+	// ekskursiIngin mencatat Selesai dalam jam sejak awal, -1 bila masih berlangsung
+	type ekskursiIngin struct {
+		jenis        string
+		mulai        int
+		selesai      int
+		suhuEkstrem  float64
+		jumlahBacaan int
+	}
+	kasus := []struct {
+		nama   string
+		bacaan []Bacaan
+		ingin  []ekskursiIngin
+	}{
+		{
+			nama:   "semua dalam rentang termasuk batasnya",
+			bacaan: []Bacaan{{jam(0), 2}, {jam(1), 5}, {jam(2), 8}},
+		},
+		{
+			nama:   "panas lalu kembali normal",
+			bacaan: []Bacaan{{jam(0), 5}, {jam(1), 8.5}, {jam(2), 11.2}, {jam(3), 9}, {jam(4), 6}},
+			ingin:  []ekskursiIngin{{Panas, 1, 4, 11.2, 3}},
+		},
+		{
+			nama:   "beku masih berlangsung",
+			bacaan: []Bacaan{{jam(0), 4}, {jam(1), 1.5}, {jam(2), -0.5}},
+			ingin:  []ekskursiIngin{{Beku, 1, -1, -0.5, 2}},
+		},
+		{
+			nama:   "panas langsung berganti beku",
+			bacaan: []Bacaan{{jam(0), 9}, {jam(1), 1}, {jam(2), 0.5}, {jam(3), 3}},
+			ingin: []ekskursiIngin{
+				{Panas, 0, 1, 9, 1},
+				{Beku, 1, 3, 0.5, 2},
+			},
+		},
+		{
+			nama:   "bacaan tidak urut waktu",
+			bacaan: []Bacaan{{jam(3), 4}, {jam(1), 10}, {jam(0), 5}, {jam(2), 12}, {jam(5), 9.5}},
+			ingin: []ekskursiIngin{
+				{Panas, 1, 3, 12, 2},
+				{Panas, 5, -1, 9.5, 1},
+			},
+		},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			hasil := Deteksi(k.bacaan, SuhuMinBawaan, SuhuMaxBawaan)
+			if len(hasil) != len(k.ingin) {
+				t.Fatalf("jumlah ekskursi = %d, ingin %d (%+v)", len(hasil), len(k.ingin), hasil)
+			}
+			for i, e := range hasil {
+				ingin := k.ingin[i]
+				if e.Jenis != ingin.jenis || !e.Mulai.Equal(jam(ingin.mulai)) || e.SuhuEkstrem != ingin.suhuEkstrem || e.JumlahBacaan != ingin.jumlahBacaan {
+					t.Errorf("ekskursi %d = %+v, ingin %+v", i, e, ingin)
+				}
+				switch {
+				case ingin.selesai < 0 && e.Selesai != nil:
+					t.Errorf("ekskursi %d selesai %s, ingin masih berlangsung", i, e.Selesai)
+				case ingin.selesai >= 0 && (e.Selesai == nil || !e.Selesai.Equal(jam(ingin.selesai))):
+					t.Errorf("ekskursi %d selesai %v, ingin %s", i, e.Selesai, jam(ingin.selesai))
+				}
+			}
+		})
+	}
+}
+
+func TestTumpang(t *testing.T) {
+	awal := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	jam := func(n int) *time.Time {
+		w := awal.Add(time.Duration(n) * time.Hour)
+		return &w
+	}
+	kasus := []struct {
+		nama     string
+		mulaiA   time.Time
+		selesaiA *time.Time
+		mulaiB   time.Time
+		selesaiB *time.Time
+		ingin    bool
+	}{
+		{"beririsan", *jam(0), jam(3), *jam(2), jam(5), true},
+		{"bersentuhan di ujung", *jam(0), jam(2), *jam(2), jam(4), true},
+		{"terpisah", *jam(0), jam(1), *jam(2), jam(4), false},
+		{"A masih berlangsung", *jam(0), nil, *jam(10), jam(12), true},
+		{"B masih berlangsung dimulai sesudah A selesai", *jam(0), jam(1), *jam(2), nil, false},
+	}
+	for _, k := range kasus {
+		t.Run(k.nama, func(t *testing.T) {
+			if got := Tumpang(k.mulaiA, k.selesaiA, k.mulaiB, k.selesaiB); got != k.ingin {
+				t.Errorf("Tumpang = %v, ingin %v", got, k.ingin)
+			}
+			if got := Tumpang(k.mulaiB, k.selesaiB, k.mulaiA, k.selesaiA); got != k.ingin {
+				t.Errorf("Tumpang (dibalik) = %v, ingin %v", got, k.ingin)
+			}
+		})
+	}
+}